@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxBatchSize is the largest number of blobs a batch request may carry
+const maxBatchSize = 1000
+
+// StoreBlobs signs the blobs concurrently and stores them in a single batch, returning
+// a result per blob in the order of the request. A blob that fails validation or signing
+// is reported in its result; if the batch then fails to store, every remaining blob reports it.
+func (s *Service) StoreBlobs(ctx context.Context, req *blobv1.StoreBlobsRequest) (*blobv1.StoreBlobsResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if len(req.Blobs) == 0 {
+		return nil, errors.New("blobs cannot be empty")
+	}
+	if len(req.Blobs) > maxBatchSize {
+		return nil, fmt.Errorf("a batch cannot have more than %d blobs", maxBatchSize)
+	}
+
+	signed := make([]*blobv1.SignedBlobRecord, len(req.Blobs))
+	errs := make([]error, len(req.Blobs))
+
+	// signing dominates the cost of a blob, one at a time per CPU. With Merkle batching the
+	// workers mostly wait for their batch to be signed, they all join the same batches instead.
+	var g errgroup.Group
+	if s.batcher == nil {
+		g.SetLimit(runtime.GOMAXPROCS(0))
+	}
+	for i, blob := range req.Blobs {
+		g.Go(func() error {
+			payloadToBeSigned, err := s.newBlobRecord(blob)
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+			signed[i], errs[i] = s.signRecord(ctx, payloadToBeSigned)
+			return nil
+		})
+	}
+	_ = g.Wait() // the workers report through errs
+
+	records := make([]*blobv1.SignedBlobRecord, 0, len(signed))
+	for i, record := range signed {
+		if errs[i] == nil {
+			records = append(records, record)
+		}
+	}
+
+	if len(records) > 0 {
+		if err := s.store.StoreBatch(ctx, records); err != nil {
+			s.logger.Error("failed to store batch of signed records", "error", err, "records", len(records))
+			err = fmt.Errorf("failed to store signed record: %w", err)
+			for i := range errs {
+				if errs[i] == nil {
+					errs[i] = err
+				}
+			}
+		}
+	}
+
+	response := &blobv1.StoreBlobsResponse{Results: make([]*blobv1.StoreBlobResult, len(req.Blobs))}
+	for i, record := range signed {
+		if errs[i] != nil {
+			response.Results[i] = &blobv1.StoreBlobResult{Error: errs[i].Error()}
+			continue
+		}
+		if record.Payload.ExpiresAt != "" {
+			blobsStoredWithTTLTotal.Inc()
+		}
+		response.Results[i] = &blobv1.StoreBlobResult{Uuid: record.Payload.Uuid}
+	}
+
+	return response, nil
+}
+
+// GetSignedBlobs retrieves the signed blobs concurrently, returning a result per UUID
+// in the order of the request
+func (s *Service) GetSignedBlobs(ctx context.Context, req *blobv1.GetSignedBlobsRequest) (*blobv1.GetSignedBlobsResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if len(req.Uuids) == 0 {
+		return nil, errors.New("uuids cannot be empty")
+	}
+	if len(req.Uuids) > maxBatchSize {
+		return nil, fmt.Errorf("a batch cannot have more than %d blobs", maxBatchSize)
+	}
+
+	response := &blobv1.GetSignedBlobsResponse{Results: make([]*blobv1.GetSignedBlobResult, len(req.Uuids))}
+
+	var g errgroup.Group
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, id := range req.Uuids {
+		g.Go(func() error {
+			blob, err := s.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: id, Format: req.Format})
+			if err != nil {
+				response.Results[i] = &blobv1.GetSignedBlobResult{Error: err.Error()}
+				return nil
+			}
+			response.Results[i] = &blobv1.GetSignedBlobResult{Blob: blob}
+			return nil
+		})
+	}
+	_ = g.Wait() // the workers report through the results
+
+	return response, nil
+}
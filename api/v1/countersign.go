@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/prit342/signed-blob-service/verifier"
+)
+
+// WithCountersignerKeys registers the public keys of the parties allowed to countersign records.
+// A party authenticates by signing the stored payload with the private key of a registered key.
+func WithCountersignerKeys(publicKeys []crypto.PublicKey) Option {
+	return func(s *Service) {
+		if s.countersignerKeys == nil {
+			s.countersignerKeys = make(map[string]crypto.PublicKey, len(publicKeys))
+		}
+		for _, publicKey := range publicKeys {
+			s.countersignerKeys[signature.KeyIDForPublicKey(publicKey)] = publicKey
+		}
+	}
+}
+
+// AddCountersignature verifies and stores the signature of a registered countersigner over a stored record
+func (s *Service) AddCountersignature(
+	ctx context.Context,
+	req *blobv1.AddCountersignatureRequest,
+) (*blobv1.AddCountersignatureResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	blobUUID, err := uuid.Parse(req.Uuid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	if len(req.Signature) == 0 {
+		return nil, errors.New("signature cannot be empty")
+	}
+
+	publicKey, ok := s.countersignerKeys[req.KeyId]
+	if !ok {
+		s.logger.Warn("countersignature from an unregistered key", "key_id", req.KeyId, "uuid", req.Uuid)
+		return nil, fmt.Errorf("key %q is not a registered countersigner", req.KeyId)
+	}
+
+	blobRow, err := s.store.GetByUUID(ctx, blobUUID)
+	if err != nil {
+		if errors.Is(err, store.ErrBlobNotFound) {
+			return nil, fmt.Errorf("blob not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to retrieve blob: %w", err)
+	}
+
+	// the countersignature covers the same bytes as the server signature
+	serialisedPayload, err := verifier.MarshalRecord(blobRow.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if err := signature.VerifyWithPublicKey(publicKey, serialisedPayload, req.Signature); err != nil {
+		s.logger.Warn("invalid countersignature", "error", err, "key_id", req.KeyId, "uuid", req.Uuid)
+		return nil, fmt.Errorf("countersignature verification failed: %w", err)
+	}
+
+	countersignature := &blobv1.Countersignature{
+		KeyId:     req.KeyId,
+		Signature: req.Signature,
+		Timestamp: time.Now().UTC().Format(timestampFormat),
+	}
+
+	if err := s.store.AddCountersignature(ctx, blobUUID, countersignature); err != nil {
+		if errors.Is(err, store.ErrCountersignatureExists) {
+			return nil, fmt.Errorf("key %q has already countersigned the blob: %w", req.KeyId, err)
+		}
+		return nil, fmt.Errorf("failed to store countersignature: %w", err)
+	}
+
+	s.logger.Info("countersignature added", "key_id", req.KeyId, "uuid", req.Uuid)
+
+	return &blobv1.AddCountersignatureResponse{
+		Countersignature: countersignature,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prit342/signed-blob-service/cose"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/jose"
+	"github.com/prit342/signed-blob-service/verifier"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// encodeJWS signs the JSON encoding of the record and returns it as a JWS compact serialisation.
+// JOSE libraries can not verify the Protobuf signature, so the record is signed again
+// with the same key inside the JWS envelope.
+func (s *Service) encodeJWS(record *blobv1.BlobRecord) (string, error) {
+	payload, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal record to JSON: %w", err)
+	}
+
+	return jose.SignCompact(s.signer, payload)
+}
+
+// encodeCOSESign1 signs the Protobuf encoding of the record and returns it as a COSE_Sign1 message.
+// The payload is the same encoding the service signs, the envelope only adds the COSE signature.
+func (s *Service) encodeCOSESign1(record *blobv1.BlobRecord) ([]byte, error) {
+	payload, err := verifier.MarshalRecord(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	return cose.Sign1(s.signer, payload)
+}
+
+// encodeJWKS returns the JWK Set document containing the signer's public key.
+func (s *Service) encodeJWKS() (string, error) {
+	set, err := jose.PublicJWKSet(s.signer)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(set)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWK Set: %w", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/store"
+)
+
+// maxLegalHoldFieldLength is the longest reason or requester accepted in the audit log
+const maxLegalHoldFieldLength = 1024
+
+// SetLegalHold places or releases a legal hold on a blob and records the change in the audit log
+func (s *Service) SetLegalHold(ctx context.Context, req *blobv1.SetLegalHoldRequest) (*blobv1.SetLegalHoldResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	uuid, err := uuid.Parse(req.Uuid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	// the audit log must say who changed the hold and why
+	if req.Reason == "" {
+		return nil, errors.New("reason cannot be empty")
+	}
+	if req.RequestedBy == "" {
+		return nil, errors.New("requested_by cannot be empty")
+	}
+	if len(req.Reason) > maxLegalHoldFieldLength || len(req.RequestedBy) > maxLegalHoldFieldLength {
+		return nil, fmt.Errorf("reason and requested_by cannot exceed %d bytes", maxLegalHoldFieldLength)
+	}
+
+	event := &blobv1.LegalHoldEvent{
+		Uuid:        uuid.String(),
+		Held:        req.Held,
+		Reason:      req.Reason,
+		RequestedBy: req.RequestedBy,
+		Timestamp:   time.Now().UTC().Format(timestampFormat),
+	}
+
+	if err := s.store.SetLegalHold(ctx, uuid, event); err != nil {
+		if errors.Is(err, store.ErrBlobNotFound) {
+			return nil, fmt.Errorf("blob not found: %w", err)
+		}
+		s.logger.Error("failed to set legal hold", "error", err, "uuid", req.Uuid)
+		return nil, fmt.Errorf("failed to set legal hold: %w", err)
+	}
+
+	s.logger.Info("legal hold changed", "uuid", req.Uuid, "held", req.Held,
+		"reason", req.Reason, "requested_by", req.RequestedBy)
+
+	return &blobv1.SetLegalHoldResponse{
+		Event: event,
+	}, nil
+}
+
+// GetLegalHoldHistory returns the legal hold audit log of a blob, oldest first
+func (s *Service) GetLegalHoldHistory(ctx context.Context, req *blobv1.GetLegalHoldHistoryRequest) (*blobv1.GetLegalHoldHistoryResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	uuid, err := uuid.Parse(req.Uuid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	events, err := s.store.GetLegalHoldEvents(ctx, uuid)
+	if err != nil {
+		s.logger.Error("failed to retrieve legal hold events", "error", err, "uuid", req.Uuid)
+		return nil, fmt.Errorf("failed to retrieve legal hold events: %w", err)
+	}
+
+	return &blobv1.GetLegalHoldHistoryResponse{
+		Events: events,
+	}, nil
+}
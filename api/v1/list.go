@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/store"
+)
+
+// page sizes of ListBlobs
+const (
+	defaultListPageSize = 100
+	maxListPageSize     = 1000
+)
+
+// ListBlobs returns the metadata of the blobs signed within a time range, oldest first, a page at a time
+func (s *Service) ListBlobs(ctx context.Context, req *blobv1.ListBlobsRequest) (*blobv1.ListBlobsResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, errors.New("page_size cannot be negative")
+	case pageSize == 0:
+		pageSize = defaultListPageSize
+	case pageSize > maxListPageSize:
+		return nil, fmt.Errorf("page_size cannot exceed %d", maxListPageSize)
+	}
+
+	query := store.ListQuery{Limit: pageSize + 1} // one more tells whether there is a next page
+	if req.StartTime != nil {
+		if err := req.StartTime.CheckValid(); err != nil {
+			return nil, fmt.Errorf("invalid start_time: %w", err)
+		}
+		query.CreatedFrom = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		if err := req.EndTime.CheckValid(); err != nil {
+			return nil, fmt.Errorf("invalid end_time: %w", err)
+		}
+		query.CreatedTo = req.EndTime.AsTime()
+	}
+	if !query.CreatedFrom.IsZero() && !query.CreatedTo.IsZero() && !query.CreatedFrom.Before(query.CreatedTo) {
+		return nil, errors.New("start_time must be before end_time")
+	}
+
+	if err := validateLabels(req.Labels); err != nil {
+		return nil, fmt.Errorf("invalid label filter: %w", err)
+	}
+	query.Labels = req.Labels
+
+	if req.PageToken != "" {
+		cursor, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		query.After = cursor
+	}
+
+	blobs, err := s.store.ListBlobs(ctx, query)
+	if err != nil {
+		s.logger.Error("failed to list blobs", "error", err)
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	response := &blobv1.ListBlobsResponse{Blobs: blobs}
+	if len(blobs) > pageSize {
+		response.Blobs = blobs[:pageSize]
+		last := response.Blobs[pageSize-1]
+		if response.NextPageToken, err = encodePageToken(last); err != nil {
+			s.logger.Error("failed to encode page token", "error", err, "uuid", last.Uuid)
+			return nil, fmt.Errorf("failed to encode page token: %w", err)
+		}
+	}
+
+	return response, nil
+}
+
+// encodePageToken returns the token of the page starting after the blob,
+// its signed timestamp and UUID, the position of the blob in the listing order
+func encodePageToken(last *blobv1.BlobMetadata) (string, error) {
+	if _, err := time.Parse(time.RFC3339Nano, last.Timestamp); err != nil {
+		return "", fmt.Errorf("invalid timestamp %q: %w", last.Timestamp, err)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(last.Timestamp + " " + last.Uuid)), nil
+}
+
+// decodePageToken returns the listing position encoded in a page token
+func decodePageToken(token string) (*store.ListCursor, error) {
+	invalid := errors.New("invalid page_token")
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	timestamp, id, ok := strings.Cut(string(decoded), " ")
+	if !ok {
+		return nil, invalid
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, invalid
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, invalid
+	}
+
+	return &store.ListCursor{CreatedAt: createdAt.UTC(), UUID: id}, nil
+}
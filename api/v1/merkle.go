@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/merkle"
+	"github.com/prit342/signed-blob-service/verifier"
+	"google.golang.org/grpc/status"
+)
+
+// WithMerkleBatching signs records in Merkle batches: the records arriving within window of the
+// first one, at most maxLeaves of them, become the leaves of a Merkle tree and only its root is
+// signed. Each record keeps the signature and its inclusion proof, so it is still verified on its
+// own, while the signer performs one private key operation per batch instead of one per record.
+func WithMerkleBatching(window time.Duration, maxLeaves int) Option {
+	return func(s *Service) {
+		s.batcher = &merkleBatcher{window: window, maxLeaves: maxLeaves}
+	}
+}
+
+// merkleBatcher collects the records to sign into batches. The first record of a batch waits for
+// the window to pass, or the batch to fill up, then signs the root of the batch for all of them.
+type merkleBatcher struct {
+	window    time.Duration
+	maxLeaves int
+
+	mu      sync.Mutex
+	pending *merkleBatch // batch accepting records, nil until the next record arrives
+}
+
+// merkleBatch is a batch of records signed together
+type merkleBatch struct {
+	leaves [][]byte      // leaf hashes of the records, in arrival order
+	full   chan struct{} // closed once the batch holds maxLeaves records
+	done   chan struct{} // closed once the batch is signed, or failed to
+
+	signature []byte
+	proofs    [][][]byte
+	err       error
+}
+
+// checkMerkleBatching validates the batching parameters when Merkle batching is enabled
+func (s *Service) checkMerkleBatching() error {
+	if s.batcher == nil {
+		return nil
+	}
+	if s.batcher.window <= 0 {
+		return errors.New("window must be positive")
+	}
+	if s.batcher.maxLeaves <= 0 {
+		return errors.New("maximum number of leaves must be positive")
+	}
+	return nil
+}
+
+// signInBatch adds the serialised record to the pending batch and returns the signature of
+// the batch root and the inclusion proof of the record once the batch is signed.
+// The first record of a batch signs it for all the others, so its caller waits for the window
+// whatever happens to its context. The others return as soon as their context is done,
+// their record stays a leaf of the batch, which is signed without them.
+func (s *Service) signInBatch(ctx context.Context, serialisedPayload []byte) ([]byte, *blobv1.InclusionProof, error) {
+	b := s.batcher
+
+	b.mu.Lock()
+	batch := b.pending
+	first := batch == nil
+	if first {
+		batch = &merkleBatch{full: make(chan struct{}), done: make(chan struct{})}
+		b.pending = batch
+	}
+	index := len(batch.leaves)
+	batch.leaves = append(batch.leaves, merkle.LeafHash(serialisedPayload))
+	if len(batch.leaves) == b.maxLeaves {
+		b.pending = nil // the next record starts a new batch
+		close(batch.full)
+	}
+	b.mu.Unlock()
+
+	// the first record signs for the whole batch, whatever happens to its own request
+	if first {
+		timer := time.NewTimer(b.window)
+		select {
+		case <-timer.C:
+		case <-batch.full:
+			timer.Stop()
+		}
+
+		b.mu.Lock()
+		if b.pending == batch {
+			b.pending = nil
+		}
+		b.mu.Unlock()
+
+		s.signBatch(batch)
+	}
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if batch.err != nil {
+		return nil, nil, batch.err
+	}
+	return batch.signature, &blobv1.InclusionProof{
+		LeafIndex: int64(index),
+		TreeSize:  int64(len(batch.leaves)),
+		Hashes:    batch.proofs[index],
+	}, nil
+}
+
+// signBatch builds the tree of a closed batch and signs its root
+func (s *Service) signBatch(batch *merkleBatch) {
+	defer close(batch.done)
+
+	root, proofs, err := merkle.Build(batch.leaves)
+	if err != nil {
+		batch.err = fmt.Errorf("failed to build Merkle tree: %w", err)
+		return
+	}
+
+	treeHead, err := verifier.MarshalTreeHead(&blobv1.MerkleTreeHead{RootHash: root, TreeSize: int64(len(batch.leaves))})
+	if err != nil {
+		s.logger.Error("failed to marshal Merkle tree head", "error", err)
+		batch.err = fmt.Errorf("failed to marshal Merkle tree head: %w", err)
+		return
+	}
+
+	signature, err := s.signer.Sign(treeHead)
+	if err != nil {
+		s.logger.Error("failed to sign Merkle tree head", "error", err, "leaves", len(batch.leaves))
+		batch.err = signingError(err)
+		return
+	}
+
+	merkleBatchLeaves.Observe(float64(len(batch.leaves)))
+	batch.signature, batch.proofs = signature, proofs
+}
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metricsNamespace prefixes every metric exported by the service
+const metricsNamespace = "signed_blob_service"
+
+// Retention metrics, registered with the default Prometheus registry
+var (
+	blobsStoredWithTTLTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "blobs_stored_with_ttl_total",
+		Help:      "Number of blobs stored with an expiry time.",
+	})
+	blobsExpiredTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "blobs_expired_total",
+		Help:      "Number of expired blobs deleted and replaced by a signed tombstone.",
+	})
+	reaperErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "reaper_errors_total",
+		Help:      "Number of reaper runs that failed.",
+	})
+	reaperRunDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "reaper_run_duration_seconds",
+		Help:      "Duration of successful reaper runs.",
+		Buckets:   prometheus.DefBuckets,
+	})
+	reaperLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "reaper_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful reaper run.",
+	})
+)
+
+// Signing metrics, registered with the default Prometheus registry
+var (
+	merkleBatchLeaves = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "merkle_batch_leaves",
+		Help:      "Number of records signed together in a Merkle batch.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+)
@@ -0,0 +1,224 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/store"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// WithRetentionPolicy bounds the time to live of blobs.
+// defaultTTL applies when a request sets none, zero keeps such blobs forever.
+// maxTTL is the longest time to live a request may ask for, zero means unbounded.
+// With a maximum but no default, blobs without a time to live get the maximum.
+func WithRetentionPolicy(defaultTTL, maxTTL time.Duration) Option {
+	return func(s *Service) {
+		s.defaultTTL = defaultTTL
+		s.maxTTL = maxTTL
+	}
+}
+
+// checkRetentionPolicy validates the retention policy and fills in the default time to live
+func (s *Service) checkRetentionPolicy() error {
+	if s.defaultTTL < 0 || s.maxTTL < 0 {
+		return errors.New("time to live cannot be negative")
+	}
+	if s.maxTTL > 0 {
+		if s.defaultTTL > s.maxTTL {
+			return fmt.Errorf("default time to live %s exceeds the maximum of %s", s.defaultTTL, s.maxTTL)
+		}
+		if s.defaultTTL == 0 {
+			s.defaultTTL = s.maxTTL
+		}
+	}
+	return nil
+}
+
+// expiresAt returns the signed expiry time of a record created at now with the requested
+// time to live, or "" when it is kept forever
+func (s *Service) expiresAt(ttl *durationpb.Duration, now time.Time) (string, error) {
+	requested := s.defaultTTL
+	if ttl != nil {
+		if err := ttl.CheckValid(); err != nil {
+			return "", fmt.Errorf("invalid ttl: %w", err)
+		}
+		requested = ttl.AsDuration()
+		if requested <= 0 {
+			return "", errors.New("ttl must be positive")
+		}
+	}
+
+	if s.maxTTL > 0 && requested > s.maxTTL {
+		return "", fmt.Errorf("ttl exceeds the maximum of %s allowed by the retention policy", s.maxTTL)
+	}
+	if requested == 0 {
+		return "", nil
+	}
+
+	return now.Add(requested).UTC().Format(timestampFormat), nil
+}
+
+// ReapExpired deletes up to batchSize expired blobs, leaving a signed tombstone for each,
+// and returns how many were deleted
+func (s *Service) ReapExpired(ctx context.Context, batchSize int) (int, error) {
+	deleted, _, err := s.reapExpired(ctx, batchSize)
+	return deleted, err
+}
+
+// reapExpired deletes the first limit expired blobs and returns how many were deleted and
+// how many were skipped for an invalid UUID, which are listed again by every later call
+func (s *Service) reapExpired(ctx context.Context, limit int) (deleted, malformed int, err error) {
+	now := time.Now().UTC()
+	expired, err := s.store.ListExpired(ctx, now, limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list expired blobs: %w", err)
+	}
+
+	for _, metadata := range expired {
+		blobUUID, err := uuid.Parse(metadata.Uuid)
+		if err != nil {
+			// a malformed row must not stop the reaper from deleting the others
+			s.logger.Error("skipping expired blob with an invalid UUID", "error", err, "uuid", metadata.Uuid)
+			malformed++
+			continue
+		}
+
+		tombstone, err := s.signTombstone(&blobv1.Tombstone{
+			Uuid:      metadata.Uuid,
+			Hash:      metadata.Hash,
+			Timestamp: metadata.Timestamp,
+			ExpiresAt: metadata.ExpiresAt,
+			DeletedAt: now.Format(timestampFormat),
+		})
+		if err != nil {
+			return deleted, malformed, err
+		}
+
+		if err := s.store.Expire(ctx, blobUUID, tombstone); err != nil {
+			// another replica got there first, or a legal hold was placed since the blob was listed
+			if errors.Is(err, store.ErrBlobNotFound) || errors.Is(err, store.ErrLegalHold) {
+				continue
+			}
+			return deleted, malformed, fmt.Errorf("failed to expire blob %s: %w", metadata.Uuid, err)
+		}
+
+		deleted++
+		blobsExpiredTotal.Inc()
+		s.logger.Info("deleted expired blob", "uuid", metadata.Uuid, "expires_at", metadata.ExpiresAt)
+	}
+
+	return deleted, malformed, nil
+}
+
+// signTombstone signs the serialised tombstone like signAndStore signs a record
+func (s *Service) signTombstone(tombstone *blobv1.Tombstone) (*blobv1.SignedTombstone, error) {
+	serialisedPayload, err := proto.Marshal(tombstone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+
+	sig, err := s.signer.Sign(serialisedPayload)
+	if err != nil {
+		s.logger.Error("failed to sign tombstone", "error", err, "uuid", tombstone.Uuid)
+		return nil, fmt.Errorf("failed to sign tombstone: %w", err)
+	}
+
+	return &blobv1.SignedTombstone{
+		Payload:   tombstone,
+		Signature: sig,
+		KeyId:     s.signer.KeyID(),
+	}, nil
+}
+
+// RunReaper deletes expired blobs every interval, in batches of batchSize, until the context is cancelled.
+// A failed run is logged and retried at the next interval.
+func (s *Service) RunReaper(ctx context.Context, interval time.Duration, batchSize int) error {
+	if interval <= 0 {
+		return errors.New("reaper interval must be positive")
+	}
+	if batchSize <= 0 {
+		return errors.New("reaper batch size must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.reap(ctx, batchSize)
+		}
+	}
+}
+
+// reap deletes batches of expired blobs until a batch comes back short. Rows with an invalid
+// UUID stay listed, so each batch is listed past the ones seen last and they cannot stall the reaper.
+func (s *Service) reap(ctx context.Context, batchSize int) {
+	start := time.Now()
+	total, malformed := 0, 0
+	for {
+		limit := batchSize + malformed
+		deleted, skipped, err := s.reapExpired(ctx, limit)
+		total += deleted
+		malformed = skipped
+		if err != nil {
+			// cancellation on shutdown is not a failure
+			if ctx.Err() == nil {
+				reaperErrorsTotal.Inc()
+				s.logger.Error("failed to delete expired blobs", "error", err, "deleted", total)
+			}
+			return
+		}
+		if deleted+skipped < limit {
+			break
+		}
+	}
+
+	reaperRunDuration.Observe(time.Since(start).Seconds())
+	reaperLastSuccess.SetToCurrentTime()
+	if total > 0 {
+		s.logger.Info("reaper run completed", "deleted", total, "duration", time.Since(start))
+	}
+}
+
+// GetTombstone returns the signed tombstone of an expired and deleted blob
+func (s *Service) GetTombstone(ctx context.Context, req *blobv1.GetTombstoneRequest) (*blobv1.GetTombstoneResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	uuid, err := uuid.Parse(req.Uuid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	tombstone, err := s.store.GetTombstone(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, store.ErrTombstoneNotFound) {
+			return nil, fmt.Errorf("no tombstone for blob: %w", err)
+		}
+		s.logger.Error("failed to retrieve tombstone", "error", err, "uuid", req.Uuid)
+		return nil, fmt.Errorf("failed to retrieve tombstone: %w", err)
+	}
+
+	return &blobv1.GetTombstoneResponse{
+		Tombstone: tombstone,
+	}, nil
+}
+
+// notFoundError explains that a missing blob expired when it left a tombstone behind
+func (s *Service) notFoundError(ctx context.Context, uuid uuid.UUID, err error) error {
+	if tombstone, tombstoneErr := s.store.GetTombstone(ctx, uuid); tombstoneErr == nil {
+		return fmt.Errorf("blob expired at %s and was deleted: %w",
+			tombstone.Payload.ExpiresAt, err)
+	}
+	return fmt.Errorf("blob not found: %w", err)
+}
@@ -2,17 +2,26 @@ package v1
 
 import (
 	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"log/slog"
+	"mime"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prit342/signed-blob-service/cose"
 	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
 	"github.com/prit342/signed-blob-service/signature"
 	"github.com/prit342/signed-blob-service/store"
-	"google.golang.org/protobuf/proto"
+	"github.com/prit342/signed-blob-service/verifier"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Sever represents the main server structure
@@ -21,13 +30,46 @@ type Service struct {
 	logger                                *slog.Logger
 	store                                 store.Storage
 	signer                                signature.Signer
+	publicKey                             crypto.PublicKey            // public key of the signer, verifying its signatures
+	certificateChain                      []*x509.Certificate         // optional chain certifying the signing key
+	countersignerKeys                     map[string]crypto.PublicKey // registered countersigning keys by key ID
+	defaultTTL                            time.Duration               // time to live of blobs stored without one, zero keeps them forever
+	maxTTL                                time.Duration               // longest time to live a request may ask for, zero means unbounded
+	batcher                               *merkleBatcher              // signs records in Merkle batches when set
+}
+
+// Option configures optional features of the Service
+type Option func(*Service)
+
+// WithCertificateChain sets the X.509 certificate chain (leaf first) certifying the signing key
+func WithCertificateChain(chain []*x509.Certificate) Option {
+	return func(s *Service) {
+		s.certificateChain = chain
+	}
 }
 
 // we only allow blobs of size 256 Kilobytes
 const maxBlobSize = 256 * 1024 // 256KB in bytes
 
+// timestampFormat is the format of every time the service signs or records, RFC 3339 in UTC with
+// microseconds so records signed within the same second are still ordered
+const timestampFormat = "2006-01-02T15:04:05.000000Z"
+
+// limits on the labels of a record
+const (
+	maxLabels           = 64
+	maxLabelKeyLength   = 63
+	maxLabelValueLength = 255
+)
+
+// maxFilenameLength is the longest filename accepted, the common file system limit
+const maxFilenameLength = 255
+
+// maxContentTypeLength is the longest media type accepted
+const maxContentTypeLength = 255
+
 // NewServer creates a new instance of Sever with the provided dependencies
-func NewService(logger *slog.Logger, storage store.Storage, signer signature.Signer) (*Service, error) {
+func NewService(logger *slog.Logger, storage store.Storage, signer signature.Signer, opts ...Option) (*Service, error) {
 	if logger == nil {
 		return nil, errors.New("logger cannot be nil")
 	}
@@ -37,15 +79,60 @@ func NewService(logger *slog.Logger, storage store.Storage, signer signature.Sig
 	if signer == nil {
 		return nil, errors.New("signer cannot be nil")
 	}
-	return &Service{
-		logger: logger,
-		store:  storage,
-		signer: signer,
-	}, nil
+	publicKeyPEM, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key: %w", err)
+	}
+	publicKey, err := signature.ParsePublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{
+		logger:    logger,
+		store:     storage,
+		signer:    signer,
+		publicKey: publicKey,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.checkRetentionPolicy(); err != nil {
+		return nil, fmt.Errorf("invalid retention policy: %w", err)
+	}
+
+	if err := s.checkMerkleBatching(); err != nil {
+		return nil, fmt.Errorf("invalid Merkle batching: %w", err)
+	}
+
+	// a chain for a different key would make every client verification fail
+	if len(s.certificateChain) > 0 {
+		if err := signature.CheckCertificateMatchesSigner(s.certificateChain, signer); err != nil {
+			return nil, fmt.Errorf("invalid certificate chain: %w", err)
+		}
+	}
+
+	return s, nil
 }
 
 // StoreBlob stores a blob and its signature and returns its UUID
 func (s *Service) StoreBlob(ctx context.Context, req *blobv1.StoreBlobRequest) (*blobv1.StoreBlobResponse, error) {
+	payloadToBeSigned, err := s.newBlobRecord(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.signAndStore(ctx, payloadToBeSigned); err != nil {
+		return nil, err
+	}
+
+	return &blobv1.StoreBlobResponse{
+		Uuid: payloadToBeSigned.Uuid,
+	}, nil
+}
+
+// newBlobRecord validates a request to store a blob and builds the record to sign for it
+func (s *Service) newBlobRecord(req *blobv1.StoreBlobRequest) (*blobv1.BlobRecord, error) {
 	if req == nil {
 		return nil, errors.New("request cannot be nil")
 	}
@@ -69,20 +156,156 @@ func (s *Service) StoreBlob(ctx context.Context, req *blobv1.StoreBlobRequest) (
 	// This is necessary because the storage expects a string representation of the hash
 	encodedHashStr := hex.EncodeToString(hash[:])
 
+	if err := validateLabels(req.Labels); err != nil {
+		return nil, err
+	}
+	if err := validateFilename(req.Filename); err != nil {
+		return nil, err
+	}
+	if err := validateContentType(req.ContentType); err != nil {
+		return nil, err
+	}
+
 	uuidStr := uuid.New().String() // the uuid for the blob
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	now := time.Now().UTC()
+	timestamp := now.Format(timestampFormat)
+
+	expiresAt, err := s.expiresAt(req.Ttl, now)
+	if err != nil {
+		return nil, err
+	}
 
 	// this is the payload we will sign
+	return &blobv1.BlobRecord{
+		Uuid:        uuidStr,
+		Blob:        req.Blob,
+		Hash:        encodedHashStr,
+		Timestamp:   timestamp,
+		Filename:    req.Filename,
+		ExpiresAt:   expiresAt,
+		Labels:      req.Labels,
+		ContentType: req.ContentType,
+	}, nil
+}
+
+// SignDigest signs and records a detached BlobRecord for content that is not uploaded
+func (s *Service) SignDigest(ctx context.Context, req *blobv1.SignDigestRequest) (*blobv1.SignDigestResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	// the digest is stored exactly like the hash the service computes for uploaded blobs
+	digest := strings.ToLower(req.Sha256Digest)
+	if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+		return nil, errors.New("sha256_digest must be a hex-encoded SHA-256 digest")
+	}
+
+	if req.Size < 0 {
+		return nil, errors.New("size cannot be negative")
+	}
+
+	if err := validateFilename(req.Filename); err != nil {
+		return nil, err
+	}
+
+	uuidStr := uuid.New().String() // the uuid for the detached record
+	now := time.Now().UTC()
+	timestamp := now.Format(timestampFormat)
+
+	expiresAt, err := s.expiresAt(req.Ttl, now)
+	if err != nil {
+		return nil, err
+	}
+
 	payloadToBeSigned := &blobv1.BlobRecord{
 		Uuid:      uuidStr,
-		Blob:      req.Blob,
-		Hash:      encodedHashStr,
+		Hash:      digest,
 		Timestamp: timestamp,
+		Detached:  true,
+		Filename:  req.Filename,
+		Size:      req.Size,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.signAndStore(ctx, payloadToBeSigned); err != nil {
+		return nil, err
+	}
+
+	return &blobv1.SignDigestResponse{
+		Uuid: uuidStr,
+	}, nil
+}
+
+// validateFilename checks an optional filename is a base name, a path would leak the client's directory layout
+func validateFilename(filename string) error {
+	if len(filename) > maxFilenameLength {
+		return fmt.Errorf("filename exceeds maximum length of %d bytes", maxFilenameLength)
+	}
+	if filename != "" && (filename != filepath.Base(filename) || filename == "." || filename == "..") {
+		return errors.New("filename must not contain a directory")
+	}
+	return nil
+}
+
+// validateContentType checks an optional content type is a media type such as "text/plain; charset=utf-8"
+func validateContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	if len(contentType) > maxContentTypeLength {
+		return fmt.Errorf("content_type exceeds maximum length of %d bytes", maxContentTypeLength)
+	}
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return fmt.Errorf("invalid content_type: %w", err)
+	}
+	return nil
+}
+
+// validateLabels checks the number and size of the labels and the characters of their keys
+func validateLabels(labels map[string]string) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("a blob cannot have more than %d labels", maxLabels)
+	}
+	for key, value := range labels {
+		if key == "" || len(key) > maxLabelKeyLength {
+			return fmt.Errorf("label key %q must be between 1 and %d bytes", key, maxLabelKeyLength)
+		}
+		for _, r := range key {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("._-/", r)) {
+				return fmt.Errorf("label key %q may only contain letters, digits, '.', '_', '-' and '/'", key)
+			}
+		}
+		if len(value) > maxLabelValueLength {
+			return fmt.Errorf("value of label %q exceeds maximum length of %d bytes", key, maxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// signAndStore signs the serialised payload and stores it along with its signature
+func (s *Service) signAndStore(ctx context.Context, payloadToBeSigned *blobv1.BlobRecord) error {
+	recordWithSignature, err := s.signRecord(ctx, payloadToBeSigned)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Store(ctx, recordWithSignature); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to store signed recored: %v", err))
+		return fmt.Errorf("failed to store signed record: %w", err)
+	}
+
+	if payloadToBeSigned.ExpiresAt != "" {
+		blobsStoredWithTTLTotal.Inc()
 	}
 
+	return nil
+}
+
+// signRecord signs the serialised payload and returns it along with its signature
+func (s *Service) signRecord(ctx context.Context, payloadToBeSigned *blobv1.BlobRecord) (*blobv1.SignedBlobRecord, error) {
 	// we need to marshal the payload to bytes before signing
 	// this is because the signer expects a byte slice to sign
-	serialisedPayload, err := proto.Marshal(payloadToBeSigned)
+	serialisedPayload, err := verifier.MarshalRecord(payloadToBeSigned)
 	s.logger.Debug("[SIGN] Marshaled payload bytes", "bytes",
 		fmt.Sprintf("%x", serialisedPayload))
 
@@ -93,34 +316,39 @@ func (s *Service) StoreBlob(ctx context.Context, req *blobv1.StoreBlobRequest) (
 	// instead of signing just the content, we sign the entire request
 	// this ensures that the signature is valid for the entire request structure
 
+	if s.batcher != nil {
+		sig, proof, err := s.signInBatch(ctx, serialisedPayload)
+		if err != nil {
+			return nil, err
+		}
+		return &blobv1.SignedBlobRecord{
+			Payload:        payloadToBeSigned,
+			Signature:      sig,
+			KeyId:          s.signer.KeyID(),
+			InclusionProof: proof,
+		}, nil
+	}
+
 	sig, err := s.signer.Sign(serialisedPayload)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("failed to sign the payload: %v", err))
-		return nil, fmt.Errorf("failed to sign payload: %w", err)
+		return nil, signingError(err)
 	}
 
-	// Create a new Blob instance to store
-	recordWithSignature := &blobv1.SignedBlobRecord{
-		Payload: &blobv1.BlobRecord{
-			Uuid:      uuidStr,
-			Blob:      req.Blob,
-			Hash:      encodedHashStr,
-			Timestamp: timestamp,
-		},
+	return &blobv1.SignedBlobRecord{
+		Payload:   payloadToBeSigned,
 		Signature: sig,
-	}
-
-	// fmt.Printf("\n%+v\n", recordBlob)
-	//s.logger.Debug("recording blob", "blob", fmt.Sprintf("%x", recordWithSignature))
+		KeyId:     s.signer.KeyID(),
+	}, nil
+}
 
-	if err := s.store.Store(ctx, recordWithSignature); err != nil {
-		s.logger.Error(fmt.Sprintf("failed to store signed recored: %v", err))
-		return nil, fmt.Errorf("failed to store signed record: %w", err)
+// signingError wraps a signing failure. A saturated signer pool is reported as ResourceExhausted,
+// the request can be retried once the queued signatures are done.
+func signingError(err error) error {
+	if errors.Is(err, signature.ErrSignerSaturated) {
+		return status.Errorf(codes.ResourceExhausted, "failed to sign payload: %v", err)
 	}
-
-	return &blobv1.StoreBlobResponse{
-		Uuid: uuidStr,
-	}, nil
+	return fmt.Errorf("failed to sign payload: %w", err)
 }
 
 // GetSignedBlob retrieves a signed blob by its UUID
@@ -143,7 +371,7 @@ func (s *Service) GetSignedBlob(ctx context.Context, req *blobv1.GetSignedBlobRe
 	blobRow, err := s.store.GetByUUID(ctx, uuid)
 	if err != nil {
 		if errors.Is(err, store.ErrBlobNotFound) {
-			return nil, fmt.Errorf("blob not found: %w", err)
+			return nil, s.notFoundError(ctx, uuid, err)
 		}
 		s.logger.Error(fmt.Sprintf("failed to retrieve blob: %v", err))
 		return nil, fmt.Errorf("failed to retrieve blob: %w", err)
@@ -154,21 +382,89 @@ func (s *Service) GetSignedBlob(ctx context.Context, req *blobv1.GetSignedBlobRe
 		return nil, errors.New("signature is empty")
 	}
 
+	countersignatures, err := s.store.GetCountersignatures(ctx, uuid)
+	if err != nil {
+		s.logger.Error("failed to retrieve countersignatures", "error", err, "uuid", req.Uuid)
+		return nil, fmt.Errorf("failed to retrieve countersignatures: %w", err)
+	}
+
+	// the payload is returned exactly as it was signed, including the detached fields
 	response := &blobv1.GetSignedBlobResponse{
-		Payload: &blobv1.BlobRecord{
-			Uuid:      blobRow.Payload.Uuid,
-			Hash:      blobRow.Payload.Hash,
-			Blob:      blobRow.Payload.Blob,
-			Timestamp: blobRow.Payload.Timestamp,
-		},
-		Signature: signature,
+		Payload:           blobRow.Payload,
+		Signature:         signature,
+		Countersignatures: countersignatures,
+		InclusionProof:    blobRow.InclusionProof,
+	}
+
+	switch req.GetFormat() {
+	case blobv1.SignedBlobFormat_SIGNED_BLOB_FORMAT_JWS:
+		jws, err := s.encodeJWS(response.Payload)
+		if err != nil {
+			s.logger.Error("failed to encode record as JWS", "error", err, "uuid", req.Uuid)
+			return nil, signingError(fmt.Errorf("failed to encode record as JWS: %w", err))
+		}
+		response.Jws = jws
+	case blobv1.SignedBlobFormat_SIGNED_BLOB_FORMAT_COSE_SIGN1:
+		msg, err := s.encodeCOSESign1(response.Payload)
+		if err != nil {
+			s.logger.Error("failed to encode record as COSE_Sign1", "error", err, "uuid", req.Uuid)
+			return nil, signingError(fmt.Errorf("failed to encode record as COSE_Sign1: %w", err))
+		}
+		response.CoseSign1 = msg
 	}
 
 	return response, nil
 }
 
+// BlobExists reports whether a blob with the UUID is stored
+func (s *Service) BlobExists(ctx context.Context, req *blobv1.BlobExistsRequest) (*blobv1.BlobExistsResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	uuid, err := uuid.Parse(req.Uuid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	exists, err := s.store.Exists(ctx, uuid)
+	if err != nil {
+		s.logger.Error("failed to check if blob exists", "error", err, "uuid", req.Uuid)
+		return nil, fmt.Errorf("failed to check if blob exists: %w", err)
+	}
+
+	return &blobv1.BlobExistsResponse{
+		Exists: exists,
+	}, nil
+}
+
+// GetBlobMetadata returns the metadata of a blob without its content
+func (s *Service) GetBlobMetadata(ctx context.Context, req *blobv1.GetBlobMetadataRequest) (*blobv1.GetBlobMetadataResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	uuid, err := uuid.Parse(req.Uuid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID format: %w", err)
+	}
+
+	metadata, err := s.store.GetMetadata(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, store.ErrBlobNotFound) {
+			return nil, s.notFoundError(ctx, uuid, err)
+		}
+		s.logger.Error("failed to retrieve blob metadata", "error", err, "uuid", req.Uuid)
+		return nil, fmt.Errorf("failed to retrieve blob metadata: %w", err)
+	}
+
+	return &blobv1.GetBlobMetadataResponse{
+		Metadata: metadata,
+	}, nil
+}
+
 // GetPublicKey returns the public key used for signing blobs
-func (s *Service) GetPublicKey(context.Context, *blobv1.GetPublicKeyRequest) (*blobv1.GetPublicKeyResponse, error) {
+func (s *Service) GetPublicKey(_ context.Context, req *blobv1.GetPublicKeyRequest) (*blobv1.GetPublicKeyResponse, error) {
 	if s.signer == nil {
 		return nil, errors.New("signer is not initialized")
 	}
@@ -177,8 +473,39 @@ func (s *Service) GetPublicKey(context.Context, *blobv1.GetPublicKeyRequest) (*b
 		s.logger.Error("failed to retrieve public key", "error", err)
 		return nil, fmt.Errorf("failed to retrieve public key: %w", err)
 	}
-	return &blobv1.GetPublicKeyResponse{
+	response := &blobv1.GetPublicKeyResponse{
 		PublicKey: string(publicKey),
-	}, nil
+		KeyId:     s.signer.KeyID(),
+	}
 
+	switch req.GetFormat() {
+	case blobv1.PublicKeyFormat_PUBLIC_KEY_FORMAT_JWKS:
+		jwks, err := s.encodeJWKS()
+		if err != nil {
+			s.logger.Error("failed to encode public key as JWKS", "error", err)
+			return nil, fmt.Errorf("failed to encode public key as JWKS: %w", err)
+		}
+		response.Jwks = jwks
+	case blobv1.PublicKeyFormat_PUBLIC_KEY_FORMAT_COSE_KEY:
+		key, err := cose.SignerKey(s.signer)
+		if err != nil {
+			s.logger.Error("failed to encode public key as COSE_Key", "error", err)
+			return nil, fmt.Errorf("failed to encode public key as COSE_Key: %w", err)
+		}
+		response.CoseKey = key
+	}
+
+	return response, nil
+}
+
+// GetCertificateChain returns the X.509 certificate chain certifying the signing key
+func (s *Service) GetCertificateChain(context.Context, *blobv1.GetCertificateChainRequest) (*blobv1.GetCertificateChainResponse, error) {
+	if len(s.certificateChain) == 0 {
+		return nil, errors.New("no certificate chain is configured for the signing key")
+	}
+
+	return &blobv1.GetCertificateChainResponse{
+		CertificateChain: string(signature.EncodeCertificateChain(s.certificateChain)),
+		KeyId:            s.signer.KeyID(),
+	}, nil
 }
@@ -0,0 +1,1106 @@
+package v1
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/merkle"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/signature/signaturetest"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/prit342/signed-blob-service/verifier"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTestService creates a service backed by the in-memory storage and a fresh Ed25519 signer.
+func newTestService(t *testing.T, opts ...Option) (*Service, signature.Signer) {
+	t.Helper()
+	signer := signaturetest.NewSignerFor(t, signature.AlgorithmEd25519)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service, err := NewService(log, store.NewMemoryStorage(), signer, opts...)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	return service, signer
+}
+
+// verifyResponse checks the server signature over the returned payload,
+// or over the tree head its inclusion proof leads to.
+func verifyResponse(t *testing.T, signer signature.Signer, resp *blobv1.GetSignedBlobResponse) {
+	t.Helper()
+	payload, err := verifier.MarshalRecord(resp.Payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	if proof := resp.InclusionProof; proof != nil {
+		root, err := merkle.RootFromInclusionProof(proof.LeafIndex, proof.TreeSize, merkle.LeafHash(payload), proof.Hashes)
+		if err != nil {
+			t.Fatalf("failed to compute root from inclusion proof: %v", err)
+		}
+		if payload, err = verifier.MarshalTreeHead(&blobv1.MerkleTreeHead{RootHash: root, TreeSize: proof.TreeSize}); err != nil {
+			t.Fatalf("failed to marshal tree head: %v", err)
+		}
+	}
+	if err := signer.VerifySignature(payload, resp.Signature); err != nil {
+		t.Fatalf("failed to verify signature: %v", err)
+	}
+}
+
+func TestStoreAndGetSignedBlob(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	storeResp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "hello world"})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+
+	getResp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: storeResp.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get blob: %v", err)
+	}
+	if getResp.Payload.Blob != "hello world" {
+		t.Fatalf("unexpected blob %q", getResp.Payload.Blob)
+	}
+	digest := sha256.Sum256([]byte("hello world"))
+	if getResp.Payload.Hash != hex.EncodeToString(digest[:]) {
+		t.Fatalf("unexpected hash %q", getResp.Payload.Hash)
+	}
+	verifyResponse(t, signer, getResp)
+
+	_, err = service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: "550e8400-e29b-41d4-a716-446655440000"})
+	if !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+
+	if _, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: strings.Repeat("A", maxBlobSize+1)}); err == nil {
+		t.Fatal("expected error for a blob larger than the limit")
+	}
+}
+
+func TestStoreBlobFilenameAndContentType(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	tests := []struct {
+		name        string
+		filename    string
+		contentType string
+		expectError bool
+	}{
+		{name: "none"},
+		{name: "filename and content type", filename: "config.yaml", contentType: "application/yaml"},
+		{name: "content type with parameters", contentType: "text/plain; charset=utf-8"},
+		{name: "path in filename", filename: "dir/config.yaml", expectError: true},
+		{name: "parent directory", filename: "..", expectError: true},
+		{name: "long filename", filename: strings.Repeat("a", maxFilenameLength+1), expectError: true},
+		{name: "invalid content type", contentType: "not a media type", expectError: true},
+		{name: "long content type", contentType: "text/" + strings.Repeat("a", maxContentTypeLength), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{
+				Blob: "key: value", Filename: tt.filename, ContentType: tt.contentType,
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// both are part of the signed payload
+			getResp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: resp.Uuid})
+			if err != nil {
+				t.Fatalf("failed to get blob: %v", err)
+			}
+			if getResp.Payload.Filename != tt.filename || getResp.Payload.ContentType != tt.contentType {
+				t.Fatalf("unexpected filename %q and content type %q", getResp.Payload.Filename, getResp.Payload.ContentType)
+			}
+			verifyResponse(t, signer, getResp)
+		})
+	}
+}
+
+func TestStoreBlobsAndGetSignedBlobs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	blobs := []*blobv1.StoreBlobRequest{
+		{Blob: "first", Filename: "first.txt"},
+		{Blob: ""}, // invalid, the others are still stored
+		{Blob: "third", Labels: map[string]string{"batch": "1"}},
+		{Blob: "fourth", Filename: "dir/fourth.txt"},
+	}
+	storeResp, err := service.StoreBlobs(ctx, &blobv1.StoreBlobsRequest{Blobs: blobs})
+	if err != nil {
+		t.Fatalf("failed to store blobs: %v", err)
+	}
+	if len(storeResp.Results) != len(blobs) {
+		t.Fatalf("expected %d results but got %d", len(blobs), len(storeResp.Results))
+	}
+	for i, failed := range []bool{false, true, false, true} {
+		result := storeResp.Results[i]
+		if failed != (result.Error != "") || failed != (result.Uuid == "") {
+			t.Fatalf("unexpected result %d: %v", i, result)
+		}
+	}
+
+	uuids := []string{storeResp.Results[0].Uuid, "550e8400-e29b-41d4-a716-446655440000", storeResp.Results[2].Uuid, "not a uuid"}
+	getResp, err := service.GetSignedBlobs(ctx, &blobv1.GetSignedBlobsRequest{
+		Uuids: uuids, Format: blobv1.SignedBlobFormat_SIGNED_BLOB_FORMAT_JWS,
+	})
+	if err != nil {
+		t.Fatalf("failed to get blobs: %v", err)
+	}
+	if len(getResp.Results) != len(uuids) {
+		t.Fatalf("expected %d results but got %d", len(uuids), len(getResp.Results))
+	}
+	for i, want := range []*blobv1.StoreBlobRequest{blobs[0], nil, blobs[2], nil} {
+		result := getResp.Results[i]
+		if want == nil {
+			if result.Error == "" || result.Blob != nil {
+				t.Fatalf("expected result %d to fail: %v", i, result)
+			}
+			continue
+		}
+		if result.Error != "" {
+			t.Fatalf("failed to get blob %d: %s", i, result.Error)
+		}
+		payload := result.Blob.Payload
+		if payload.Uuid != uuids[i] || payload.Blob != want.Blob || payload.Filename != want.Filename ||
+			!maps.Equal(payload.Labels, want.Labels) || result.Blob.Jws == "" {
+			t.Fatalf("unexpected result %d: %v", i, result)
+		}
+		verifyResponse(t, signer, result.Blob)
+	}
+
+	if _, err := service.StoreBlobs(ctx, &blobv1.StoreBlobsRequest{}); err == nil {
+		t.Fatal("expected error for an empty batch")
+	}
+	if _, err := service.StoreBlobs(ctx, &blobv1.StoreBlobsRequest{Blobs: make([]*blobv1.StoreBlobRequest, maxBatchSize+1)}); err == nil {
+		t.Fatal("expected error for a batch larger than the limit")
+	}
+	if _, err := service.GetSignedBlobs(ctx, &blobv1.GetSignedBlobsRequest{Uuids: make([]string, maxBatchSize+1)}); err == nil {
+		t.Fatal("expected error for a batch larger than the limit")
+	}
+}
+
+func TestMerkleBatching(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t, WithMerkleBatching(200*time.Millisecond, 4))
+
+	// ten records arriving together fill two batches and start a third
+	uuids := make([]string, 10)
+	errs := make(chan error, len(uuids))
+	for i := range uuids {
+		go func() {
+			resp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: fmt.Sprintf("blob %d", i)})
+			if err == nil {
+				uuids[i] = resp.Uuid
+			}
+			errs <- err
+		}()
+	}
+	for range uuids {
+		if err := <-errs; err != nil {
+			t.Fatalf("failed to store blob: %v", err)
+		}
+	}
+
+	detached, err := service.SignDigest(ctx, &blobv1.SignDigestRequest{Sha256Digest: strings.Repeat("ab", sha256.Size)})
+	if err != nil {
+		t.Fatalf("failed to sign digest: %v", err)
+	}
+
+	leaves := map[string]int64{} // leaves per signature, the records of a batch share it
+	for _, id := range append(uuids, detached.Uuid) {
+		resp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: id})
+		if err != nil {
+			t.Fatalf("failed to get blob: %v", err)
+		}
+		if resp.InclusionProof == nil {
+			t.Fatalf("expected an inclusion proof for %s", id)
+		}
+		verifyResponse(t, signer, resp)
+		leaves[string(resp.Signature)]++
+		if size := resp.InclusionProof.TreeSize; size < 1 || size > 4 {
+			t.Fatalf("unexpected tree size %d", size)
+		}
+	}
+	if len(leaves) >= len(uuids)+1 {
+		t.Fatalf("expected records to share batches but got %d signatures for %d records", len(leaves), len(uuids)+1)
+	}
+
+	// the signature covers the tree head, tampering with the record breaks the proof
+	resp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: uuids[0]})
+	if err != nil {
+		t.Fatalf("failed to get blob: %v", err)
+	}
+	resp.Payload.Blob = "tampered"
+	payload, err := verifier.MarshalRecord(resp.Payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	root, err := merkle.RootFromInclusionProof(resp.InclusionProof.LeafIndex, resp.InclusionProof.TreeSize,
+		merkle.LeafHash(payload), resp.InclusionProof.Hashes)
+	if err != nil {
+		t.Fatalf("failed to compute root from inclusion proof: %v", err)
+	}
+	treeHead, err := verifier.MarshalTreeHead(&blobv1.MerkleTreeHead{RootHash: root, TreeSize: resp.InclusionProof.TreeSize})
+	if err != nil {
+		t.Fatalf("failed to marshal tree head: %v", err)
+	}
+	if err := signer.VerifySignature(treeHead, resp.Signature); err == nil {
+		t.Fatal("expected the signature not to cover a tampered record")
+	}
+
+	for _, opt := range []Option{WithMerkleBatching(0, 4), WithMerkleBatching(time.Millisecond, 0)} {
+		if _, err := NewService(service.logger, store.NewMemoryStorage(), signer, opt); err == nil {
+			t.Fatal("expected error for invalid Merkle batching parameters")
+		}
+	}
+}
+
+func TestMerkleBatchingCancelled(t *testing.T) {
+	t.Parallel()
+	service, signer := newTestService(t, WithMerkleBatching(time.Second, 4))
+
+	// the first record signs the batch, whatever the other callers do
+	first := make(chan error, 1)
+	go func() {
+		resp, err := service.StoreBlob(context.Background(), &blobv1.StoreBlobRequest{Blob: "first"})
+		if err == nil {
+			var get *blobv1.GetSignedBlobResponse
+			if get, err = service.GetSignedBlob(context.Background(), &blobv1.GetSignedBlobRequest{Uuid: resp.Uuid}); err == nil {
+				verifyResponse(t, signer, get)
+			}
+		}
+		first <- err
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		service.batcher.mu.Lock()
+		pending := service.batcher.pending != nil
+		service.batcher.mu.Unlock()
+		if pending {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the batch to start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// a cancelled caller joining the batch returns without waiting for the window
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	_, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "cancelled"})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("expected Canceled but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("cancelled caller waited %s for the batch", elapsed)
+	}
+
+	if err := <-first; err != nil {
+		t.Fatalf("failed to store the first blob: %v", err)
+	}
+}
+
+// saturatedSigner rejects every signature like a signer pool with a full queue
+type saturatedSigner struct {
+	signature.Signer
+}
+
+func (saturatedSigner) Sign([]byte) ([]byte, error) {
+	return nil, signature.ErrSignerSaturated
+}
+
+// failingSigner fails every signature
+type failingSigner struct {
+	signature.Signer
+}
+
+func (failingSigner) Sign([]byte) ([]byte, error) {
+	return nil, errors.New("signing key unavailable")
+}
+
+func TestSignerSaturated(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+	service.signer = saturatedSigner{signer}
+
+	_, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "hello world"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted but got %v", err)
+	}
+
+	// other failures keep their error
+	service.signer = failingSigner{signer}
+	_, err = service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "hello world"})
+	if err == nil || status.Code(err) == codes.ResourceExhausted {
+		t.Fatalf("expected a plain signing error but got %v", err)
+	}
+}
+
+func TestSignDigest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	digest := sha256.Sum256([]byte("content"))
+	validDigest := hex.EncodeToString(digest[:])
+
+	tests := []struct {
+		name        string
+		req         *blobv1.SignDigestRequest
+		expectError bool
+	}{
+		{name: "valid", req: &blobv1.SignDigestRequest{Sha256Digest: validDigest, Filename: "a.tar.gz", Size: 7}},
+		{name: "upper case digest", req: &blobv1.SignDigestRequest{Sha256Digest: strings.ToUpper(validDigest)}},
+		{name: "nil request", req: nil, expectError: true},
+		{name: "short digest", req: &blobv1.SignDigestRequest{Sha256Digest: validDigest[:62]}, expectError: true},
+		{name: "not hex", req: &blobv1.SignDigestRequest{Sha256Digest: strings.Repeat("z", 64)}, expectError: true},
+		{name: "negative size", req: &blobv1.SignDigestRequest{Sha256Digest: validDigest, Size: -1}, expectError: true},
+		{name: "path in filename", req: &blobv1.SignDigestRequest{Sha256Digest: validDigest, Filename: "dir/a"}, expectError: true},
+		{name: "long filename", req: &blobv1.SignDigestRequest{
+			Sha256Digest: validDigest, Filename: strings.Repeat("a", maxFilenameLength+1)}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp, err := service.SignDigest(ctx, tt.req)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			getResp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: resp.Uuid})
+			if err != nil {
+				t.Fatalf("failed to get record: %v", err)
+			}
+			if !getResp.Payload.Detached || getResp.Payload.Blob != "" || getResp.Payload.Hash != validDigest {
+				t.Fatalf("unexpected detached record: %v", getResp.Payload)
+			}
+			verifyResponse(t, signer, getResp)
+		})
+	}
+}
+
+func TestAddCountersignature(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	countersignerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+	keyID := signature.KeyIDForPublicKey(&countersignerKey.PublicKey)
+	service, _ := newTestService(t, WithCountersignerKeys([]crypto.PublicKey{&countersignerKey.PublicKey}))
+
+	storeResp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "hello world"})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+	getResp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: storeResp.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get blob: %v", err)
+	}
+	payload, err := verifier.MarshalRecord(getResp.Payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	digest := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, countersignerKey, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		req         *blobv1.AddCountersignatureRequest
+		expectError bool
+		expectIs    error // storage error the call must wrap, if any
+	}{
+		{name: "unregistered key", req: &blobv1.AddCountersignatureRequest{
+			Uuid: storeResp.Uuid, KeyId: "unknown", Signature: sig}, expectError: true},
+		{name: "invalid signature", req: &blobv1.AddCountersignatureRequest{
+			Uuid: storeResp.Uuid, KeyId: keyID, Signature: getResp.Signature}, expectError: true},
+		{name: "unknown record", req: &blobv1.AddCountersignatureRequest{
+			Uuid: "550e8400-e29b-41d4-a716-446655440000", KeyId: keyID, Signature: sig},
+			expectIs: store.ErrBlobNotFound},
+		{name: "valid", req: &blobv1.AddCountersignatureRequest{
+			Uuid: storeResp.Uuid, KeyId: keyID, Signature: sig}},
+		{name: "duplicate", req: &blobv1.AddCountersignatureRequest{
+			Uuid: storeResp.Uuid, KeyId: keyID, Signature: sig}, expectIs: store.ErrCountersignatureExists},
+	}
+
+	// the cases depend on each other, so they run in order
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AddCountersignature(ctx, tt.req)
+			switch {
+			case tt.expectIs != nil:
+				if !errors.Is(err, tt.expectIs) {
+					t.Fatalf("expected %v but got %v", tt.expectIs, err)
+				}
+			case tt.expectError:
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	getResp, err = service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: storeResp.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get blob: %v", err)
+	}
+	if len(getResp.Countersignatures) != 1 || getResp.Countersignatures[0].KeyId != keyID {
+		t.Fatalf("unexpected countersignatures: %v", getResp.Countersignatures)
+	}
+}
+
+func TestVerifyBlob(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	countersignerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+	service, signer := newTestService(t, WithCountersignerKeys([]crypto.PublicKey{&countersignerKey.PublicKey}))
+	batched, batchSigner := newTestService(t, WithMerkleBatching(time.Millisecond, 16))
+
+	// a stored record, a detached one and one signed in a Merkle batch
+	get := func(service *Service, uuid string) *blobv1.GetSignedBlobResponse {
+		t.Helper()
+		resp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: uuid})
+		if err != nil {
+			t.Fatalf("failed to get blob: %v", err)
+		}
+		return resp
+	}
+	stored, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "hello world", Labels: map[string]string{"owner": "team-a"}})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+	record := get(service, stored.Uuid)
+	digest := sha256.Sum256([]byte("release"))
+	detached, err := service.SignDigest(ctx, &blobv1.SignDigestRequest{Sha256Digest: hex.EncodeToString(digest[:]), Size: 7})
+	if err != nil {
+		t.Fatalf("failed to sign digest: %v", err)
+	}
+	detachedRecord := get(service, detached.Uuid)
+	unsized, err := service.SignDigest(ctx, &blobv1.SignDigestRequest{Sha256Digest: hex.EncodeToString(digest[:])})
+	if err != nil {
+		t.Fatalf("failed to sign digest: %v", err)
+	}
+	wrongSize, err := service.SignDigest(ctx, &blobv1.SignDigestRequest{Sha256Digest: hex.EncodeToString(digest[:]), Size: 3})
+	if err != nil {
+		t.Fatalf("failed to sign digest: %v", err)
+	}
+	batchedStored, err := batched.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "batched"})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+	batchedRecord := get(batched, batchedStored.Uuid)
+
+	payload, err := verifier.MarshalRecord(record.Payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	payloadDigest := sha256.Sum256(payload)
+	countersignature, err := ecdsa.SignASN1(rand.Reader, countersignerKey, payloadDigest[:])
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+
+	// tampered copies of the records
+	withBlob := func(blob string) *blobv1.BlobRecord {
+		payload := proto.Clone(record.Payload).(*blobv1.BlobRecord)
+		payload.Blob = blob
+		return payload
+	}
+	withLabel := proto.Clone(record.Payload).(*blobv1.BlobRecord)
+	withLabel.Labels["owner"] = "team-b"
+	otherLeaf := proto.Clone(batchedRecord.InclusionProof).(*blobv1.InclusionProof)
+	otherLeaf.TreeSize, otherLeaf.Hashes = 2, [][]byte{make([]byte, sha256.Size)}
+	outsideTree := proto.Clone(batchedRecord.InclusionProof).(*blobv1.InclusionProof)
+	outsideTree.LeafIndex = outsideTree.TreeSize
+
+	tests := []struct {
+		name        string
+		service     *Service
+		req         *blobv1.VerifyBlobRequest
+		expectError bool
+		keyID       string                     // key expected to have made a valid signature
+		failure     blobv1.VerificationFailure // expected failure of an invalid signature
+	}{
+		{name: "record", req: &blobv1.VerifyBlobRequest{Record: record.Payload, Signature: record.Signature},
+			keyID: signer.KeyID()},
+		{name: "countersigned record", req: &blobv1.VerifyBlobRequest{Record: record.Payload, Signature: countersignature},
+			keyID: signature.KeyIDForPublicKey(&countersignerKey.PublicKey)},
+		{name: "detached record", req: &blobv1.VerifyBlobRequest{Record: detachedRecord.Payload, Signature: detachedRecord.Signature},
+			keyID: signer.KeyID()},
+		{name: "uuid and content", req: &blobv1.VerifyBlobRequest{Uuid: stored.Uuid, Content: []byte("hello world")},
+			keyID: signer.KeyID()},
+		{name: "uuid and content of a detached record", req: &blobv1.VerifyBlobRequest{Uuid: detached.Uuid, Content: []byte("release")},
+			keyID: signer.KeyID()},
+		{name: "uuid and content of a detached record without size", req: &blobv1.VerifyBlobRequest{
+			Uuid: unsized.Uuid, Content: []byte("release")}, keyID: signer.KeyID()},
+		{name: "uuid of a stored record", req: &blobv1.VerifyBlobRequest{Uuid: stored.Uuid}, keyID: signer.KeyID()},
+		{name: "uuid, content and signature", req: &blobv1.VerifyBlobRequest{
+			Uuid: stored.Uuid, Content: []byte("hello world"), Signature: countersignature},
+			keyID: signature.KeyIDForPublicKey(&countersignerKey.PublicKey)},
+		{name: "batched record", service: batched, req: &blobv1.VerifyBlobRequest{
+			Record: batchedRecord.Payload, Signature: batchedRecord.Signature, InclusionProof: batchedRecord.InclusionProof},
+			keyID: batchSigner.KeyID()},
+		{name: "batched uuid and content", service: batched, req: &blobv1.VerifyBlobRequest{
+			Uuid: batchedStored.Uuid, Content: []byte("batched")}, keyID: batchSigner.KeyID()},
+
+		{name: "tampered content", req: &blobv1.VerifyBlobRequest{Record: withBlob("hello there"), Signature: record.Signature},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH},
+		{name: "tampered label", req: &blobv1.VerifyBlobRequest{Record: withLabel, Signature: record.Signature},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_SIGNATURE},
+		{name: "other content", req: &blobv1.VerifyBlobRequest{Uuid: stored.Uuid, Content: []byte("hello there")},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH},
+		{name: "other size of a detached record", req: &blobv1.VerifyBlobRequest{Uuid: detached.Uuid, Content: []byte("release!")},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH},
+		{name: "content of another size than signed", req: &blobv1.VerifyBlobRequest{Uuid: wrongSize.Uuid, Content: []byte("release")},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH},
+		{name: "uuid of a detached record without content", req: &blobv1.VerifyBlobRequest{Uuid: detached.Uuid},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH},
+		{name: "signature of another record", req: &blobv1.VerifyBlobRequest{Record: record.Payload, Signature: detachedRecord.Signature},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_SIGNATURE},
+		{name: "batched record without proof", service: batched, req: &blobv1.VerifyBlobRequest{
+			Record: batchedRecord.Payload, Signature: batchedRecord.Signature},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_SIGNATURE},
+		{name: "proof of another tree", service: batched, req: &blobv1.VerifyBlobRequest{
+			Record: batchedRecord.Payload, Signature: batchedRecord.Signature, InclusionProof: otherLeaf},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_SIGNATURE},
+		{name: "leaf outside the tree", service: batched, req: &blobv1.VerifyBlobRequest{
+			Record: batchedRecord.Payload, Signature: batchedRecord.Signature, InclusionProof: outsideTree},
+			failure: blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_INCLUSION_PROOF},
+
+		{name: "neither record nor uuid", req: &blobv1.VerifyBlobRequest{Signature: record.Signature}, expectError: true},
+		{name: "record and uuid", req: &blobv1.VerifyBlobRequest{
+			Record: record.Payload, Uuid: stored.Uuid, Signature: record.Signature}, expectError: true},
+		{name: "record without signature", req: &blobv1.VerifyBlobRequest{Record: record.Payload}, expectError: true},
+		{name: "unknown record", req: &blobv1.VerifyBlobRequest{Uuid: "550e8400-e29b-41d4-a716-446655440000"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.service == nil {
+				tt.service = service
+			}
+			resp, err := tt.service.VerifyBlob(ctx, tt.req)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.keyID != "" {
+				if !resp.Valid || resp.KeyId != tt.keyID || resp.Failure != blobv1.VerificationFailure_VERIFICATION_FAILURE_UNSPECIFIED {
+					t.Fatalf("expected a valid signature by %s but got %v", tt.keyID, resp)
+				}
+				return
+			}
+			if resp.Valid || resp.KeyId != "" || resp.Failure != tt.failure || resp.Error == "" {
+				t.Fatalf("expected failure %v but got %v", tt.failure, resp)
+			}
+		})
+	}
+}
+
+func TestBlobExistsAndMetadata(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	storeResp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "hello world"})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+	unknown := "550e8400-e29b-41d4-a716-446655440000"
+
+	existsResp, err := service.BlobExists(ctx, &blobv1.BlobExistsRequest{Uuid: storeResp.Uuid})
+	if err != nil || !existsResp.Exists {
+		t.Fatalf("expected blob to exist: %v", err)
+	}
+	existsResp, err = service.BlobExists(ctx, &blobv1.BlobExistsRequest{Uuid: unknown})
+	if err != nil || existsResp.Exists {
+		t.Fatalf("expected unknown blob not to exist: %v", err)
+	}
+
+	metadataResp, err := service.GetBlobMetadata(ctx, &blobv1.GetBlobMetadataRequest{Uuid: storeResp.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get metadata: %v", err)
+	}
+	metadata := metadataResp.Metadata
+	if metadata.Uuid != storeResp.Uuid || metadata.Size != int64(len("hello world")) ||
+		metadata.KeyId != signer.KeyID() || metadata.Hash == "" || metadata.Timestamp == "" {
+		t.Fatalf("unexpected metadata: %v", metadata)
+	}
+
+	if _, err := service.GetBlobMetadata(ctx, &blobv1.GetBlobMetadataRequest{Uuid: unknown}); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	labels := map[string]string{"git.commit": "0a1b2c3", "pipeline/id": "42", "owner": "team-a", "env": "prod"}
+	storeResp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "build output", Labels: labels})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+	if _, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "unlabelled"}); err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+
+	// the labels are covered by the signature
+	getResp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: storeResp.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get blob: %v", err)
+	}
+	if !maps.Equal(getResp.Payload.Labels, labels) {
+		t.Fatalf("expected labels %v but got %v", labels, getResp.Payload.Labels)
+	}
+	verifyResponse(t, signer, getResp)
+	getResp.Payload.Labels["owner"] = "team-b"
+	payload, err := verifier.MarshalRecord(getResp.Payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	if err := signer.VerifySignature(payload, getResp.Signature); err == nil {
+		t.Fatal("expected the signature to fail once a label is changed")
+	}
+
+	listResp, err := service.ListBlobs(ctx, &blobv1.ListBlobsRequest{Labels: map[string]string{"owner": "team-a"}})
+	if err != nil {
+		t.Fatalf("failed to list blobs: %v", err)
+	}
+	if len(listResp.Blobs) != 1 || listResp.Blobs[0].Uuid != storeResp.Uuid || !maps.Equal(listResp.Blobs[0].Labels, labels) {
+		t.Fatalf("expected only the labelled blob but got %v", listResp.Blobs)
+	}
+
+	tooMany := map[string]string{}
+	for i := range maxLabels + 1 {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+	invalid := []map[string]string{
+		tooMany,
+		{"": "empty key"},
+		{strings.Repeat("k", maxLabelKeyLength+1): "long key"},
+		{"owner name": "space in key"},
+		{"owner=": "equals in key"},
+		{"owner": strings.Repeat("v", maxLabelValueLength+1)},
+	}
+	for _, labels := range invalid {
+		if _, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "labelled", Labels: labels}); err == nil {
+			t.Fatalf("expected error for labels %v but got none", labels)
+		}
+	}
+}
+
+func TestListBlobs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, _ := newTestService(t)
+
+	start := time.Now().UTC()
+	var stored []string
+	for i := range 5 {
+		resp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: fmt.Sprintf("blob %d", i)})
+		if err != nil {
+			t.Fatalf("failed to store blob: %v", err)
+		}
+		stored = append(stored, resp.Uuid)
+	}
+
+	// pages of two, the token of each page resumes after its last blob
+	var (
+		listed []string
+		token  string
+	)
+	for page := 0; ; page++ {
+		resp, err := service.ListBlobs(ctx, &blobv1.ListBlobsRequest{
+			StartTime: timestamppb.New(start.Add(-time.Second)),
+			PageSize:  2,
+			PageToken: token,
+		})
+		if err != nil {
+			t.Fatalf("failed to list blobs: %v", err)
+		}
+		if len(resp.Blobs) > 2 {
+			t.Fatalf("page %d has %d blobs, more than the page size", page, len(resp.Blobs))
+		}
+		for _, blob := range resp.Blobs {
+			listed = append(listed, blob.Uuid)
+			if !strings.HasSuffix(blob.Timestamp, "Z") || len(blob.Timestamp) != len(timestampFormat) {
+				t.Fatalf("expected a timestamp with microseconds but got %q", blob.Timestamp)
+			}
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	slices.Sort(listed)
+	slices.Sort(stored)
+	if !slices.Equal(listed, stored) {
+		t.Fatalf("expected every stored blob once but got %v", listed)
+	}
+
+	resp, err := service.ListBlobs(ctx, &blobv1.ListBlobsRequest{EndTime: timestamppb.New(start.Add(-time.Second))})
+	if err != nil || len(resp.Blobs) != 0 || resp.NextPageToken != "" {
+		t.Fatalf("expected no blob signed before the start: %v %v", resp, err)
+	}
+
+	invalid := []*blobv1.ListBlobsRequest{
+		{PageSize: -1},
+		{PageSize: maxListPageSize + 1},
+		{PageToken: "not a token"},
+		{StartTime: timestamppb.New(start), EndTime: timestamppb.New(start)},
+	}
+	for _, req := range invalid {
+		if _, err := service.ListBlobs(ctx, req); err == nil {
+			t.Fatalf("expected error for %v but got none", req)
+		}
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		defaultTTL  time.Duration
+		maxTTL      time.Duration
+		ttl         *durationpb.Duration
+		expectTTL   time.Duration // zero when the blob is kept forever
+		expectError bool
+	}{
+		{name: "no policy keeps blobs forever"},
+		{name: "requested ttl without a maximum", ttl: durationpb.New(time.Hour), expectTTL: time.Hour},
+		{name: "server default applies", defaultTTL: 24 * time.Hour, expectTTL: 24 * time.Hour},
+		{name: "maximum is the default without one", maxTTL: 90 * 24 * time.Hour, expectTTL: 90 * 24 * time.Hour},
+		{name: "requested ttl within the maximum", maxTTL: 90 * 24 * time.Hour, ttl: durationpb.New(time.Hour), expectTTL: time.Hour},
+		{name: "requested ttl above the maximum", maxTTL: time.Hour, ttl: durationpb.New(2 * time.Hour), expectError: true},
+		{name: "zero ttl", ttl: durationpb.New(0), expectError: true},
+		{name: "negative ttl", ttl: durationpb.New(-time.Hour), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service, signer := newTestService(t, WithRetentionPolicy(tt.defaultTTL, tt.maxTTL))
+
+			before := time.Now().UTC().Truncate(time.Second)
+			storeResp, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "retained", Ttl: tt.ttl})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to store blob: %v", err)
+			}
+
+			getResp, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: storeResp.Uuid})
+			if err != nil {
+				t.Fatalf("failed to get blob: %v", err)
+			}
+			verifyResponse(t, signer, getResp)
+
+			if tt.expectTTL == 0 {
+				if getResp.Payload.ExpiresAt != "" {
+					t.Fatalf("expected no expiry but got %q", getResp.Payload.ExpiresAt)
+				}
+				return
+			}
+			expiresAt, err := time.Parse(time.RFC3339, getResp.Payload.ExpiresAt)
+			if err != nil {
+				t.Fatalf("invalid expires_at %q: %v", getResp.Payload.ExpiresAt, err)
+			}
+			if ttl := expiresAt.Sub(before); ttl < tt.expectTTL || ttl > tt.expectTTL+time.Minute {
+				t.Fatalf("expected a time to live of %s but got %s", tt.expectTTL, ttl)
+			}
+		})
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	_, signer := newTestService(t)
+	if _, err := NewService(log, store.NewMemoryStorage(), signer, WithRetentionPolicy(2*time.Hour, time.Hour)); err == nil {
+		t.Fatal("expected error for a default time to live above the maximum")
+	}
+}
+
+func TestReapExpired(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	// records cannot be stored already expired through the API
+	expired := &blobv1.BlobRecord{
+		Uuid:      uuid.NewString(),
+		Blob:      "expired",
+		Hash:      hex.EncodeToString(signer.ComputeHash([]byte("expired"))),
+		Timestamp: "2025-07-28T17:42:05Z",
+		ExpiresAt: "2025-10-26T17:42:05Z",
+	}
+	if err := service.signAndStore(ctx, expired); err != nil {
+		t.Fatalf("failed to store expired record: %v", err)
+	}
+	kept, err := service.StoreBlob(ctx, &blobv1.StoreBlobRequest{Blob: "kept", Ttl: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+
+	if _, err := service.GetTombstone(ctx, &blobv1.GetTombstoneRequest{Uuid: expired.Uuid}); !errors.Is(err, store.ErrTombstoneNotFound) {
+		t.Fatalf("expected ErrTombstoneNotFound before expiry but got %v", err)
+	}
+
+	deleted, err := service.ReapExpired(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to reap expired blobs: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted blob but got %d", deleted)
+	}
+
+	_, err = service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: expired.Uuid})
+	if !errors.Is(err, store.ErrBlobNotFound) || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected an expired blob error but got %v", err)
+	}
+	if _, err := service.GetSignedBlob(ctx, &blobv1.GetSignedBlobRequest{Uuid: kept.Uuid}); err != nil {
+		t.Fatalf("blob that has not expired was deleted: %v", err)
+	}
+
+	// the tombstone is signed by the server like a record
+	resp, err := service.GetTombstone(ctx, &blobv1.GetTombstoneRequest{Uuid: expired.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get tombstone: %v", err)
+	}
+	tombstone := resp.Tombstone
+	if tombstone.Payload.Hash != expired.Hash || tombstone.Payload.ExpiresAt != expired.ExpiresAt ||
+		tombstone.Payload.DeletedAt == "" || tombstone.KeyId != signer.KeyID() {
+		t.Fatalf("unexpected tombstone: %v", tombstone)
+	}
+	payload, err := proto.Marshal(tombstone.Payload)
+	if err != nil {
+		t.Fatalf("failed to marshal tombstone: %v", err)
+	}
+	if err := signer.VerifySignature(payload, tombstone.Signature); err != nil {
+		t.Fatalf("failed to verify tombstone signature: %v", err)
+	}
+
+	// nothing is left to delete
+	if deleted, err = service.ReapExpired(ctx, 10); err != nil || deleted != 0 {
+		t.Fatalf("expected nothing to reap: %d %v", deleted, err)
+	}
+}
+
+// malformedExpiredStorage lists rows with an invalid UUID before the expired blobs
+type malformedExpiredStorage struct {
+	*store.MemoryStorage
+	malformed int // number of rows with an invalid UUID
+}
+
+func (s malformedExpiredStorage) ListExpired(ctx context.Context, now time.Time, limit int) ([]*blobv1.BlobMetadata, error) {
+	expired, err := s.MemoryStorage.ListExpired(ctx, now, limit)
+	rows := make([]*blobv1.BlobMetadata, 0, s.malformed+len(expired))
+	for i := 0; i < s.malformed; i++ {
+		rows = append(rows, &blobv1.BlobMetadata{Uuid: fmt.Sprintf("not-a-uuid-%d", i), ExpiresAt: "2025-10-26T17:42:05Z"})
+	}
+	rows = append(rows, expired...)
+	return rows[:min(limit, len(rows))], err
+}
+
+func TestReapExpiredMalformedRow(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, signer := newTestService(t)
+
+	service, err := NewService(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		malformedExpiredStorage{store.NewMemoryStorage(), 1}, signer)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	expired := &blobv1.BlobRecord{
+		Uuid:      uuid.NewString(),
+		Blob:      "expired",
+		Hash:      hex.EncodeToString(signer.ComputeHash([]byte("expired"))),
+		Timestamp: "2025-07-28T17:42:05Z",
+		ExpiresAt: "2025-10-26T17:42:05Z",
+	}
+	if err := service.signAndStore(ctx, expired); err != nil {
+		t.Fatalf("failed to store expired record: %v", err)
+	}
+
+	// the malformed row is skipped, the valid one is still deleted
+	if deleted, err := service.ReapExpired(ctx, 10); err != nil || deleted != 1 {
+		t.Fatalf("expected 1 deleted blob: %d %v", deleted, err)
+	}
+}
+
+func TestReapMalformedRows(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, signer := newTestService(t)
+
+	// more malformed rows than fit in a batch are listed ahead of the expired blobs
+	storage := store.NewMemoryStorage()
+	service, err := NewService(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		malformedExpiredStorage{storage, 3}, signer)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	for i := range 3 {
+		content := fmt.Sprintf("expired %d", i)
+		expired := &blobv1.BlobRecord{
+			Uuid:      uuid.NewString(),
+			Blob:      content,
+			Hash:      hex.EncodeToString(signer.ComputeHash([]byte(content))),
+			Timestamp: "2025-07-28T17:42:05Z",
+			ExpiresAt: "2025-10-26T17:42:05Z",
+		}
+		if err := service.signAndStore(ctx, expired); err != nil {
+			t.Fatalf("failed to store expired record: %v", err)
+		}
+	}
+
+	service.reap(ctx, 2)
+
+	expired, err := storage.ListExpired(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("failed to list expired blobs: %v", err)
+	}
+	if len(expired) != 0 {
+		t.Fatalf("expected the reaper to get past the malformed rows, %d expired blobs left", len(expired))
+	}
+}
+
+func TestLegalHold(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	service, signer := newTestService(t)
+
+	record := &blobv1.BlobRecord{
+		Uuid:      uuid.NewString(),
+		Blob:      "held",
+		Hash:      hex.EncodeToString(signer.ComputeHash([]byte("held"))),
+		Timestamp: "2025-07-28T17:42:05Z",
+		ExpiresAt: "2025-10-26T17:42:05Z",
+	}
+	if err := service.signAndStore(ctx, record); err != nil {
+		t.Fatalf("failed to store expired record: %v", err)
+	}
+
+	invalid := []*blobv1.SetLegalHoldRequest{
+		{Uuid: record.Uuid, Held: true, RequestedBy: "legal"},
+		{Uuid: record.Uuid, Held: true, Reason: "case 42"},
+		{Uuid: record.Uuid, Held: true, Reason: strings.Repeat("a", maxLegalHoldFieldLength+1), RequestedBy: "legal"},
+		{Uuid: "not-a-uuid", Held: true, Reason: "case 42", RequestedBy: "legal"},
+	}
+	for _, req := range invalid {
+		if _, err := service.SetLegalHold(ctx, req); err == nil {
+			t.Fatalf("expected error for request %v", req)
+		}
+	}
+	if _, err := service.SetLegalHold(ctx, &blobv1.SetLegalHoldRequest{
+		Uuid: uuid.NewString(), Held: true, Reason: "case 42", RequestedBy: "legal",
+	}); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+
+	holdResp, err := service.SetLegalHold(ctx, &blobv1.SetLegalHoldRequest{
+		Uuid: record.Uuid, Held: true, Reason: "case 42", RequestedBy: "legal",
+	})
+	if err != nil {
+		t.Fatalf("failed to place legal hold: %v", err)
+	}
+	if !holdResp.Event.Held || holdResp.Event.Timestamp == "" {
+		t.Fatalf("unexpected legal hold event: %v", holdResp.Event)
+	}
+
+	metadataResp, err := service.GetBlobMetadata(ctx, &blobv1.GetBlobMetadataRequest{Uuid: record.Uuid})
+	if err != nil || !metadataResp.Metadata.LegalHold {
+		t.Fatalf("expected metadata to report the legal hold: %v %v", metadataResp, err)
+	}
+
+	// the reaper leaves the expired record alone while it is held
+	if deleted, err := service.ReapExpired(ctx, 10); err != nil || deleted != 0 {
+		t.Fatalf("expected held record not to be reaped: %d %v", deleted, err)
+	}
+
+	if _, err := service.SetLegalHold(ctx, &blobv1.SetLegalHoldRequest{
+		Uuid: record.Uuid, Held: false, Reason: "case 42 closed", RequestedBy: "legal",
+	}); err != nil {
+		t.Fatalf("failed to release legal hold: %v", err)
+	}
+	if deleted, err := service.ReapExpired(ctx, 10); err != nil || deleted != 1 {
+		t.Fatalf("expected released record to be reaped: %d %v", deleted, err)
+	}
+
+	historyResp, err := service.GetLegalHoldHistory(ctx, &blobv1.GetLegalHoldHistoryRequest{Uuid: record.Uuid})
+	if err != nil {
+		t.Fatalf("failed to get legal hold history: %v", err)
+	}
+	if events := historyResp.Events; len(events) != 2 || !events[0].Held || events[1].Held ||
+		events[1].Reason != "case 42 closed" {
+		t.Fatalf("unexpected legal hold history: %v", events)
+	}
+}
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/merkle"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/prit342/signed-blob-service/verifier"
+)
+
+// VerifyBlob verifies a signature over a record, or over a stored record and the content it should
+// match, under the server signing key and the registered countersigning keys. An invalid signature
+// is reported in the response, errors are left for invalid requests.
+func (s *Service) VerifyBlob(ctx context.Context, req *blobv1.VerifyBlobRequest) (*blobv1.VerifyBlobResponse, error) {
+	if req == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	record, sig, proof := req.Record, req.Signature, req.InclusionProof
+	var opts []verifier.Option
+	switch {
+	case req.Record != nil && req.Uuid != "":
+		return nil, errors.New("only one of record and uuid can be set")
+	case req.Record != nil:
+		if len(sig) == 0 {
+			return nil, errors.New("signature cannot be empty")
+		}
+	case req.Uuid != "":
+		blobUUID, err := uuid.Parse(req.Uuid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UUID format: %w", err)
+		}
+		stored, err := s.store.GetByUUID(ctx, blobUUID)
+		if err != nil {
+			if errors.Is(err, store.ErrBlobNotFound) {
+				return nil, s.notFoundError(ctx, blobUUID, err)
+			}
+			s.logger.Error("failed to retrieve blob", "error", err, "uuid", req.Uuid)
+			return nil, fmt.Errorf("failed to retrieve blob: %w", err)
+		}
+		record = stored.Payload
+		if len(sig) == 0 {
+			sig, proof = stored.Signature, stored.InclusionProof
+		}
+		// the content must be the one the record was signed for, detached records only carry its hash
+		// and their size when signed with one, the stored content is verified when none is given
+		if len(req.Content) > 0 || record.Detached {
+			opts = append(opts, verifier.WithContent(req.Content))
+		}
+	default:
+		return nil, errors.New("either record or uuid must be set")
+	}
+
+	// records signed in a Merkle batch are signed through the tree head their proof leads to
+	serverOpts := append(slices.Clone(opts), verifier.WithInclusionProof(proof))
+	result, err := verifier.Verify(record, sig, []crypto.PublicKey{s.publicKey}, serverOpts...)
+	var signatureErr *verifier.SignatureError
+	if errors.As(err, &signatureErr) {
+		// countersignatures cover the record itself, never a tree head
+		keys := make([]crypto.PublicKey, 0, len(s.countersignerKeys))
+		for _, keyID := range slices.Sorted(maps.Keys(s.countersignerKeys)) {
+			keys = append(keys, s.countersignerKeys[keyID])
+		}
+		if len(keys) > 0 {
+			if countersigned, countersignedErr := verifier.Verify(record, sig, keys, opts...); countersignedErr == nil {
+				result, err = countersigned, nil
+			}
+		}
+	}
+
+	var hashErr *verifier.HashMismatchError
+	var sizeErr *verifier.SizeMismatchError
+	switch {
+	case err == nil:
+		return &blobv1.VerifyBlobResponse{Valid: true, KeyId: result.KeyID}, nil
+	case errors.As(err, &hashErr), errors.As(err, &sizeErr):
+		return verificationFailed(blobv1.VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH, err), nil
+	case errors.Is(err, merkle.ErrInvalidProof):
+		return verificationFailed(blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_INCLUSION_PROOF, err), nil
+	case errors.As(err, &signatureErr):
+		return verificationFailed(blobv1.VerificationFailure_VERIFICATION_FAILURE_INVALID_SIGNATURE,
+			fmt.Errorf("not signed by the server key or a registered countersigning key: %w", err)), nil
+	default:
+		return nil, fmt.Errorf("failed to verify blob: %w", err)
+	}
+}
+
+// verificationFailed returns the response of a failed verification
+func verificationFailed(failure blobv1.VerificationFailure, err error) *blobv1.VerifyBlobResponse {
+	return &blobv1.VerifyBlobResponse{Failure: failure, Error: err.Error()}
+}
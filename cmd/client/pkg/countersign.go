@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/verifier"
+	"github.com/spf13/cobra"
+)
+
+var countersignKeyPath string // private key of the countersigning party
+
+func init() {
+	countersignCommand.Flags().StringVar(&countersignKeyPath, "private-key", "",
+		"Path to the PEM-encoded private key to countersign with (required)")
+	_ = countersignCommand.MarkFlagRequired("private-key")
+	rootCmd.AddCommand(countersignCommand)
+}
+
+var countersignCommand = &cobra.Command{
+	Use:          "countersign <uuid> --private-key <path>",
+	SilenceUsage: true,
+	Short:        "Adds a countersignature by your own key to a signed blob",
+	Long: `Fetches the signed record, signs the same payload as the server with your private key
+and attaches the signature to the record.
+
+The server only accepts countersignatures from keys it has registered in COUNTERSIGNER_KEYS_PATH.
+RSA, ECDSA P-256 and Ed25519 keys are supported.
+
+Example:
+  ./client countersign 10315b7a... --private-key reviewer.pem
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please provide the UUID of the blob to countersign")
+		}
+		blobUUID := args[0]
+		if _, err := uuid.Parse(blobUUID); err != nil {
+			return fmt.Errorf("invalid UUID format, please provide a valid UUID: %w", err)
+		}
+
+		signer, err := signature.NewSignerFromFile(countersignKeyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load private key: %w", err)
+		}
+
+		resp, err := client.GetSignedBlob(cmd.Context(), &blobv1.GetSignedBlobRequest{Uuid: blobUUID})
+		if err != nil {
+			return fmt.Errorf("unable to get blob: %w", err)
+		}
+		if resp == nil || resp.Payload == nil {
+			return errors.New("got empty response from the server")
+		}
+
+		// countersign exactly the bytes the server signed
+		payloadBytes, err := verifier.MarshalRecord(resp.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+
+		sig, err := signer.Sign(payloadBytes)
+		if err != nil {
+			return fmt.Errorf("failed to sign payload: %w", err)
+		}
+
+		addResp, err := client.AddCountersignature(cmd.Context(), &blobv1.AddCountersignatureRequest{
+			Uuid:      blobUUID,
+			KeyId:     signer.KeyID(),
+			Signature: sig,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to add countersignature: %w", err)
+		}
+
+		log.Printf("✅ Countersignature added by key %s at %s",
+			addResp.GetCountersignature().GetKeyId(), addResp.GetCountersignature().GetTimestamp())
+
+		return nil
+	},
+}
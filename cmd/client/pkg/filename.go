@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// sidecarExtensions are those of the files get writes next to the content, named <uuid><extension>
+var sidecarExtensions = []string{".sig", ".meta.json", ".jws", ".cose"}
+
+// contentFilename returns the name of the file the content of a record is saved to: its original
+// filename made safe to write, <uuid> with the extension of its content type, or <uuid>.txt.
+// original reports whether the name is the signed filename, which may be that of an unrelated file.
+// Names of the files written next to the content are never used, they would replace it.
+func contentFilename(blobUUID, filename, contentType string) (name string, original bool) {
+	if name := sanitizeFilename(filename); name != "" && !isSidecar(blobUUID, name) {
+		return name, true
+	}
+	if contentType != "" {
+		if extensions, err := mime.ExtensionsByType(contentType); err == nil && len(extensions) > 0 &&
+			!isSidecar(blobUUID, blobUUID+extensions[0]) {
+			return blobUUID + extensions[0], false
+		}
+	}
+	return blobUUID + ".txt", false
+}
+
+// isSidecar reports whether the name is that of a file get writes next to the content,
+// ignoring case as file systems may
+func isSidecar(blobUUID, name string) bool {
+	for _, extension := range sidecarExtensions {
+		if strings.EqualFold(name, blobUUID+extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeFilename returns a filename signed by the server reduced to a plain file name in the
+// download directory: directories, control characters and leading dots are dropped so it can
+// neither escape the directory nor hide or replace a dotfile. It is "" when nothing is left.
+func sanitizeFilename(filename string) string {
+	// the server only accepts base names, a name from another platform may still use backslashes
+	filename = filepath.Base(strings.ReplaceAll(filename, `\`, "/"))
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r), r == unicode.ReplacementChar:
+			return -1
+		case strings.ContainsRune(`/:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, filename)
+	// the server bounds the length, replacing characters never makes the name longer
+	return strings.TrimSpace(strings.TrimLeft(name, ". "))
+}
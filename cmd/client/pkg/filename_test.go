@@ -0,0 +1,81 @@
+package pkg
+
+import "testing"
+
+func TestSanitizeFilename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		expected string
+	}{
+		{name: "plain name", filename: "report.pdf", expected: "report.pdf"},
+		{name: "parent directory", filename: "../x", expected: "x"},
+		{name: "parent directory with backslashes", filename: `..\x`, expected: "x"},
+		{name: "nested parent directories", filename: "../../etc/passwd", expected: "passwd"},
+		{name: "absolute path", filename: "/abs", expected: "abs"},
+		{name: "windows path", filename: `C:\Users\me\report.pdf`, expected: "report.pdf"},
+		{name: "leading dot", filename: ".bashrc", expected: "bashrc"},
+		{name: "leading dots and spaces", filename: ". .. hidden.txt", expected: "hidden.txt"},
+		{name: "inner dots kept", filename: "archive.tar.gz", expected: "archive.tar.gz"},
+		{name: "control characters", filename: "a\x00b\nc\x1b.txt", expected: "abc.txt"},
+		{name: "invalid UTF-8", filename: "a\xffb.txt", expected: "ab.txt"},
+		{name: "reserved characters", filename: `a:b*c?"<d>|.txt`, expected: "a_b_c___d__.txt"},
+		{name: "surrounding spaces", filename: " report.pdf ", expected: "report.pdf"},
+		{name: "empty", filename: "", expected: ""},
+		{name: "dot", filename: ".", expected: ""},
+		{name: "all dots", filename: "...", expected: ""},
+		{name: "parent directory only", filename: "..", expected: ""},
+		{name: "control characters only", filename: "\x00\t\n", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := sanitizeFilename(tt.filename); got != tt.expected {
+				t.Fatalf("expected %q but got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestContentFilename(t *testing.T) {
+	t.Parallel()
+
+	const blobUUID = "10315b7a-0000-0000-0000-000000000000"
+
+	tests := []struct {
+		name        string
+		filename    string
+		contentType string
+		expected    string
+		original    bool
+	}{
+		{name: "original filename", filename: "report.pdf", contentType: "image/png", expected: "report.pdf", original: true},
+		{name: "sanitised filename", filename: "../.report.pdf", expected: "report.pdf", original: true},
+		{name: "extension of the content type", contentType: "image/png", expected: blobUUID + ".png"},
+		{name: "nothing left of the filename", filename: "..", contentType: "application/pdf", expected: blobUUID + ".pdf"},
+		{name: "content type with parameters", contentType: "application/pdf; name=x", expected: blobUUID + ".pdf"},
+		{name: "unknown content type", contentType: "application/x-signed-blob-unknown", expected: blobUUID + ".txt"},
+		{name: "invalid content type", contentType: "not a type", expected: blobUUID + ".txt"},
+		{name: "no filename nor content type", expected: blobUUID + ".txt"},
+		{name: "signature file", filename: blobUUID + ".sig", expected: blobUUID + ".txt"},
+		{name: "metadata file", filename: blobUUID + ".meta.json", contentType: "image/png", expected: blobUUID + ".png"},
+		{name: "JWS file", filename: blobUUID + ".jws", expected: blobUUID + ".txt"},
+		{name: "COSE file", filename: blobUUID + ".cose", expected: blobUUID + ".txt"},
+		{name: "signature file in upper case", filename: "10315B7A-0000-0000-0000-000000000000.SIG", expected: blobUUID + ".txt"},
+		{name: "signature file of another record", filename: "20315b7a-0000-0000-0000-000000000000.sig",
+			expected: "20315b7a-0000-0000-0000-000000000000.sig", original: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, original := contentFilename(blobUUID, tt.filename, tt.contentType)
+			if got != tt.expected || original != tt.original {
+				t.Fatalf("expected %q original %v but got %q original %v", tt.expected, tt.original, got, original)
+			}
+		})
+	}
+}
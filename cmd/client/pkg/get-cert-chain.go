@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(getCertChainCommand)
+}
+
+var getCertChainCommand = &cobra.Command{
+	Use:          "get-cert-chain <filename>",
+	SilenceUsage: true,
+	Short:        "Downloads the X.509 certificate chain of the server signing key and stores it in a file.",
+	Long: `Fetches the PEM-encoded certificate chain (leaf first) certifying the server signing key.
+
+Overrides the destination file if it already exists.
+The chain can be passed to verify with --cert-chain and validated against a trusted --root-bundle.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please provide a filename to save the certificate chain")
+		}
+
+		chainFile := args[0]
+
+		resp, err := client.GetCertificateChain(cmd.Context(), &blobv1.GetCertificateChainRequest{})
+		if err != nil {
+			return fmt.Errorf("unable to get certificate chain: %w", err)
+		}
+
+		if err := os.WriteFile(chainFile, []byte(resp.GetCertificateChain()), 0600); err != nil {
+			return fmt.Errorf("failed to write certificate chain to file %s: %w", chainFile, err)
+		}
+
+		log.Printf("✅ Certificate chain saved to file: %s", chainFile)
+		log.Printf("ℹ️ Key ID: %s", resp.GetKeyId())
+
+		return nil
+	},
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func init() {
+	rootCmd.AddCommand(getMetadataCommand)
+	rootCmd.AddCommand(existsCommand)
+}
+
+var getMetadataCommand = &cobra.Command{
+	Use:          "get-metadata <uuid>",
+	SilenceUsage: true,
+	Short:        "Prints the metadata of a signed blob as JSON without downloading its content",
+	Long: `Fetches the uuid, hash, timestamp, size and signing key ID of a signed blob
+and prints them as JSON on stdout, the content and signature are not downloaded.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please provide a UUID")
+		}
+		if _, err := uuid.Parse(args[0]); err != nil {
+			return fmt.Errorf("invalid UUID format, please provide a valid UUID: %w", err)
+		}
+
+		resp, err := client.GetBlobMetadata(cmd.Context(), &blobv1.GetBlobMetadataRequest{Uuid: args[0]})
+		if err != nil {
+			return fmt.Errorf("unable to get blob metadata: %w", err)
+		}
+
+		out, err := protojson.MarshalOptions{UseProtoNames: true, Multiline: true}.Marshal(resp.GetMetadata())
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata into JSON: %w", err)
+		}
+		fmt.Println(string(out))
+
+		return nil
+	},
+}
+
+var existsCommand = &cobra.Command{
+	Use:          "exists <uuid>",
+	SilenceUsage: true,
+	Short:        "Checks whether a signed blob exists, exits with an error when it does not",
+	Long: `Asks the server whether a signed blob with the UUID is stored and prints true or false.
+The command fails when the blob does not exist, so it can be used as a condition in scripts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please provide a UUID")
+		}
+		if _, err := uuid.Parse(args[0]); err != nil {
+			return fmt.Errorf("invalid UUID format, please provide a valid UUID: %w", err)
+		}
+
+		resp, err := client.BlobExists(cmd.Context(), &blobv1.BlobExistsRequest{Uuid: args[0]})
+		if err != nil {
+			return fmt.Errorf("unable to check if blob exists: %w", err)
+		}
+
+		fmt.Println(resp.GetExists())
+		if !resp.GetExists() {
+			return fmt.Errorf("blob %s does not exist", args[0])
+		}
+
+		return nil
+	},
+}
@@ -9,7 +9,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	getJWKS    bool // save the public key as a JWK Set instead of PEM
+	getCOSEKey bool // save the public key as a COSE_Key instead of PEM
+)
+
 func init() {
+	getPublicKeyCommand.Flags().BoolVar(&getJWKS, "jwks", false,
+		"Save the public key as a JWK Set document instead of PEM")
+	getPublicKeyCommand.Flags().BoolVar(&getCOSEKey, "cose-key", false,
+		"Save the public key as a CBOR encoded COSE_Key instead of PEM")
+	getPublicKeyCommand.MarkFlagsMutuallyExclusive("jwks", "cose-key")
 	rootCmd.AddCommand(getPublicKeyCommand)
 }
 
@@ -28,20 +38,35 @@ var getPublicKeyCommand = &cobra.Command{
 
 		publicKeyFile := args[0]
 
-		resp, err := client.GetPublicKey(cmd.Context(),
-			&blobv1.GetPublicKeyRequest{},
-		)
+		req := &blobv1.GetPublicKeyRequest{}
+		if getJWKS {
+			req.Format = blobv1.PublicKeyFormat_PUBLIC_KEY_FORMAT_JWKS
+		}
+		if getCOSEKey {
+			req.Format = blobv1.PublicKeyFormat_PUBLIC_KEY_FORMAT_COSE_KEY
+		}
+
+		resp, err := client.GetPublicKey(cmd.Context(), req)
 
 		if err != nil {
 			return fmt.Errorf("unable to write file %q: %w", publicKeyFile, err)
 		}
 
+		content := []byte(resp.PublicKey)
+		if getJWKS {
+			content = []byte(resp.Jwks)
+		}
+		if getCOSEKey {
+			content = resp.CoseKey
+		}
+
 		// write the public key to the file
-		if err := os.WriteFile(publicKeyFile, []byte(resp.PublicKey), 0600); err != nil {
+		if err := os.WriteFile(publicKeyFile, content, 0600); err != nil {
 			return fmt.Errorf("failed to write blob to file %s: %w", publicKeyFile, err)
 		}
 		// user feedback
 		log.Printf("✅ Public key saved to file: %s", publicKeyFile)
+		log.Printf("ℹ️ Key ID: %s", resp.GetKeyId())
 
 		return nil
 	},
@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func init() {
+	rootCmd.AddCommand(getTombstoneCommand)
+}
+
+var getTombstoneCommand = &cobra.Command{
+	Use:          "get-tombstone <uuid>",
+	SilenceUsage: true,
+	Short:        "Prints the signed tombstone of an expired blob as JSON",
+	Long: `Fetches the tombstone the server keeps once a blob has expired and been deleted,
+and prints it as JSON on stdout. The tombstone records the hash, signing time, expiry and
+deletion time of the blob. Its signature is over the Protobuf-encoded Tombstone payload,
+made with the server signing key identified by key_id.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please provide a UUID")
+		}
+		if _, err := uuid.Parse(args[0]); err != nil {
+			return fmt.Errorf("invalid UUID format, please provide a valid UUID: %w", err)
+		}
+
+		resp, err := client.GetTombstone(cmd.Context(), &blobv1.GetTombstoneRequest{Uuid: args[0]})
+		if err != nil {
+			return fmt.Errorf("unable to get tombstone: %w", err)
+		}
+
+		out, err := protojson.MarshalOptions{UseProtoNames: true, Multiline: true}.Marshal(resp.GetTombstone())
+		if err != nil {
+			return fmt.Errorf("failed to marshal tombstone into JSON: %w", err)
+		}
+		fmt.Println(string(out))
+
+		return nil
+	},
+}
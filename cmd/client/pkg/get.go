@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/google/uuid"
 	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
@@ -14,11 +15,21 @@ import (
 
 var (
 	storeDir string // place to strore the downloaded files
+	getJWS   bool   // also download the record as a JWS
+	getCOSE  bool   // also download the record as a COSE_Sign1 message
+	getForce bool   // overwrite an existing file with the original filename of the content
 )
 
 func init() {
 	getCommand.Flags().StringVar(&storeDir, "dir", ".",
 		"Directory to store downloaded blob files (default: current directory)")
+	getCommand.Flags().BoolVar(&getJWS, "jws", false,
+		"Also save the signed record as a JWS compact serialisation in <uuid>.jws")
+	getCommand.Flags().BoolVar(&getCOSE, "cose", false,
+		"Also save the signed record as a CBOR encoded COSE_Sign1 message in <uuid>.cose")
+	getCommand.Flags().BoolVar(&getForce, "force", false,
+		"Overwrite an existing file with the original filename of the blob")
+	getCommand.MarkFlagsMutuallyExclusive("jws", "cose")
 	rootCmd.AddCommand(getCommand)
 }
 
@@ -29,9 +40,12 @@ var getCommand = &cobra.Command{
 	Long: `Downloads a signed blob identified by its UUID from the server.
 
 			The following files will be saved:
-			- <uuid>.txt     : The raw blob content
+			- <filename>     : The raw blob content under its original filename, sanitised,
+			                   or <uuid>.txt when it has none (not for detached records)
 			- <uuid>.sig     : The base64-encoded signature
-			- <uuid>.meta    : Metadata including UUID, hash, and timestamp
+			- <uuid>.meta    : Metadata including UUID, hash, timestamp, labels and countersignatures
+			- <uuid>.jws     : The signed record as a JWS (only with --jws)
+			- <uuid>.cose    : The signed record as a COSE_Sign1 message (only with --cose)
 
 			These files can later be used to verify the integrity and authenticity of the blob.
 `,
@@ -52,11 +66,17 @@ var getCommand = &cobra.Command{
 			return fmt.Errorf("%s is not a directory", stat.Name())
 		}
 
-		resp, err := client.GetSignedBlob(cmd.Context(),
-			&blobv1.GetSignedBlobRequest{
-				Uuid: blobUUID,
-			},
-		)
+		req := &blobv1.GetSignedBlobRequest{
+			Uuid: blobUUID,
+		}
+		if getJWS {
+			req.Format = blobv1.SignedBlobFormat_SIGNED_BLOB_FORMAT_JWS
+		}
+		if getCOSE {
+			req.Format = blobv1.SignedBlobFormat_SIGNED_BLOB_FORMAT_COSE_SIGN1
+		}
+
+		resp, err := client.GetSignedBlob(cmd.Context(), req)
 
 		if err != nil {
 			return fmt.Errorf("unable to get blob: %w", err)
@@ -66,11 +86,18 @@ var getCommand = &cobra.Command{
 			log.Fatal("response is nil, please check the server logs")
 		}
 
-		// write blob contents to to <UUID>.txt
-		// in our service we only sign the Payload.Blob, so we can safely write it to a file
-		blobFilename := fmt.Sprintf("%s/%s.txt", storeDir, blobUUID)
-		if err := os.WriteFile(blobFilename, []byte(resp.Payload.Blob), 0600); err != nil {
-			return fmt.Errorf("failed to write blob to file %s: %v", blobFilename, err)
+		// write blob contents under the original filename, or <UUID>.txt
+		// detached records have no content on the server, the original file is kept by the user
+		name, original := contentFilename(blobUUID, resp.GetPayload().GetFilename(), resp.GetPayload().GetContentType())
+		blobFilename := filepath.Join(storeDir, name)
+		if !resp.GetPayload().GetDetached() {
+			// unlike <UUID>.txt the original filename may be that of an unrelated file
+			if _, err := os.Lstat(blobFilename); err == nil && original && !getForce {
+				return fmt.Errorf("%s already exists, use --force to overwrite it or --dir to save elsewhere", blobFilename)
+			}
+			if err := os.WriteFile(blobFilename, []byte(resp.Payload.Blob), 0600); err != nil {
+				return fmt.Errorf("failed to write blob to file %s: %v", blobFilename, err)
+			}
 		}
 
 		// write signature to <UUID>.sig (base64-encoded)
@@ -84,9 +111,30 @@ var getCommand = &cobra.Command{
 		metaFilename := fmt.Sprintf("%s/%s.meta.json", storeDir, blobUUID)
 
 		m := metaData{
-			UUID:      resp.GetPayload().GetUuid(),
-			Hash:      resp.GetPayload().GetHash(),
-			TimeStamp: resp.GetPayload().GetTimestamp(),
+			UUID:        resp.GetPayload().GetUuid(),
+			Hash:        resp.GetPayload().GetHash(),
+			TimeStamp:   resp.GetPayload().GetTimestamp(),
+			Detached:    resp.GetPayload().GetDetached(),
+			Filename:    resp.GetPayload().GetFilename(),
+			Size:        resp.GetPayload().GetSize(),
+			ExpiresAt:   resp.GetPayload().GetExpiresAt(),
+			Labels:      resp.GetPayload().GetLabels(),
+			ContentType: resp.GetPayload().GetContentType(),
+		}
+		for _, cs := range resp.GetCountersignatures() {
+			m.Countersignatures = append(m.Countersignatures, countersignature{
+				KeyID:     cs.GetKeyId(),
+				Signature: cs.GetSignature(),
+				TimeStamp: cs.GetTimestamp(),
+			})
+		}
+
+		if proof := resp.GetInclusionProof(); proof != nil {
+			m.InclusionProof = &inclusionProof{
+				LeafIndex: proof.GetLeafIndex(),
+				TreeSize:  proof.GetTreeSize(),
+				Hashes:    proof.GetHashes(),
+			}
 		}
 
 		metaByte, err := json.MarshalIndent(&m, "", "  ")
@@ -99,10 +147,32 @@ var getCommand = &cobra.Command{
 		}
 
 		// user feedback
-		log.Printf("✅ Blob content saved to: %s", blobFilename)
+		if resp.GetPayload().GetDetached() {
+			log.Printf("ℹ️ Detached record for:   %s", resp.GetPayload().GetFilename())
+		} else {
+			log.Printf("✅ Blob content saved to: %s", blobFilename)
+		}
 		log.Printf("✅ Signature saved to:    %s", sigFilename)
 		log.Printf("ℹ️ Metadata saved to:     %s", metaFilename)
 
+		if getJWS {
+			// write the JWS compact serialisation to <UUID>.jws
+			jwsFilename := fmt.Sprintf("%s/%s.jws", storeDir, blobUUID)
+			if err := os.WriteFile(jwsFilename, []byte(resp.GetJws()), 0600); err != nil {
+				return fmt.Errorf("failed to write JWS to file %q: %w", jwsFilename, err)
+			}
+			log.Printf("✅ JWS saved to:          %s", jwsFilename)
+		}
+
+		if getCOSE {
+			// write the COSE_Sign1 message to <UUID>.cose
+			coseFilename := fmt.Sprintf("%s/%s.cose", storeDir, blobUUID)
+			if err := os.WriteFile(coseFilename, resp.GetCoseSign1(), 0600); err != nil {
+				return fmt.Errorf("failed to write COSE_Sign1 to file %q: %w", coseFilename, err)
+			}
+			log.Printf("✅ COSE_Sign1 saved to:   %s", coseFilename)
+		}
+
 		return nil
 	},
 }
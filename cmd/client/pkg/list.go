@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	listFrom   string
+	listTo     string
+	listLimit  int
+	listLabels []string
+)
+
+// listPageSize is the number of blobs fetched per request
+const listPageSize = 1000
+
+func init() {
+	rootCmd.AddCommand(listCommand)
+	listCommand.Flags().StringVar(&listFrom, "from", "",
+		"list the blobs signed at or after this RFC3339 time")
+	listCommand.Flags().StringVar(&listTo, "to", "",
+		"list the blobs signed before this RFC3339 time")
+	listCommand.Flags().IntVar(&listLimit, "limit", 100,
+		"maximum number of blobs listed, 0 lists them all")
+	listCommand.Flags().StringArrayVar(&listLabels, "label", nil,
+		"only list the blobs carrying this key=value label, repeat to require several")
+}
+
+var listCommand = &cobra.Command{
+	Use:          "list",
+	SilenceUsage: true,
+	Short:        "Prints the metadata of the blobs signed within a time range, oldest first",
+	Long: `Lists the blobs signed within a time range and prints the metadata of each one as a
+line of JSON on stdout, oldest first. --from and --to are RFC3339 times such as
+2025-07-28T17:42:05Z, either can be left out for an open range. With --label key=value,
+repeated for each label, only the blobs carrying all of the labels are listed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listLimit < 0 {
+			return errors.New("--limit cannot be negative")
+		}
+
+		labels, err := parseLabels(listLabels)
+		if err != nil {
+			return err
+		}
+
+		req := &blobv1.ListBlobsRequest{Labels: labels}
+		if listFrom != "" {
+			from, err := time.Parse(time.RFC3339Nano, listFrom)
+			if err != nil {
+				return fmt.Errorf("invalid --from time: %w", err)
+			}
+			req.StartTime = timestamppb.New(from)
+		}
+		if listTo != "" {
+			to, err := time.Parse(time.RFC3339Nano, listTo)
+			if err != nil {
+				return fmt.Errorf("invalid --to time: %w", err)
+			}
+			req.EndTime = timestamppb.New(to)
+		}
+
+		// follow the page tokens until the limit or the last page
+		listed := 0
+		for {
+			req.PageSize = listPageSize
+			if listLimit > 0 {
+				req.PageSize = int32(min(listLimit-listed, listPageSize))
+			}
+
+			resp, err := client.ListBlobs(cmd.Context(), req)
+			if err != nil {
+				return fmt.Errorf("unable to list blobs: %w", err)
+			}
+
+			for _, metadata := range resp.GetBlobs() {
+				out, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(metadata)
+				if err != nil {
+					return fmt.Errorf("failed to marshal metadata into JSON: %w", err)
+				}
+				fmt.Println(string(out))
+			}
+			listed += len(resp.GetBlobs())
+
+			if resp.GetNextPageToken() == "" || listLimit > 0 && listed >= listLimit {
+				return nil
+			}
+			req.PageToken = resp.GetNextPageToken()
+		}
+	},
+}
@@ -1,8 +1,43 @@
 package pkg
 
+import blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+
 // metaData is the data associated with each blob.
 type metaData struct {
 	UUID      string `json:"uuid"`
 	Hash      string `json:"hash"`
 	TimeStamp string `json:"timestamp"`
+	Detached  bool   `json:"detached,omitempty"` // the content was not uploaded, only its digest was signed
+	Filename  string `json:"filename,omitempty"` // original filename, the content is saved under it sanitised
+	Size      int64  `json:"size,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"` // the server deletes the record at this time
+	// Labels signed with the blob
+	Labels      map[string]string `json:"labels,omitempty"`
+	ContentType string            `json:"content_type,omitempty"` // media type of the content
+	// Countersignatures by other parties over the same payload
+	Countersignatures []countersignature `json:"countersignatures,omitempty"`
+	// InclusionProof of a record signed in a Merkle batch, the signature covers the root it leads to
+	InclusionProof *inclusionProof `json:"inclusion_proof,omitempty"`
+}
+
+// inclusionProof is the path from a record to the root of the Merkle batch it was signed in.
+type inclusionProof struct {
+	LeafIndex int64    `json:"leaf_index"`
+	TreeSize  int64    `json:"tree_size"`
+	Hashes    [][]byte `json:"hashes"` // base64-encoded in the JSON file, from the leaf up to the root
+}
+
+// toProto returns the proof as the server sends it, nil for records signed on their own
+func (p *inclusionProof) toProto() *blobv1.InclusionProof {
+	if p == nil {
+		return nil
+	}
+	return &blobv1.InclusionProof{LeafIndex: p.LeafIndex, TreeSize: p.TreeSize, Hashes: p.Hashes}
+}
+
+// countersignature is a signature by another party over the same payload as the server signature.
+type countersignature struct {
+	KeyID     string `json:"key_id"`
+	Signature []byte `json:"signature"` // base64-encoded in the JSON file
+	TimeStamp string `json:"timestamp"`
 }
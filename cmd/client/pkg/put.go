@@ -1,87 +1,388 @@
 package pkg
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	putDetached    bool          // sign only the digest of the file, the content is not uploaded
+	putTTL         time.Duration // time to live of the record, zero leaves it to the server
+	putLabels      []string      // key=value labels signed with the blob
+	putContentType string        // media type of the file, detected when empty
 )
 
 func init() {
+	putCommand.Flags().BoolVar(&putDetached, "detached", false,
+		"Sign only the SHA-256 digest of the file, the content stays local and is not size limited")
+	putCommand.Flags().DurationVar(&putTTL, "ttl", 0,
+		"Delete the record after this long, e.g. 2160h for 90 days (default: the server's retention policy)")
+	putCommand.Flags().StringArrayVar(&putLabels, "label", nil,
+		"Label signed with the blob as key=value, e.g. git.commit=0a1b2c3, repeat for each label")
+	putCommand.Flags().StringVar(&putContentType, "content-type", "",
+		"Media type of the file (default: from its extension, or detected from its content)")
+	putCommand.MarkFlagsMutuallyExclusive("detached", "label")
+	putCommand.MarkFlagsMutuallyExclusive("detached", "content-type")
 	rootCmd.AddCommand(putCommand)
 }
 
+// limits of the StoreBlobs requests put sends, the content of every file counts against the
+// 4MB default gRPC message size
+const (
+	putBatchSize  = 500
+	putBatchBytes = 3 * 1024 * 1024
+)
+
 var putCommand = &cobra.Command{
-	Use:          "put <filename>",
+	Use:          "put <file or directory>...",
 	SilenceUsage: true,
 	Short:        "uploads a blob from a file and then and return its unique UUID",
-	Long:         `uploads a blob of content to the Sign-Blob-Service and return its UUID.`,
+	Long: `uploads a blob of content to the Sign-Blob-Service and return its UUID.
+
+Given several files, or a directory whose regular files are all uploaded, the blobs are
+sent in batches and the path and UUID of each one is printed. A file that fails does not
+stop the others, put reports it and fails once all of them are done.
+
+With --detached only the SHA-256 digest, base filename and size of the file are sent.
+The server signs a detached record and the original file is needed to verify it later.
+
+With --ttl the server deletes the record once the time to live has passed, keeping only a
+signed tombstone. The server may reject a time to live longer than its retention policy allows.
+
+With --label key=value, repeated for each label, the labels are signed along with the blob,
+for instance the git commit, pipeline ID or owner it came from.
+
+The base name of the file and its media type are signed with the blob too, so get can
+restore the file under its original name. --content-type overrides the detected type.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return errors.New("Please provide a file name to upload")
 		}
-		filename := args[0]
-		if filename == "" {
-			return errors.New("Please provide a file name to upload")
-		}
-		// grab the full path of the file
-		fullPath, err := filepath.Abs(filename)
+
+		labels, err := parseLabels(putLabels)
 		if err != nil {
-			return fmt.Errorf("unable to get full path of the file: %w", err)
+			return err
 		}
 
-		fileInfo, err := os.Stat(fullPath)
+		files, err := collectFiles(args)
 		if err != nil {
-			return fmt.Errorf("error reading file %s: %w", fullPath, err)
+			return err
+		}
+
+		// a single file keeps the plain StoreBlob and its error
+		if len(args) == 1 && len(files) == 1 && files[0] == args[0] {
+			blobUUID, err := putFile(cmd.Context(), files[0], labels)
+			if err != nil {
+				return err
+			}
+			if putDetached {
+				log.Printf("Detached record signed successfully with UUID: %s", blobUUID)
+			} else {
+				log.Printf("Blob stored successfully with UUID: %s", blobUUID)
+			}
+			return nil
+		}
+
+		if len(files) == 0 {
+			return errors.New("no regular files to upload")
 		}
 
-		if fileInfo.IsDir() {
-			return fmt.Errorf("%q is a directory, please provide a file", fullPath)
+		failed := 0
+		if putDetached {
+			// there is no batch of digests, they are small and quick to sign
+			for _, filename := range files {
+				blobUUID, err := putFile(cmd.Context(), filename, labels)
+				if err != nil {
+					log.Printf("%s: %s", filename, err)
+					failed++
+					continue
+				}
+				fmt.Printf("%s: %s\n", filename, blobUUID)
+			}
+		} else {
+			failed = putFiles(cmd.Context(), files, labels)
 		}
-		// check if the fileInfo is a regular file and not a
-		if !fileInfo.Mode().IsRegular() {
-			return fmt.Errorf("file %s is not a regular file", fullPath)
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d files failed to upload", failed, len(files))
 		}
+		return nil
+	},
+}
 
-		// open the file and read its content
-		file, err := os.Open(fullPath)
+// collectFiles returns the files named by the arguments and the regular files found in
+// the directories among them, each directory walked in lexical order
+func collectFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		if arg == "" {
+			return nil, errors.New("Please provide a file name to upload")
+		}
+		fileInfo, err := os.Stat(arg)
 		if err != nil {
-			return fmt.Errorf("error opening file %s: %w", filename, err)
+			return nil, fmt.Errorf("error reading file %s: %w", arg, err)
+		}
+		if !fileInfo.IsDir() {
+			files = append(files, arg)
+			continue
 		}
-		defer func() {
-			if err := file.Close(); err != nil {
-				log.Printf("error closing file %s: %s", filename, err)
-			}
-		}()
 
-		b, err := io.ReadAll(file)
+		err = filepath.WalkDir(arg, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// symbolic links, devices and the like are skipped, like directories
+			if entry.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("error reading file %s: %w", filename, err)
+			return nil, fmt.Errorf("error reading directory %s: %w", arg, err)
 		}
+	}
+	return files, nil
+}
+
+// putFile uploads a single file, or signs its digest with --detached, and returns its UUID
+func putFile(ctx context.Context, filename string, labels map[string]string) (string, error) {
+	// grab the full path of the file
+	fullPath, err := filepath.Abs(filename)
+	if err != nil {
+		return "", fmt.Errorf("unable to get full path of the file: %w", err)
+	}
+
+	fileInfo, err := os.Stat(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading file %s: %w", fullPath, err)
+	}
 
-		if len(b) == 0 {
-			return fmt.Errorf("file is %q empty, please provide a file with content", fullPath)
+	if fileInfo.IsDir() {
+		return "", fmt.Errorf("%q is a directory, please provide a file", fullPath)
+	}
+	// check if the fileInfo is a regular file and not a
+	if !fileInfo.Mode().IsRegular() {
+		return "", fmt.Errorf("file %s is not a regular file", fullPath)
+	}
+
+	// open the file and read its content
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("error closing file %s: %s", filename, err)
 		}
+	}()
 
-		resp, err := client.StoreBlob(cmd.Context(), &blobv1.StoreBlobRequest{
-			Blob: string(b),
-		})
+	if putDetached {
+		return signDigest(ctx, file, fileInfo)
+	}
+
+	req, err := newStoreBlobRequest(file, fileInfo, labels)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.StoreBlob(ctx, req)
 
+	if err != nil {
+		return "", fmt.Errorf("error storing blob: %w", err)
+	}
+
+	if resp == nil {
+		return "", errors.New("got empty response from the server")
+	}
+
+	return resp.GetUuid(), nil
+}
+
+// putFiles uploads the files with as few StoreBlobs requests as the limits allow, printing
+// the UUID of each file stored, and returns the number of files that failed
+func putFiles(ctx context.Context, files []string, labels map[string]string) int {
+	failed := 0
+	var (
+		batch     []string
+		req       = &blobv1.StoreBlobsRequest{}
+		batchSize int
+	)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		failed += storeBatch(ctx, batch, req)
+		batch, req, batchSize = nil, &blobv1.StoreBlobsRequest{}, 0
+	}
+
+	for _, filename := range files {
+		blob, err := readBlob(filename, labels)
 		if err != nil {
-			return fmt.Errorf("error storing blob: %w", err)
+			log.Printf("%s: %s", filename, err)
+			failed++
+			continue
+		}
+
+		if len(batch) == putBatchSize || batchSize+len(blob.Blob) > putBatchBytes {
+			flush()
 		}
+		batch = append(batch, filename)
+		req.Blobs = append(req.Blobs, blob)
+		batchSize += len(blob.Blob)
+	}
+	flush()
 
-		if resp == nil {
-			return errors.New("got empty response from the server")
+	return failed
+}
+
+// storeBatch sends a StoreBlobs request for the files and returns the number of them that failed
+func storeBatch(ctx context.Context, files []string, req *blobv1.StoreBlobsRequest) int {
+	resp, err := client.StoreBlobs(ctx, req)
+	if err == nil && len(resp.GetResults()) != len(files) {
+		err = fmt.Errorf("got %d results for %d blobs from the server", len(resp.GetResults()), len(files))
+	}
+	if err != nil {
+		for _, filename := range files {
+			log.Printf("%s: error storing blob: %s", filename, err)
 		}
+		return len(files)
+	}
+
+	failed := 0
+	for i, result := range resp.GetResults() {
+		if result.GetError() != "" {
+			log.Printf("%s: error storing blob: %s", files[i], result.GetError())
+			failed++
+			continue
+		}
+		fmt.Printf("%s: %s\n", files[i], result.GetUuid())
+	}
+	return failed
+}
 
-		log.Printf("Blob stored successfully with UUID: %s", resp.GetUuid())
+// readBlob reads a regular file into the request storing it
+func readBlob(filename string, labels map[string]string) (*blobv1.StoreBlobRequest, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("error closing file %s: %s", filename, err)
+		}
+	}()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	if !fileInfo.Mode().IsRegular() {
+		return nil, errors.New("not a regular file")
+	}
+
+	return newStoreBlobRequest(file, fileInfo, labels)
+}
 
+// newStoreBlobRequest reads the content of the file into a request to store it, with its
+// base name and media type
+func newStoreBlobRequest(file *os.File, fileInfo os.FileInfo, labels map[string]string) (*blobv1.StoreBlobRequest, error) {
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", file.Name(), err)
+	}
+
+	if len(b) == 0 {
+		return nil, fmt.Errorf("file is %q empty, please provide a file with content", file.Name())
+	}
+
+	contentType := putContentType
+	if contentType == "" {
+		contentType = detectContentType(fileInfo.Name(), b)
+	}
+
+	return &blobv1.StoreBlobRequest{
+		Blob:        string(b),
+		Ttl:         ttlOrDefault(putTTL),
+		Labels:      labels,
+		ContentType: contentType,
+		Filename:    fileInfo.Name(),
+	}, nil
+}
+
+// signDigest hashes the file locally and asks the server to sign a detached record for it.
+func signDigest(ctx context.Context, file *os.File, fileInfo os.FileInfo) (string, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return "", fmt.Errorf("error hashing file %s: %w", file.Name(), err)
+	}
+
+	resp, err := client.SignDigest(ctx, &blobv1.SignDigestRequest{
+		Sha256Digest: hex.EncodeToString(h.Sum(nil)),
+		Filename:     fileInfo.Name(),
+		Size:         size,
+		Ttl:          ttlOrDefault(putTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error signing digest: %w", err)
+	}
+
+	if resp == nil {
+		return "", errors.New("got empty response from the server")
+	}
+
+	return resp.GetUuid(), nil
+}
+
+// detectContentType returns the media type registered for the extension of the file,
+// or the one sniffed from its content
+func detectContentType(filename string, content []byte) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(content)
+}
+
+// parseLabels parses key=value flags into labels, nil when there are none
+func parseLabels(flags []string) (map[string]string, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", flag)
+		}
+		if _, exists := labels[key]; exists {
+			return nil, fmt.Errorf("label %q is given more than once", key)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// ttlOrDefault returns the requested time to live, or nil to let the server apply its default.
+func ttlOrDefault(ttl time.Duration) *durationpb.Duration {
+	if ttl == 0 {
 		return nil
-	},
+	}
+	return durationpb.New(ttl)
 }
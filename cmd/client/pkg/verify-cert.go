@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/prit342/signed-blob-service/signature"
+)
+
+// verifyCertificateChain validates the --cert-chain file against the --root-bundle as of the
+// record timestamp and returns the public key certified by the leaf certificate.
+func verifyCertificateChain(timestamp string) (crypto.PublicKey, error) {
+	signedAt, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record timestamp %q: %w", timestamp, err)
+	}
+
+	chain, err := signature.LoadCertificateChain(certChainPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rootBytes, err := os.ReadFile(rootBundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read root bundle: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootBytes) {
+		return nil, fmt.Errorf("no certificates found in root bundle %s", rootBundle)
+	}
+
+	if err := signature.VerifyCertificateChain(chain, roots, signedAt); err != nil {
+		return nil, err
+	}
+	log.Printf("✅ Certificate chain valid at %s for %s", timestamp, chain[0].Subject.String())
+
+	return chain[0].PublicKey, nil
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"crypto"
+	"fmt"
+	"log"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/verifier"
+)
+
+// checkThreshold makes sure at least verifyThreshold of the trusted keys have a valid signature
+// over the payload. The server signature counts when the server key is one of the trusted keys,
+// every other signature comes from the countersignatures in the metadata.
+func checkThreshold(
+	payload *blobv1.BlobRecord, // the signed record
+	proof *blobv1.InclusionProof, // inclusion proof of a record signed in a Merkle batch, nil otherwise
+	serverSig []byte, // the already verified server signature
+	serverKey crypto.PublicKey, // the server public key
+	countersignatures []countersignature, // countersignatures from the metadata
+) error {
+	trusted := make(map[string]crypto.PublicKey, len(trustedKeyPaths))
+	for _, path := range trustedKeyPaths {
+		publicKey, err := loadPublicKey(path)
+		if err != nil {
+			return fmt.Errorf("failed to load trusted key %s: %w", path, err)
+		}
+		trusted[signature.KeyIDForPublicKey(publicKey)] = publicKey
+	}
+
+	if verifyThreshold > len(trusted) {
+		return fmt.Errorf("threshold %d is larger than the %d distinct trusted keys", verifyThreshold, len(trusted))
+	}
+
+	// each trusted key counts once, however many signatures it has
+	signed := make(map[string]bool)
+	if serverKeyID := signature.KeyIDForPublicKey(serverKey); trusted[serverKeyID] != nil {
+		if _, err := verifier.Verify(payload, serverSig, []crypto.PublicKey{serverKey}, verifier.WithInclusionProof(proof)); err == nil {
+			signed[serverKeyID] = true
+		}
+	}
+
+	for _, cs := range countersignatures {
+		publicKey, ok := trusted[cs.KeyID]
+		if !ok {
+			log.Printf("ℹ️ Ignoring countersignature by untrusted key %s", cs.KeyID)
+			continue
+		}
+		// countersignatures cover the record itself, never a Merkle tree head
+		if _, err := verifier.Verify(payload, cs.Signature, []crypto.PublicKey{publicKey}); err != nil {
+			log.Printf("⚠️ Invalid countersignature by key %s: %v", cs.KeyID, err)
+			continue
+		}
+		signed[cs.KeyID] = true
+	}
+
+	if len(signed) < verifyThreshold {
+		return fmt.Errorf("threshold not met: %d of the %d trusted keys signed, %d required",
+			len(signed), len(trusted), verifyThreshold)
+	}
+	log.Printf("✅ Threshold met: %d of the %d trusted keys signed, %d required",
+		len(signed), len(trusted), verifyThreshold)
+
+	return nil
+}
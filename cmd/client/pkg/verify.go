@@ -1,14 +1,11 @@
 package pkg
 
 import (
+	"context"
 	"crypto"
-	"crypto/rsa"
-	"crypto/sha256"
-	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"log"
@@ -16,14 +13,22 @@ import (
 	"path/filepath"
 
 	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/verifier"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/protobuf/proto"
 )
 
 var (
-	verifyDir     string // place to look for blob files, metadata and signatures
-	publicKeyPath string // location of the public key on the disk
+	verifyDir       string   // place to look for blob files, metadata and signatures
+	publicKeyPath   string   // location of the public key on the disk
+	verifyCOSE      bool     // verify the <uuid>.cose COSE_Sign1 message instead
+	certChainPath   string   // optional PEM certificate chain certifying the signing key
+	rootBundle      string   // PEM bundle of trusted root certificates for the chain
+	contentFile     string   // original content of a detached record, or an alternative to the downloaded file
+	trustedKeyPaths []string // public keys taking part in the threshold policy
+	verifyThreshold int      // minimum number of trusted keys that must have signed
+	verifyRemote    bool     // ask the server to verify the signature instead of checking it locally
 )
 
 func init() {
@@ -31,6 +36,26 @@ func init() {
 		"Path to PEM-encoded public key file (required)")
 	verifyCommand.Flags().StringVar(&verifyDir, "dir", ".",
 		"Directory to look for blob files (default: current directory)")
+	verifyCommand.Flags().BoolVar(&verifyCOSE, "cose", false,
+		"Verify the COSE_Sign1 message in <uuid>.cose instead of the content, .sig and .meta.json files")
+	verifyCommand.Flags().StringVar(&certChainPath, "cert-chain", "",
+		"Path to the PEM certificate chain of the signing key, used instead of --public-key")
+	verifyCommand.Flags().StringVar(&rootBundle, "root-bundle", "",
+		"Path to the PEM bundle of trusted root certificates (required with --cert-chain)")
+	verifyCommand.Flags().StringVar(&contentFile, "file", "",
+		"Path to the signed content, required for detached records (default: the file saved by get)")
+	verifyCommand.Flags().StringArrayVar(&trustedKeyPaths, "trusted-key", nil,
+		"Public key (PEM or COSE_Key) taking part in the --threshold policy, repeat for each key")
+	verifyCommand.Flags().IntVar(&verifyThreshold, "threshold", 0,
+		"Minimum number of --trusted-key keys that must have signed the record (0 disables the check)")
+	verifyCommand.Flags().BoolVar(&verifyRemote, "remote", false,
+		"Ask the server to verify the signature under its keys instead of a local --public-key")
+	verifyCommand.MarkFlagsRequiredTogether("cert-chain", "root-bundle")
+	verifyCommand.MarkFlagsRequiredTogether("trusted-key", "threshold")
+	verifyCommand.MarkFlagsMutuallyExclusive("cose", "threshold")
+	verifyCommand.MarkFlagsMutuallyExclusive("remote", "cose")
+	verifyCommand.MarkFlagsMutuallyExclusive("remote", "cert-chain")
+	verifyCommand.MarkFlagsMutuallyExclusive("remote", "threshold")
 	rootCmd.AddCommand(verifyCommand)
 }
 
@@ -43,12 +68,34 @@ var verifyCommand = &cobra.Command{
 Requires the public key used by the signing service.
 
 Expected files:
-  - <uuid>.txt        : The raw blob content
+  - <filename>        : The raw blob content, saved by get under its original filename or <uuid>.txt
   - <uuid>.sig        : The base64-encoded signature
   - <uuid>.meta.json  : Metadata with UUID, hash, timestamp
 
+Detached records have no downloaded content, pass the original file with --file instead.
+Its SHA-256 digest and size must match the signed record.
+
+With --threshold N and --trusted-key for each key, at least N of the trusted keys
+must have a valid signature over the record: the server signature counts when its
+key is trusted, the others come from the countersignatures in <uuid>.meta.json.
+
+With --cose only <uuid>.cose is read, and the public key may be either
+a PEM file or a CBOR encoded COSE_Key.
+
+With --remote the hash is still checked locally, the signature is sent to the server
+which reports whether its signing key or a registered countersigning key made it.
+
+With --cert-chain and --root-bundle the signing key is taken from the leaf
+certificate instead. The chain must validate against the trusted roots and
+the record timestamp must fall within the leaf certificate validity period.
+
 Example:
   ./client verify 10315b7a... --public-key server_pub.pem --directory ./blobs
+  ./client verify 10315b7a... --file ./release.tar.gz --directory ./blobs
+  ./client verify 10315b7a... --threshold 2 --trusted-key a.pem --trusted-key b.pem --trusted-key c.pem
+  ./client verify 10315b7a... --cose --public-key server.cosekey --directory ./blobs
+  ./client verify 10315b7a... --cert-chain chain.pem --root-bundle roots.pem --directory ./blobs
+  ./client verify 10315b7a... --remote --dir ./blobs
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
@@ -65,6 +112,10 @@ Example:
 			return fmt.Errorf("%s is not a directory", stat.Name())
 		}
 
+		if verifyCOSE {
+			return verifyCOSESign1(blobUUID)
+		}
+
 		var (
 			metaFile string // file containing metadata
 			sigFile  string // file containing signature
@@ -80,11 +131,6 @@ Example:
 			return fmt.Errorf("unable to read signature file: %w", err)
 		}
 
-		blobFile, err = getAbsolutePath(verifyDir + "/" + blobUUID + ".txt")
-		if err != nil {
-			return fmt.Errorf("unable to read blob content file: %w", err)
-		}
-
 		// Read and then marashl the metadata associated with the blob
 		metaBytes, err := os.ReadFile(metaFile)
 		if err != nil {
@@ -99,6 +145,16 @@ Example:
 			return fmt.Errorf("failed to parse metadata: %w", err)
 		}
 
+		// detached records are verified against the original file, which has no default location
+		if meta.Detached && contentFile == "" {
+			return fmt.Errorf("record %s is detached, please provide the original file with --file", blobUUID)
+		}
+
+		blobFile, err = contentPath(blobUUID, &meta)
+		if err != nil {
+			return fmt.Errorf("unable to read blob content file: %w", err)
+		}
+
 		// Load the content i.e the blob that was signed
 		blobBytes, err := os.ReadFile(blobFile)
 		if err != nil {
@@ -115,62 +171,99 @@ Example:
 			return fmt.Errorf("invalid base64 in signature file: %w", err)
 		}
 
-		// Compute hash and compare
-		hash := sha256.Sum256(blobBytes)
-		computedHash := hex.EncodeToString(hash[:])
-		if computedHash != meta.Hash {
-			return fmt.Errorf("hash mismatch! Expected: %s, Computed: %s", meta.Hash, computedHash)
-		}
-		log.Printf("✅ Hash matches: %s", computedHash)
-
 		// Rebuild protobuf message
 		// this is necesarey because the server signd the byte payload of this
 		payload := &blobv1.BlobRecord{
-			Uuid:      meta.UUID,
-			Blob:      string(blobBytes),
-			Hash:      meta.Hash,
-			Timestamp: meta.TimeStamp,
+			Uuid:        meta.UUID,
+			Hash:        meta.Hash,
+			Timestamp:   meta.TimeStamp,
+			Detached:    meta.Detached,
+			Filename:    meta.Filename,
+			Size:        meta.Size,
+			ExpiresAt:   meta.ExpiresAt,
+			Labels:      meta.Labels,
+			ContentType: meta.ContentType,
 		}
-		payloadBytes, err := proto.Marshal(payload)
-		if err != nil {
-			return fmt.Errorf("failed to marshal payload for verification: %w", err)
+		if !meta.Detached { // detached records were signed without the content
+			payload.Blob = string(blobBytes)
 		}
 
-		// we assume that the argument  is a full path
-		pubBytes, err := os.ReadFile(publicKeyPath)
-		if err != nil {
-			return fmt.Errorf("failed to read public key: %w", err)
-		}
-		block, _ := pem.Decode(pubBytes)
-		if block == nil || block.Type != "PUBLIC KEY" {
-			return fmt.Errorf("invalid PEM format for public key")
+		// Compute hash and compare, and the size of detached records signed with one
+		if err := verifier.VerifyContent(payload, blobBytes); err != nil {
+			return err
 		}
-		pubInterface, err := x509.ParsePKIXPublicKey(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("failed to parse public key: %w", err)
+		log.Printf("✅ Hash matches: %s", meta.Hash)
+
+		proof := meta.InclusionProof.toProto()
+
+		if verifyRemote {
+			return verifyOnServer(cmd.Context(), payload, sig, proof)
 		}
 
-		// since we are using RSAPSS to sign, we need to read RSA public key
-		rsaPubKey, ok := pubInterface.(*rsa.PublicKey)
-		if !ok {
-			log.Fatal("public key is not RSA, We use RSAPSS")
+		var publicKey crypto.PublicKey
+		if certChainPath != "" {
+			// the key comes from the leaf certificate, valid at the time the record was signed
+			if publicKey, err = verifyCertificateChain(meta.TimeStamp); err != nil {
+				return err
+			}
+		} else {
+			// we assume that the argument  is a full path
+			pubBytes, err := os.ReadFile(publicKeyPath)
+			if err != nil {
+				return fmt.Errorf("failed to read public key: %w", err)
+			}
+			if publicKey, err = signature.ParsePublicKeyPEM(pubBytes); err != nil {
+				return err
+			}
 		}
 
-		// Verify using RSASSA-PSS
-		hashed := sha256.Sum256(payloadBytes)
-		err = rsa.VerifyPSS(rsaPubKey, crypto.SHA256, hashed[:], sig, &rsa.PSSOptions{
-			SaltLength: rsa.PSSSaltLengthEqualsHash,
-			Hash:       crypto.SHA256,
-		})
+		// any algorithm the server signs with, through the Merkle tree head for batched records
+		result, err := verifier.Verify(payload, sig, []crypto.PublicKey{publicKey}, verifier.WithInclusionProof(proof))
 		if err != nil {
-			return fmt.Errorf("signature verification failed: %v", err)
+			return err
+		}
+		if result.TreeHead != nil {
+			log.Printf("✅ Inclusion proof leads to root: %s (leaf %d of %d)",
+				hex.EncodeToString(result.TreeHead.RootHash), proof.LeafIndex, proof.TreeSize)
+		}
+		log.Printf("✅ Signature verification successful! Signed with %s", result.Algorithm)
+
+		if verifyThreshold > 0 {
+			return checkThreshold(payload, proof, sig, publicKey, meta.Countersignatures)
 		}
-		log.Println("✅ Signature verification successful!")
 
 		return nil
 	},
 }
 
+// contentPath returns the file holding the signed content, --file if given or the file get saved it to.
+func contentPath(blobUUID string, meta *metaData) (string, error) {
+	if contentFile != "" {
+		return getAbsolutePath(contentFile)
+	}
+	// get saves the content under its original filename, older clients saved it to <uuid>.txt
+	name, _ := contentFilename(blobUUID, meta.Filename, meta.ContentType)
+	path := filepath.Join(verifyDir, name)
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Join(verifyDir, blobUUID+".txt")
+	}
+	return getAbsolutePath(path)
+}
+
+// verifyOnServer asks the server to verify the signature over the record
+func verifyOnServer(ctx context.Context, payload *blobv1.BlobRecord, sig []byte, proof *blobv1.InclusionProof) error {
+	resp, err := client.VerifyBlob(ctx, &blobv1.VerifyBlobRequest{Record: payload, Signature: sig, InclusionProof: proof})
+	if err != nil {
+		return fmt.Errorf("unable to verify blob: %w", err)
+	}
+	if !resp.GetValid() {
+		return fmt.Errorf("signature verification failed: %s", resp.GetError())
+	}
+	log.Printf("✅ Signature verification successful! Signed by key: %s", resp.GetKeyId())
+
+	return nil
+}
+
 func getAbsolutePath(fileName string) (string, error) {
 	if fileName == "" {
 		return "", errors.New("empty filename passed")
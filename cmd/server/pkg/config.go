@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prit342/signed-blob-service/store"
+)
+
+// config holds the server configuration read from the environment.
+// All the variables are documented in env-local-sample.
+type config struct {
+	ListenAddr         string           // LISTEN_ADDR: address and port the gRPC server listens on
+	DatabaseURL        string           // DATABASE_URL: postgres://, sqlite:// or memory:// data source name
+	DatabaseReplicaURL string           // DATABASE_REPLICA_URL: optional PostgreSQL read replica serving read-only queries
+	DatabasePool       store.PoolConfig // DATABASE_MAX_OPEN_CONNS, DATABASE_MAX_IDLE_CONNS, DATABASE_CONN_MAX_LIFETIME, DATABASE_CONN_MAX_IDLE_TIME
+	DatabaseMigrate    bool             // DATABASE_MIGRAGE: run the database migrations on startup
+	MigrationDir       string           // MIGRATION_DIR: optional directory overriding the migrations embedded in the binary
+	Compression        store.Codec      // STORAGE_COMPRESSION: gzip or zstd compression of content kept in the database
+	EncryptionKeysPath string           // ENCRYPTION_KEYS_PATH: optional keyring of master keys encrypting content kept in the database
+	PrivateKeyPath     string           // PRIVATE_KEY_PATH: PEM-encoded private signing key
+	CertChainPath      string           // CERT_CHAIN_PATH: optional PEM bundle certifying the signing key
+	CountersignersPath string           // COUNTERSIGNER_KEYS_PATH: optional PEM bundle of countersigning public keys
+	ContentStoreURL    string           // CONTENT_STORE_URL: optional file:// or s3:// store for blob content
+	S3                 store.S3Config   // S3_ENDPOINT, S3_REGION, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_USE_SSL
+	DefaultTTL         time.Duration    // RETENTION_DEFAULT_TTL: time to live of blobs stored without one, 0 keeps them forever
+	MaxTTL             time.Duration    // RETENTION_MAX_TTL: longest time to live a client may ask for, 0 means unbounded
+	ReaperInterval     time.Duration    // REAPER_INTERVAL: how often expired blobs are deleted, 0 disables the reaper
+	ReaperBatchSize    int              // REAPER_BATCH_SIZE: expired blobs deleted per batch
+	MerkleBatchWindow  time.Duration    // MERKLE_BATCH_WINDOW: how long records wait to be signed together in a Merkle batch, 0 signs each on its own
+	MerkleBatchLeaves  int              // MERKLE_BATCH_MAX_LEAVES: most records signed in one Merkle batch
+	SignerWorkers      int              // SIGNER_WORKERS: signatures computed at once, 0 signs on the request goroutines without a limit
+	SignerQueueDepth   int              // SIGNER_QUEUE_DEPTH: requests waiting for a signer worker before new ones are rejected
+	MetricsListenAddr  string           // METRICS_LISTEN_ADDR: optional address serving Prometheus metrics on /metrics
+	AppEnv             string           // APP_ENV: "production" switches the logs to JSON
+	LogLevel           slog.Level       // LOG_LEVEL: debug, info, warn or error
+}
+
+// loadConfig reads the server configuration from the environment.
+func loadConfig() (*config, error) {
+	cfg := &config{
+		ListenAddr:         getEnv("LISTEN_ADDR", "0.0.0.0:55555"),
+		DatabaseURL:        os.Getenv("DATABASE_URL"),
+		DatabaseReplicaURL: os.Getenv("DATABASE_REPLICA_URL"),
+		PrivateKeyPath:     getEnv("PRIVATE_KEY_PATH", "./private_key.pem"),
+		CertChainPath:      os.Getenv("CERT_CHAIN_PATH"),
+		CountersignersPath: os.Getenv("COUNTERSIGNER_KEYS_PATH"),
+		EncryptionKeysPath: os.Getenv("ENCRYPTION_KEYS_PATH"),
+		ContentStoreURL:    os.Getenv("CONTENT_STORE_URL"),
+		S3: store.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		},
+		MetricsListenAddr: os.Getenv("METRICS_LISTEN_ADDR"),
+		AppEnv:            getEnv("APP_ENV", "development"),
+	}
+
+	if cfg.DatabaseURL == "" {
+		return nil, errors.New("DATABASE_URL must be set")
+	}
+
+	backend, err := store.BackendForDSN(cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+	// the migrations of the backend are embedded in the binary, the directory only overrides them
+	cfg.MigrationDir = getEnv("MIGRATION_DIR", "")
+
+	// only PostgreSQL has read replicas
+	if cfg.DatabaseReplicaURL != "" {
+		if backend != store.BackendPostgres {
+			return nil, errors.New("DATABASE_REPLICA_URL requires a postgres:// DATABASE_URL")
+		}
+		if replicaBackend, err := store.BackendForDSN(cfg.DatabaseReplicaURL); err != nil || replicaBackend != store.BackendPostgres {
+			return nil, errors.New("invalid DATABASE_REPLICA_URL, expected a postgres:// URL")
+		}
+	}
+
+	// the content store writes the content as it is, encrypting only the database would leave it in the clear
+	if cfg.EncryptionKeysPath != "" && cfg.ContentStoreURL != "" {
+		return nil, errors.New("ENCRYPTION_KEYS_PATH cannot be combined with CONTENT_STORE_URL, the content store does not encrypt content")
+	}
+
+	if cfg.DatabasePool.MaxOpenConns, err = strconv.Atoi(getEnv("DATABASE_MAX_OPEN_CONNS", "25")); err != nil || cfg.DatabasePool.MaxOpenConns < 0 {
+		return nil, fmt.Errorf("invalid DATABASE_MAX_OPEN_CONNS value %q, must be a non-negative integer", os.Getenv("DATABASE_MAX_OPEN_CONNS"))
+	}
+
+	if cfg.DatabasePool.MaxIdleConns, err = strconv.Atoi(getEnv("DATABASE_MAX_IDLE_CONNS", "10")); err != nil || cfg.DatabasePool.MaxIdleConns < 0 {
+		return nil, fmt.Errorf("invalid DATABASE_MAX_IDLE_CONNS value %q, must be a non-negative integer", os.Getenv("DATABASE_MAX_IDLE_CONNS"))
+	}
+
+	if cfg.DatabasePool.ConnMaxLifetime, err = time.ParseDuration(getEnv("DATABASE_CONN_MAX_LIFETIME", "30m")); err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_CONN_MAX_LIFETIME value: %w", err)
+	}
+
+	if cfg.DatabasePool.ConnMaxIdleTime, err = time.ParseDuration(getEnv("DATABASE_CONN_MAX_IDLE_TIME", "5m")); err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_CONN_MAX_IDLE_TIME value: %w", err)
+	}
+
+	if cfg.Compression, err = store.ParseCodec(os.Getenv("STORAGE_COMPRESSION")); err != nil {
+		return nil, fmt.Errorf("invalid STORAGE_COMPRESSION value: %w", err)
+	}
+
+	if cfg.DatabaseMigrate, err = strconv.ParseBool(getEnv("DATABASE_MIGRAGE", "false")); err != nil {
+		return nil, fmt.Errorf("invalid DATABASE_MIGRAGE value: %w", err)
+	}
+
+	if cfg.S3.UseSSL, err = strconv.ParseBool(getEnv("S3_USE_SSL", "true")); err != nil {
+		return nil, fmt.Errorf("invalid S3_USE_SSL value: %w", err)
+	}
+
+	if cfg.DefaultTTL, err = time.ParseDuration(getEnv("RETENTION_DEFAULT_TTL", "0")); err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_DEFAULT_TTL value: %w", err)
+	}
+
+	if cfg.MaxTTL, err = time.ParseDuration(getEnv("RETENTION_MAX_TTL", "0")); err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_MAX_TTL value: %w", err)
+	}
+
+	if cfg.ReaperInterval, err = time.ParseDuration(getEnv("REAPER_INTERVAL", "1m")); err != nil {
+		return nil, fmt.Errorf("invalid REAPER_INTERVAL value: %w", err)
+	}
+
+	if cfg.ReaperBatchSize, err = strconv.Atoi(getEnv("REAPER_BATCH_SIZE", "100")); err != nil || cfg.ReaperBatchSize <= 0 {
+		return nil, fmt.Errorf("invalid REAPER_BATCH_SIZE value %q, must be a positive integer", os.Getenv("REAPER_BATCH_SIZE"))
+	}
+
+	if cfg.MerkleBatchWindow, err = time.ParseDuration(getEnv("MERKLE_BATCH_WINDOW", "0")); err != nil || cfg.MerkleBatchWindow < 0 {
+		return nil, fmt.Errorf("invalid MERKLE_BATCH_WINDOW value %q, must be a non-negative duration", os.Getenv("MERKLE_BATCH_WINDOW"))
+	}
+
+	if cfg.MerkleBatchLeaves, err = strconv.Atoi(getEnv("MERKLE_BATCH_MAX_LEAVES", "1024")); err != nil || cfg.MerkleBatchLeaves <= 0 {
+		return nil, fmt.Errorf("invalid MERKLE_BATCH_MAX_LEAVES value %q, must be a positive integer", os.Getenv("MERKLE_BATCH_MAX_LEAVES"))
+	}
+
+	if cfg.SignerWorkers, err = strconv.Atoi(getEnv("SIGNER_WORKERS", strconv.Itoa(runtime.GOMAXPROCS(0)))); err != nil || cfg.SignerWorkers < 0 {
+		return nil, fmt.Errorf("invalid SIGNER_WORKERS value %q, must be a non-negative integer", os.Getenv("SIGNER_WORKERS"))
+	}
+
+	if cfg.SignerQueueDepth, err = strconv.Atoi(getEnv("SIGNER_QUEUE_DEPTH", "1024")); err != nil || cfg.SignerQueueDepth < 0 {
+		return nil, fmt.Errorf("invalid SIGNER_QUEUE_DEPTH value %q, must be a non-negative integer", os.Getenv("SIGNER_QUEUE_DEPTH"))
+	}
+
+	if err := cfg.LogLevel.UnmarshalText([]byte(getEnv("LOG_LEVEL", "info"))); err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL value: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// getEnv returns the trimmed value of the environment variable or the fallback when it is unset.
+func getEnv(key string, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/prit342/signed-blob-service/logger"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manages the database schema migrations",
+	Long: `Applies, reverts and inspects the database schema migrations embedded in the binary,
+or those in MIGRATION_DIR when it is set, for the database in DATABASE_URL. The server refuses to start on a schema that is dirty
+or newer than it supports, these commands are the way to fix it.`,
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:          "up",
+	SilenceUsage: true,
+	Short:        "Applies all the pending migrations",
+	Args:         cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd.Context(), func(m *store.Migrator) error {
+			return m.Up()
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:          "down <steps>",
+	SilenceUsage: true,
+	Short:        "Reverts the last <steps> migrations",
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		steps, err := strconv.Atoi(args[0])
+		if err != nil || steps <= 0 {
+			return fmt.Errorf("invalid number of steps %q, must be a positive integer", args[0])
+		}
+		return withMigrator(cmd.Context(), func(m *store.Migrator) error {
+			return m.Down(steps)
+		})
+	},
+}
+
+var migrateGotoCmd = &cobra.Command{
+	Use:          "goto <version>",
+	SilenceUsage: true,
+	Short:        "Migrates up or down to <version>",
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[0], err)
+		}
+		return withMigrator(cmd.Context(), func(m *store.Migrator) error {
+			return m.Goto(uint(version))
+		})
+	},
+}
+
+var migrateForceCmd = &cobra.Command{
+	Use:          "force <version>",
+	SilenceUsage: true,
+	Short:        "Records <version> as applied and clears the dirty flag, without running any migration",
+	Long: `Records <version> as the applied schema version and clears the dirty flag, without running
+any migration. Use it once the schema left behind by a failed migration has been fixed by hand.
+Version -1 records that no migration has been applied.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			return fmt.Errorf("invalid version %q, must be -1 or more", args[0])
+		}
+		return withMigrator(cmd.Context(), func(m *store.Migrator) error {
+			return m.Force(version)
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:          "status",
+	SilenceUsage: true,
+	Short:        "Prints the schema version of the database",
+	Args:         cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withMigrator(cmd.Context(), func(m *store.Migrator) error {
+			status, err := m.Status()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("source:    %s\n", status.Source)
+			fmt.Printf("version:   %d\n", status.Version)
+			fmt.Printf("dirty:     %t\n", status.Dirty)
+			fmt.Printf("latest:    %d\n", status.Latest)
+			fmt.Printf("supported: %d\n", status.Supported)
+			if status.Version < status.Latest {
+				fmt.Printf("%d migrations pending\n", status.Latest-status.Version)
+			}
+			return nil
+		})
+	},
+}
+
+// withMigrator runs fn with a migrator for the configured database and migration directory
+func withMigrator(ctx context.Context, fn func(m *store.Migrator) error) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	log := logger.NewLogger(applicationName, os.Stderr, cfg.LogLevel, version, cfg.AppEnv)
+
+	storage, err := openDatabase(cfg, log)
+	if err != nil {
+		return err
+	}
+	migratable, ok := storage.(store.Migratable)
+	if !ok {
+		return errors.New("the storage has no database schema to migrate")
+	}
+
+	m, err := migratable.Migrator(ctx, cfg.MigrationDir)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return fn(m)
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateGotoCmd, migrateForceCmd, migrateStatusCmd)
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/prit342/signed-blob-service/logger"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rewrapBatchSize int // data keys re-wrapped per batch
+)
+
+var rewrapKeysCmd = &cobra.Command{
+	Use:          "rewrap-keys",
+	SilenceUsage: true,
+	Short:        "Re-wraps the data keys of encrypted blobs under the active master key",
+	Long: `Re-wraps the data key of every encrypted blob that is still wrapped by a previous master key
+with the active master key, the first key of ENCRYPTION_KEYS_PATH. The blob content is not
+re-encrypted. Once it completes, the previous master keys can be removed from the keyring.
+
+It is safe to run while servers are serving requests and to interrupt, a new run carries on.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if cfg.EncryptionKeysPath == "" {
+			return errors.New("ENCRYPTION_KEYS_PATH must be set")
+		}
+		if rewrapBatchSize <= 0 {
+			return errors.New("batch size must be positive")
+		}
+		log := logger.NewLogger(applicationName, os.Stdout, cfg.LogLevel, version, cfg.AppEnv)
+
+		storage, err := openDatabase(cfg, log)
+		if err != nil {
+			return err
+		}
+		rewrapper, ok := storage.(store.KeyRewrapper)
+		if !ok {
+			return fmt.Errorf("the %T storage does not encrypt blob content", storage)
+		}
+
+		total := 0
+		for {
+			rewrapped, err := rewrapper.RewrapKeys(cmd.Context(), rewrapBatchSize)
+			total += rewrapped
+			if err != nil {
+				return fmt.Errorf("failed to re-wrap data keys after %d blobs: %w", total, err)
+			}
+			if rewrapped == 0 {
+				break
+			}
+			log.Info("re-wrapped data keys", "count", rewrapped, "total", total)
+		}
+
+		log.Info("all data keys are wrapped by the active master key", "rewrapped", total)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rewrapKeysCmd)
+	rewrapKeysCmd.Flags().IntVar(&rewrapBatchSize, "batch-size", 100, "number of data keys re-wrapped per batch")
+}
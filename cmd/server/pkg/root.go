@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envFile string // optional .env file loaded before reading the configuration
+)
+
+var rootCmd = &cobra.Command{
+	Use:          "sign-blob-service",
+	SilenceUsage: true,
+	Short:        "The gRPC server of the Signed Blob Service",
+	Long: `Runs the gRPC server that signs, stores and serves blobs.
+
+The configuration is read from environment variables, see env-local-sample.
+Variables already set in the environment take precedence over the .env file.`,
+
+	// load the .env file before any subcommand reads the configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := godotenv.Load(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to load env file %q: %w", envFile, err)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		return runServer(cmd.Context(), cfg)
+	},
+}
+
+// ExecuteWithContext - run the root command with context.
+func ExecuteWithContext(ctx context.Context) error {
+	return rootCmd.ExecuteContext(ctx)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&envFile, "env-file", ".env", "path to the .env file to load (ignored if missing)")
+}
@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	apiv1 "github.com/prit342/signed-blob-service/api/v1"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/logger"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+const (
+	applicationName = "sign-blob-server"
+	// the database is expected to be ready within dbMaxReadyDuration
+	dbRetryInterval    = 2 * time.Second
+	dbMaxReadyDuration = 1 * time.Minute
+)
+
+// version is set at build time with -ldflags "-X .../cmd/server/pkg.version=..."
+var version = "dev"
+
+// openDatabase connects to the database storage with the configured pool, replica, compression and encryption
+func openDatabase(cfg *config, log *slog.Logger) (store.Storage, error) {
+	opts := []store.Option{
+		store.WithPool(cfg.DatabasePool),
+		store.WithCompression(cfg.Compression),
+	}
+	if cfg.DatabaseReplicaURL != "" {
+		opts = append(opts, store.WithReadReplica(cfg.DatabaseReplicaURL))
+		log.Info("sending read-only queries to the read replica")
+	}
+	if cfg.Compression != store.CodecNone {
+		log.Info("compressing blob content in the database", "codec", cfg.Compression)
+	}
+
+	if cfg.EncryptionKeysPath != "" {
+		keyring, err := store.LoadKeyring(cfg.EncryptionKeysPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+		}
+		opts = append(opts, store.WithEncryption(keyring))
+		log.Info("encrypting blob content in the database", "master_key_id", keyring.ActiveKeyID())
+	}
+
+	storage, err := store.NewStorage(cfg.DatabaseURL, log, dbRetryInterval, dbMaxReadyDuration, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise storage: %w", err)
+	}
+	return storage, nil
+}
+
+// runServer wires the storage, signer and service together and serves gRPC until the context is cancelled.
+func runServer(ctx context.Context, cfg *config) error {
+	log := logger.NewLogger(applicationName, os.Stdout, cfg.LogLevel, version, cfg.AppEnv)
+
+	storage, err := openDatabase(cfg, log)
+	if err != nil {
+		return err
+	}
+	// the connections are closed once the server has stopped, the content store wrapper has none of its own
+	if closer, ok := storage.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				log.Error("failed to close storage", "error", err)
+			}
+		}()
+	}
+
+	// a schema migrated by a newer release may have changed under our queries
+	if migratable, ok := storage.(store.Migratable); ok {
+		if err := migratable.CheckSchema(ctx); err != nil {
+			return fmt.Errorf("refusing to serve: %w", err)
+		}
+	}
+
+	if cfg.DatabaseMigrate {
+		if err := storage.Migrate(ctx, cfg.MigrationDir); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	if cfg.ContentStoreURL != "" {
+		// the database keeps only metadata and signatures, the content goes to the content store
+		content, err := store.NewContentStore(cfg.ContentStoreURL, cfg.S3)
+		if err != nil {
+			return fmt.Errorf("failed to initialise content store: %w", err)
+		}
+		if storage, err = store.NewContentAddressedStorage(storage, content, log); err != nil {
+			return fmt.Errorf("failed to initialise storage: %w", err)
+		}
+		log.Info("storing blob content outside the database", "content_store", cfg.ContentStoreURL)
+	}
+
+	signer, err := signature.NewSignerFromFile(cfg.PrivateKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load signing key: %w", err)
+	}
+	log.Info("loaded signing key", "algorithm", signer.Algorithm(), "key_id", signer.KeyID())
+
+	// bound the concurrent private key operations, a burst of uploads would otherwise starve the server
+	if cfg.SignerWorkers > 0 {
+		pool, err := signature.NewPool(signer, cfg.SignerWorkers, cfg.SignerQueueDepth)
+		if err != nil {
+			return fmt.Errorf("failed to start signer pool: %w", err)
+		}
+		defer pool.Close()
+		signer = pool
+		log.Info("signer pool started", "workers", cfg.SignerWorkers, "queue_depth", cfg.SignerQueueDepth)
+	}
+
+	var opts []apiv1.Option
+	if cfg.CertChainPath != "" {
+		chain, err := signature.LoadCertificateChain(cfg.CertChainPath)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate chain: %w", err)
+		}
+		opts = append(opts, apiv1.WithCertificateChain(chain))
+		log.Info("loaded certificate chain", "subject", chain[0].Subject.String(),
+			"not_after", chain[0].NotAfter)
+	}
+
+	if cfg.CountersignersPath != "" {
+		keys, err := signature.LoadPublicKeys(cfg.CountersignersPath)
+		if err != nil {
+			return fmt.Errorf("failed to load countersigner keys: %w", err)
+		}
+		opts = append(opts, apiv1.WithCountersignerKeys(keys))
+		log.Info("loaded countersigner keys", "count", len(keys))
+	}
+
+	if cfg.DefaultTTL > 0 || cfg.MaxTTL > 0 {
+		opts = append(opts, apiv1.WithRetentionPolicy(cfg.DefaultTTL, cfg.MaxTTL))
+		log.Info("retention policy enabled", "default_ttl", cfg.DefaultTTL, "max_ttl", cfg.MaxTTL)
+	}
+
+	if cfg.MerkleBatchWindow > 0 {
+		opts = append(opts, apiv1.WithMerkleBatching(cfg.MerkleBatchWindow, cfg.MerkleBatchLeaves))
+		log.Info("Merkle batch signing enabled", "window", cfg.MerkleBatchWindow, "max_leaves", cfg.MerkleBatchLeaves)
+	}
+
+	service, err := apiv1.NewService(log, storage, signer, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddr, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	blobv1.RegisterBlobServiceServer(grpcServer, service)
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		log.Info("gRPC server listening", "address", cfg.ListenAddr)
+		return grpcServer.Serve(listener)
+	})
+
+	g.Go(func() error {
+		<-ctx.Done()
+		log.Info("shutting down gRPC server")
+		grpcServer.GracefulStop()
+		return nil
+	})
+
+	if cfg.ReaperInterval > 0 {
+		g.Go(func() error {
+			log.Info("deleting expired blobs", "interval", cfg.ReaperInterval, "batch_size", cfg.ReaperBatchSize)
+			return service.RunReaper(ctx, cfg.ReaperInterval, cfg.ReaperBatchSize)
+		})
+	}
+
+	if cfg.MetricsListenAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.MetricsListenAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		g.Go(func() error {
+			log.Info("metrics server listening", "address", cfg.MetricsListenAddr)
+			if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("metrics server failed: %w", err)
+			}
+			return nil
+		})
+
+		g.Go(func() error {
+			<-ctx.Done()
+			return metricsServer.Shutdown(context.Background())
+		})
+	}
+
+	return g.Wait()
+}
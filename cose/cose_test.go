@@ -0,0 +1,81 @@
+package cose
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/signature/signaturetest"
+)
+
+func TestSign1AndKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		algorithm   signature.Algorithm
+		expectedAlg int64
+	}{
+		{name: "RSA signer uses PS256", algorithm: signature.AlgorithmRSAPSSSHA256, expectedAlg: AlgorithmPS256},
+		{name: "ECDSA signer uses ES256", algorithm: signature.AlgorithmECDSAP256SHA256, expectedAlg: AlgorithmES256},
+		{name: "Ed25519 signer uses EdDSA", algorithm: signature.AlgorithmEd25519, expectedAlg: AlgorithmEdDSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			signer := signaturetest.NewSignerFor(t, tt.algorithm)
+			payload := []byte{0x0a, 0x05, 'h', 'e', 'l', 'l', 'o'}
+
+			msg, err := Sign1(signer, payload)
+			if err != nil {
+				t.Fatalf("failed to sign COSE_Sign1: %v", err)
+			}
+
+			// verify using the public key decoded from the COSE_Key
+			keyBytes, err := SignerKey(signer)
+			if err != nil {
+				t.Fatalf("failed to encode COSE_Key: %v", err)
+			}
+			pub, kid, err := DecodeKey(keyBytes)
+			if err != nil {
+				t.Fatalf("failed to decode COSE_Key: %v", err)
+			}
+			if kid != signer.KeyID() {
+				t.Fatalf("expected kid %q but got %q", signer.KeyID(), kid)
+			}
+
+			header, got, err := VerifySign1(msg, pub)
+			if err != nil {
+				t.Fatalf("failed to verify COSE_Sign1: %v", err)
+			}
+			if header.Algorithm != tt.expectedAlg {
+				t.Fatalf("expected alg %d but got %d", tt.expectedAlg, header.Algorithm)
+			}
+			if header.KeyID != signer.KeyID() {
+				t.Fatalf("expected kid %q but got %q", signer.KeyID(), header.KeyID)
+			}
+			if string(got) != string(payload) {
+				t.Fatalf("payload mismatch: %x", got)
+			}
+
+			// replacing the payload must invalidate the signature
+			var tag cbor.RawTag
+			if err := cbor.Unmarshal(msg, &tag); err != nil {
+				t.Fatalf("failed to decode COSE_Sign1 tag: %v", err)
+			}
+			var decoded sign1Message
+			if err := cbor.Unmarshal(tag.Content, &decoded); err != nil {
+				t.Fatalf("failed to decode COSE_Sign1: %v", err)
+			}
+			decoded.Payload = []byte("tampered")
+			tampered, err := cbor.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("failed to encode tampered COSE_Sign1: %v", err)
+			}
+			if _, _, err := VerifySign1(tampered, pub); err == nil {
+				t.Fatal("expected verification of a tampered COSE_Sign1 to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package cose
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/prit342/signed-blob-service/signature"
+)
+
+// COSE_Key common parameter labels
+const (
+	keyLabelKeyType   int64 = 1
+	keyLabelKeyID     int64 = 2
+	keyLabelAlgorithm int64 = 3
+)
+
+// COSE_Key type specific parameter labels. RSA keys use -1 and -2 for the modulus and exponent,
+// EC2 and OKP keys use -1 for the curve and -2 (and -3 for EC2) for the coordinates.
+const (
+	keyLabelParam1 int64 = -1
+	keyLabelParam2 int64 = -2
+	keyLabelParam3 int64 = -3
+)
+
+// COSE key types
+const (
+	keyTypeOKP int64 = 1
+	keyTypeEC2 int64 = 2
+	keyTypeRSA int64 = 3
+)
+
+// COSE elliptic curves
+const (
+	curveP256    int64 = 1
+	curveEd25519 int64 = 6
+)
+
+// p256CoordinateSize is the size in bytes of each P-256 coordinate in a COSE_Key.
+const p256CoordinateSize = 32
+
+// EncodeKey returns the CBOR encoded COSE_Key for the given public key.
+// kid is the hex-encoded key ID, stored as raw bytes in the key.
+func EncodeKey(publicKey crypto.PublicKey, kid string) ([]byte, error) {
+	keyAlg, err := signature.AlgorithmForPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := AlgorithmFor(keyAlg)
+	if err != nil {
+		return nil, err
+	}
+
+	key := map[int64]any{
+		keyLabelAlgorithm: alg,
+	}
+	if kid != "" {
+		kidBytes, err := hex.DecodeString(kid)
+		if err != nil {
+			return nil, fmt.Errorf("key ID is not hex encoded: %w", err)
+		}
+		key[keyLabelKeyID] = kidBytes
+	}
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		key[keyLabelKeyType] = keyTypeRSA
+		key[keyLabelParam1] = pub.N.Bytes()
+		key[keyLabelParam2] = big.NewInt(int64(pub.E)).Bytes()
+	case *ecdsa.PublicKey:
+		// coordinates are encoded as fixed size big-endian integers
+		x := make([]byte, p256CoordinateSize)
+		y := make([]byte, p256CoordinateSize)
+		pub.X.FillBytes(x)
+		pub.Y.FillBytes(y)
+		key[keyLabelKeyType] = keyTypeEC2
+		key[keyLabelParam1] = curveP256
+		key[keyLabelParam2] = x
+		key[keyLabelParam3] = y
+	case ed25519.PublicKey:
+		key[keyLabelKeyType] = keyTypeOKP
+		key[keyLabelParam1] = curveEd25519
+		key[keyLabelParam2] = []byte(pub)
+	}
+
+	b, err := encMode.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal COSE_Key: %w", err)
+	}
+	return b, nil
+}
+
+// DecodeKey parses a CBOR encoded COSE_Key and returns the public key and its hex-encoded key ID.
+func DecodeKey(data []byte) (crypto.PublicKey, string, error) {
+	var key map[int64]cbor.RawMessage
+	if err := decMode.Unmarshal(data, &key); err != nil {
+		return nil, "", fmt.Errorf("invalid COSE_Key: %w", err)
+	}
+
+	var kty int64
+	if err := decMode.Unmarshal(key[keyLabelKeyType], &kty); err != nil {
+		return nil, "", fmt.Errorf("invalid COSE_Key key type: %w", err)
+	}
+
+	var kid string
+	if raw, ok := key[keyLabelKeyID]; ok {
+		var kidBytes []byte
+		if err := decMode.Unmarshal(raw, &kidBytes); err != nil {
+			return nil, "", fmt.Errorf("invalid COSE_Key kid: %w", err)
+		}
+		kid = hex.EncodeToString(kidBytes)
+	}
+
+	bytesParam := func(label int64) ([]byte, error) {
+		var b []byte
+		if err := decMode.Unmarshal(key[label], &b); err != nil {
+			return nil, fmt.Errorf("invalid COSE_Key parameter %d: %w", label, err)
+		}
+		return b, nil
+	}
+	curveParam := func() (int64, error) {
+		var crv int64
+		if err := decMode.Unmarshal(key[keyLabelParam1], &crv); err != nil {
+			return 0, fmt.Errorf("invalid COSE_Key curve: %w", err)
+		}
+		return crv, nil
+	}
+
+	switch kty {
+	case keyTypeRSA:
+		n, err := bytesParam(keyLabelParam1)
+		if err != nil {
+			return nil, "", err
+		}
+		e, err := bytesParam(keyLabelParam2)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(n) == 0 || len(e) == 0 {
+			return nil, "", errors.New("RSA COSE_Key is missing its modulus or exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, kid, nil
+	case keyTypeEC2:
+		crv, err := curveParam()
+		if err != nil {
+			return nil, "", err
+		}
+		if crv != curveP256 {
+			return nil, "", fmt.Errorf("unsupported EC2 curve %d", crv)
+		}
+		x, err := bytesParam(keyLabelParam2)
+		if err != nil {
+			return nil, "", err
+		}
+		y, err := bytesParam(keyLabelParam3)
+		if err != nil {
+			return nil, "", err
+		}
+		// validate that the point is on the curve before using it
+		point := append([]byte{0x04}, append(x, y...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, "", fmt.Errorf("invalid EC2 public key: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, kid, nil
+	case keyTypeOKP:
+		crv, err := curveParam()
+		if err != nil {
+			return nil, "", err
+		}
+		if crv != curveEd25519 {
+			return nil, "", fmt.Errorf("unsupported OKP curve %d", crv)
+		}
+		x, err := bytesParam(keyLabelParam2)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, "", errors.New("invalid Ed25519 public key length")
+		}
+		return ed25519.PublicKey(x), kid, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported COSE key type %d", kty)
+	}
+}
+
+// SignerKey returns the CBOR encoded COSE_Key of the signer's public key.
+func SignerKey(signer signature.Signer) ([]byte, error) {
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+
+	pemBytes, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve public key: %w", err)
+	}
+
+	publicKey, err := signature.ParsePublicKeyPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodeKey(publicKey, signer.KeyID())
+}
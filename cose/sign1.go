@@ -0,0 +1,214 @@
+// Package cose encodes signed blob records as COSE_Sign1 messages and public keys
+// as COSE_Key structures (RFC 9052 and RFC 9053). CBOR is far more compact than
+// the JSON and base64 files written by the client, which suits constrained devices.
+package cose
+
+import (
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/prit342/signed-blob-service/signature"
+)
+
+// COSE algorithm identifiers (RFC 9053 and RFC 8230)
+const (
+	AlgorithmES256 int64 = -7
+	AlgorithmEdDSA int64 = -8
+	AlgorithmPS256 int64 = -37
+)
+
+// COSE header labels used by the service
+const (
+	headerLabelAlgorithm int64 = 1
+	headerLabelKeyID     int64 = 4
+)
+
+// sign1Tag is the CBOR tag identifying a COSE_Sign1 message.
+const sign1Tag = 18
+
+// sign1Context is the context string of the Sig_structure for COSE_Sign1.
+const sign1Context = "Signature1"
+
+// Header is the protected header of the COSE_Sign1 messages produced by the service.
+type Header struct {
+	Algorithm int64  // COSE algorithm identifier
+	KeyID     string // hex-encoded key ID, the kid header carries the raw bytes
+}
+
+// sign1Message is the COSE_Sign1 array: [protected, unprotected, payload, signature].
+type sign1Message struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected map[int64]any
+	Payload     []byte
+	Signature   []byte
+}
+
+var (
+	// encMode uses the core deterministic encoding so the same input always yields the same bytes
+	encMode cbor.EncMode
+	// decMode rejects duplicate map keys to avoid ambiguous headers
+	decMode cbor.DecMode
+)
+
+func init() {
+	var err error
+	if encMode, err = cbor.CoreDetEncOptions().EncMode(); err != nil {
+		panic(fmt.Sprintf("failed to create CBOR encoding mode: %v", err))
+	}
+	if decMode, err = (cbor.DecOptions{DupMapKey: cbor.DupMapKeyEnforcedAPF}).DecMode(); err != nil {
+		panic(fmt.Sprintf("failed to create CBOR decoding mode: %v", err))
+	}
+}
+
+// AlgorithmFor maps a signer algorithm to its COSE algorithm identifier.
+func AlgorithmFor(alg signature.Algorithm) (int64, error) {
+	switch alg {
+	case signature.AlgorithmRSAPSSSHA256:
+		return AlgorithmPS256, nil
+	case signature.AlgorithmECDSAP256SHA256:
+		return AlgorithmES256, nil
+	case signature.AlgorithmEd25519:
+		return AlgorithmEdDSA, nil
+	default:
+		return 0, fmt.Errorf("signature algorithm %q has no COSE equivalent", alg)
+	}
+}
+
+// Sign1 signs the payload with the given signer and returns a tagged COSE_Sign1 message.
+// The protected header carries the COSE algorithm matching the signer and its key ID.
+func Sign1(signer signature.Signer, payload []byte) ([]byte, error) {
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+
+	alg, err := AlgorithmFor(signer.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := hex.DecodeString(signer.KeyID())
+	if err != nil {
+		return nil, fmt.Errorf("signer key ID is not hex encoded: %w", err)
+	}
+
+	protected, err := encMode.Marshal(map[int64]any{
+		headerLabelAlgorithm: alg,
+		headerLabelKeyID:     kid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal COSE protected header: %w", err)
+	}
+
+	toBeSigned, err := sigStructure(protected, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := signer.Sign(toBeSigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign COSE_Sign1: %w", err)
+	}
+
+	// COSE uses the raw R || S form for ECDSA rather than ASN.1 DER
+	if alg == AlgorithmES256 {
+		if sig, err = signature.ECDSASignatureToRaw(sig); err != nil {
+			return nil, err
+		}
+	}
+
+	msg, err := encMode.Marshal(cbor.Tag{
+		Number: sign1Tag,
+		Content: sign1Message{
+			Protected:   protected,
+			Unprotected: map[int64]any{},
+			Payload:     payload,
+			Signature:   sig,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal COSE_Sign1: %w", err)
+	}
+
+	return msg, nil
+}
+
+// VerifySign1 verifies a tagged or untagged COSE_Sign1 message against the given public key
+// and returns its protected header and payload.
+func VerifySign1(data []byte, publicKey crypto.PublicKey) (*Header, []byte, error) {
+	var msg sign1Message
+
+	// the COSE_Sign1 tag is optional when the type is known from context
+	var tag cbor.RawTag
+	if err := decMode.Unmarshal(data, &tag); err == nil {
+		if tag.Number != sign1Tag {
+			return nil, nil, fmt.Errorf("unexpected CBOR tag %d, expected COSE_Sign1", tag.Number)
+		}
+		data = tag.Content
+	}
+	if err := decMode.Unmarshal(data, &msg); err != nil {
+		return nil, nil, fmt.Errorf("invalid COSE_Sign1 message: %w", err)
+	}
+
+	var headers map[int64]cbor.RawMessage
+	if err := decMode.Unmarshal(msg.Protected, &headers); err != nil {
+		return nil, nil, fmt.Errorf("invalid COSE protected header: %w", err)
+	}
+
+	header := &Header{}
+	if err := decMode.Unmarshal(headers[headerLabelAlgorithm], &header.Algorithm); err != nil {
+		return nil, nil, fmt.Errorf("invalid COSE algorithm header: %w", err)
+	}
+	if raw, ok := headers[headerLabelKeyID]; ok {
+		var kid []byte
+		if err := decMode.Unmarshal(raw, &kid); err != nil {
+			return nil, nil, fmt.Errorf("invalid COSE kid header: %w", err)
+		}
+		header.KeyID = hex.EncodeToString(kid)
+	}
+
+	// the algorithm in the header must match the key, never trust the header alone
+	keyAlg, err := signature.AlgorithmForPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	expectedAlg, err := AlgorithmFor(keyAlg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Algorithm != expectedAlg {
+		return nil, nil, fmt.Errorf("COSE algorithm %d does not match the public key algorithm %d",
+			header.Algorithm, expectedAlg)
+	}
+
+	sig := msg.Signature
+	if header.Algorithm == AlgorithmES256 {
+		if sig, err = signature.ECDSASignatureFromRaw(sig); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	toBeSigned, err := sigStructure(msg.Protected, msg.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := signature.VerifyWithPublicKey(publicKey, toBeSigned, sig); err != nil {
+		return nil, nil, fmt.Errorf("COSE_Sign1 signature verification failed: %w", err)
+	}
+
+	return header, msg.Payload, nil
+}
+
+// sigStructure builds the CBOR encoded Sig_structure that is signed for a COSE_Sign1 message:
+// ["Signature1", protected, external_aad, payload]. The service never uses external AAD.
+func sigStructure(protected []byte, payload []byte) ([]byte, error) {
+	b, err := encMode.Marshal([]any{sign1Context, protected, []byte{}, payload})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal COSE Sig_structure: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,10 @@
+// Package dbmigrations embeds the SQL migration files of the database storage backends,
+// one directory per backend, so that the server binary always carries the migrations it was built with.
+package dbmigrations
+
+import "embed"
+
+// FS holds the postgres and sqlite migration directories
+//
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
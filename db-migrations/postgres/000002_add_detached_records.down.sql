@@ -0,0 +1,4 @@
+ALTER TABLE signed_blobs
+    DROP COLUMN IF EXISTS size,
+    DROP COLUMN IF EXISTS filename,
+    DROP COLUMN IF EXISTS detached;
@@ -0,0 +1,5 @@
+-- Detached records sign a digest supplied by the client, the content itself is not stored
+ALTER TABLE signed_blobs
+    ADD COLUMN IF NOT EXISTS detached BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS filename TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS size BIGINT NOT NULL DEFAULT 0;
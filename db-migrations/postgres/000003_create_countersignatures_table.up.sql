@@ -0,0 +1,10 @@
+-- Countersignatures are signatures by other parties over the same signed payload
+CREATE TABLE IF NOT EXISTS countersignatures (
+    uuid UUID NOT NULL REFERENCES signed_blobs(uuid) ON DELETE CASCADE,
+    -- hex SHA-256 of the DER-encoded countersigning public key
+    key_id VARCHAR(64) NOT NULL,
+    signature BYTEA NOT NULL,
+    timestamp TEXT NOT NULL,
+    -- each key countersigns a record at most once
+    PRIMARY KEY (uuid, key_id)
+);
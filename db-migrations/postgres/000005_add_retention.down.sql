@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tombstones;
+DROP INDEX IF EXISTS idx_signed_blobs_expires_at;
+ALTER TABLE signed_blobs DROP COLUMN IF EXISTS expires_at;
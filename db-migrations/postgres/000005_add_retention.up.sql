@@ -0,0 +1,15 @@
+-- Time the record expires at, in the same RFC3339 format as the timestamp, empty when it is kept forever.
+-- The format sorts chronologically, so expired records are found with a string comparison.
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS expires_at TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_expires_at ON signed_blobs(expires_at) WHERE expires_at <> '';
+
+-- Tombstones are the signed proof left behind once an expired record has been deleted
+CREATE TABLE IF NOT EXISTS tombstones (
+    uuid UUID PRIMARY KEY,
+    hash VARCHAR(64) NOT NULL,
+    timestamp TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    deleted_at TEXT NOT NULL,
+    signature BYTEA NOT NULL,
+    key_id VARCHAR(64) NOT NULL DEFAULT ''
+);
@@ -0,0 +1,15 @@
+-- Records under legal hold are neither deleted nor expired until the hold is released
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Audit log of every legal hold placed or released. It has no foreign key to signed_blobs,
+-- so the history outlives a record deleted after its hold was released.
+CREATE TABLE IF NOT EXISTS legal_hold_events (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    held BOOLEAN NOT NULL,
+    reason TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    timestamp TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_legal_hold_events_uuid ON legal_hold_events(uuid);
@@ -0,0 +1,2 @@
+ALTER TABLE signed_blobs DROP COLUMN IF EXISTS compressed_blob;
+ALTER TABLE signed_blobs DROP COLUMN IF EXISTS codec;
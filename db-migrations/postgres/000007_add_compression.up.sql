@@ -0,0 +1,4 @@
+-- Compressed records keep their content in compressed_blob, encoded with codec, and an empty blob.
+-- The size column then holds the uncompressed content size.
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS codec TEXT NOT NULL DEFAULT '';
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS compressed_blob BYTEA;
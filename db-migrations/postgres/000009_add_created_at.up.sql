@@ -0,0 +1,5 @@
+-- The signed timestamp stays a string, created_at holds the same instant for indexed range queries.
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+UPDATE signed_blobs SET created_at = CAST("timestamp" AS TIMESTAMPTZ) WHERE created_at IS NULL;
+ALTER TABLE signed_blobs ALTER COLUMN created_at SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_created_at ON signed_blobs(created_at, uuid);
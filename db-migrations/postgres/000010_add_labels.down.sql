@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_signed_blobs_labels;
+ALTER TABLE signed_blobs DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,3 @@
+-- Signed labels of the record, the GIN index serves containment (@>) filters when listing.
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_labels ON signed_blobs USING GIN (labels jsonb_path_ops);
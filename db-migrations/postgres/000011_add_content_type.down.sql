@@ -0,0 +1 @@
+ALTER TABLE signed_blobs DROP COLUMN IF EXISTS content_type;
@@ -0,0 +1,2 @@
+-- Media type of the content, signed with the record like its filename.
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS content_type TEXT NOT NULL DEFAULT '';
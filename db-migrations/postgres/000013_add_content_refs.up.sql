@@ -0,0 +1,7 @@
+-- One row per content hash a content store holds or is being written. pending counts the uploads
+-- writing the content whose records are not stored yet. Deleting content locks its row, so an
+-- upload either waits for the deletion to finish or keeps the content from being deleted.
+CREATE TABLE IF NOT EXISTS content_refs (
+    hash VARCHAR(64) PRIMARY KEY,
+    pending INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_expires_at ON signed_blobs(expires_at) WHERE expires_at <> '';
+DROP INDEX IF EXISTS idx_signed_blobs_expiry;
+ALTER TABLE signed_blobs DROP COLUMN IF EXISTS expiry;
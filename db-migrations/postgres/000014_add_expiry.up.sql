@@ -0,0 +1,6 @@
+-- The expiry time stays a string, expiry holds the same instant for indexed expiry queries and is NULL
+-- when the record is kept forever. Expiry strings written in different formats do not compare as strings.
+ALTER TABLE signed_blobs ADD COLUMN IF NOT EXISTS expiry TIMESTAMPTZ;
+UPDATE signed_blobs SET expiry = CAST(expires_at AS TIMESTAMPTZ) WHERE expires_at <> '' AND expiry IS NULL;
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_expiry ON signed_blobs(expiry, uuid) WHERE expiry IS NOT NULL;
+DROP INDEX IF EXISTS idx_signed_blobs_expires_at;
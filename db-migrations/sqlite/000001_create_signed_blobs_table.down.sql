@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS countersignatures;
+DROP TABLE IF EXISTS signed_blobs;
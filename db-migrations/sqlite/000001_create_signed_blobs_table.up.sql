@@ -0,0 +1,31 @@
+-- Signed Blob Storage Service - SQLite Schema
+-- SQLite has no UUID, BOOLEAN or BYTEA types, they are stored as TEXT, INTEGER and BLOB
+
+-- Create table for storing signed blobs
+CREATE TABLE IF NOT EXISTS signed_blobs (
+    uuid TEXT PRIMARY KEY,
+    blob TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    -- due to marshalling, we store the timestamp as a string in RFC3339 format
+    timestamp TEXT NOT NULL,
+    signature BLOB NOT NULL,
+    -- detached records sign a digest supplied by the client, the content itself is not stored
+    detached INTEGER NOT NULL DEFAULT 0,
+    filename TEXT NOT NULL DEFAULT '',
+    size INTEGER NOT NULL DEFAULT 0
+);
+
+-- Create indexes for performance
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_hash ON signed_blobs(hash);
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_timestamp ON signed_blobs(timestamp);
+
+-- Countersignatures are signatures by other parties over the same signed payload
+CREATE TABLE IF NOT EXISTS countersignatures (
+    uuid TEXT NOT NULL REFERENCES signed_blobs(uuid) ON DELETE CASCADE,
+    -- hex SHA-256 of the DER-encoded countersigning public key
+    key_id TEXT NOT NULL,
+    signature BLOB NOT NULL,
+    timestamp TEXT NOT NULL,
+    -- each key countersigns a record at most once
+    PRIMARY KEY (uuid, key_id)
+);
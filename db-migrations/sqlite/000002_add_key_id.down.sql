@@ -0,0 +1 @@
+ALTER TABLE signed_blobs DROP COLUMN key_id;
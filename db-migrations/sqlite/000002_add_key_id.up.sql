@@ -0,0 +1,2 @@
+-- Identifier of the key that signed the record, unknown for records stored before this migration
+ALTER TABLE signed_blobs ADD COLUMN key_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- Time the record expires at, in the same RFC3339 format as the timestamp, empty when it is kept forever.
+-- The format sorts chronologically, so expired records are found with a string comparison.
+ALTER TABLE signed_blobs ADD COLUMN expires_at TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_expires_at ON signed_blobs(expires_at) WHERE expires_at <> '';
+
+-- Tombstones are the signed proof left behind once an expired record has been deleted
+CREATE TABLE IF NOT EXISTS tombstones (
+    uuid TEXT PRIMARY KEY,
+    hash TEXT NOT NULL,
+    timestamp TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    deleted_at TEXT NOT NULL,
+    signature BLOB NOT NULL,
+    key_id TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS legal_hold_events;
+ALTER TABLE signed_blobs DROP COLUMN legal_hold;
@@ -0,0 +1,2 @@
+ALTER TABLE signed_blobs DROP COLUMN compressed_blob;
+ALTER TABLE signed_blobs DROP COLUMN codec;
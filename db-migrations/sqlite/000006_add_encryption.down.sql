@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_signed_blobs_master_key_id;
+ALTER TABLE signed_blobs DROP COLUMN wrapped_key;
+ALTER TABLE signed_blobs DROP COLUMN master_key_id;
+ALTER TABLE signed_blobs RENAME COLUMN encoded_blob TO compressed_blob;
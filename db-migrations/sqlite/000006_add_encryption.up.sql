@@ -0,0 +1,6 @@
+-- Encrypted records keep their content in encoded_blob, sealed with a per-record data key.
+-- The data key is stored wrapped by the master key named in master_key_id, '' for records stored in the clear.
+ALTER TABLE signed_blobs RENAME COLUMN compressed_blob TO encoded_blob;
+ALTER TABLE signed_blobs ADD COLUMN master_key_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE signed_blobs ADD COLUMN wrapped_key BLOB;
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_master_key_id ON signed_blobs(master_key_id) WHERE master_key_id <> '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_signed_blobs_created_at;
+ALTER TABLE signed_blobs DROP COLUMN created_at;
@@ -0,0 +1,15 @@
+-- The signed timestamp stays a string, created_at holds the same instant in Unix microseconds
+-- for indexed range queries. The backfill keeps up to six digits of fractional seconds.
+ALTER TABLE signed_blobs ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+-- a timestamp SQLite cannot parse fails the migration rather than sorting the record first
+CREATE TEMP TRIGGER check_created_at BEFORE UPDATE OF created_at ON signed_blobs
+WHEN strftime('%s', NEW.timestamp) IS NULL
+BEGIN
+    SELECT RAISE(ABORT, 'signed_blobs.timestamp holds a value that is not a timestamp');
+END;
+UPDATE signed_blobs SET created_at = CAST(strftime('%s', timestamp) AS INTEGER) * 1000000
+    + CASE WHEN substr(timestamp, 20, 1) = '.'
+        THEN CAST(ROUND(CAST('0.' || substr(timestamp, 21, 6) AS REAL) * 1000000) AS INTEGER)
+        ELSE 0 END;
+DROP TRIGGER check_created_at;
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_created_at ON signed_blobs(created_at, uuid);
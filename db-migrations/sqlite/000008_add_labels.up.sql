@@ -0,0 +1,2 @@
+-- Signed labels of the record as a JSON object, SQLite has no JSON index so label filters scan.
+ALTER TABLE signed_blobs ADD COLUMN labels TEXT NOT NULL DEFAULT '{}';
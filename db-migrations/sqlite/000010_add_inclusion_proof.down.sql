@@ -0,0 +1 @@
+ALTER TABLE signed_blobs DROP COLUMN inclusion_proof;
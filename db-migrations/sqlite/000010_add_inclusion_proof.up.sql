@@ -0,0 +1,2 @@
+-- Inclusion proof of a record signed in a Merkle batch, NULL for a record signed on its own.
+ALTER TABLE signed_blobs ADD COLUMN inclusion_proof BLOB;
@@ -0,0 +1,18 @@
+-- The expiry time stays a string, expiry holds the same instant in Unix microseconds for indexed expiry
+-- queries and is NULL when the record is kept forever. Expiry strings written in different formats
+-- do not compare as strings. The backfill keeps up to six digits of fractional seconds.
+ALTER TABLE signed_blobs ADD COLUMN expiry INTEGER;
+-- an expiry time SQLite cannot parse fails the migration rather than keeping the record forever
+CREATE TEMP TRIGGER check_expiry BEFORE UPDATE OF expiry ON signed_blobs
+WHEN strftime('%s', NEW.expires_at) IS NULL
+BEGIN
+    SELECT RAISE(ABORT, 'signed_blobs.expires_at holds a value that is not a timestamp');
+END;
+UPDATE signed_blobs SET expiry = CAST(strftime('%s', expires_at) AS INTEGER) * 1000000
+    + CASE WHEN substr(expires_at, 20, 1) = '.'
+        THEN CAST(ROUND(CAST('0.' || substr(expires_at, 21, 6) AS REAL) * 1000000) AS INTEGER)
+        ELSE 0 END
+WHERE expires_at <> '';
+DROP TRIGGER check_expiry;
+CREATE INDEX IF NOT EXISTS idx_signed_blobs_expiry ON signed_blobs(expiry, uuid) WHERE expiry IS NOT NULL;
+DROP INDEX IF EXISTS idx_signed_blobs_expires_at;
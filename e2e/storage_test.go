@@ -0,0 +1,92 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prit342/signed-blob-service/logger"
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/prit342/signed-blob-service/store/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresStorageConformance runs the storage conformance suite against PostgreSQL.
+// One container is shared, every storage the suite asks for gets its own database.
+func TestPostgresStorageConformance(t *testing.T) {
+	ctxContainer, cancel := context.WithTimeout(context.Background(), containerStartTimeout)
+	defer cancel()
+
+	dbHost, dbPort, cleanup := RunPostgresContainer(
+		ctxContainer,
+		t,
+		postgresImage,
+		postgresContainerReadyMsg,
+		postgresUser,
+		postgresPassword,
+		postgresDB,
+	)
+	defer cleanup()
+
+	dsn := func(database string) string {
+		return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+			postgresUser, postgresPassword, dbHost, dbPort, database)
+	}
+
+	admin, err := sql.Open("postgres", dsn(postgresDB))
+	require.NoError(t, err)
+	defer admin.Close()
+
+	log := logger.NewLogger(appName, os.Stdout, slog.LevelWarn, appVersion, appEnvironment)
+
+	var databases atomic.Int64
+	factory := func(opts ...store.Option) storetest.Factory {
+		return func(t *testing.T) (store.Storage, string) {
+			database := fmt.Sprintf("conformance_%d", databases.Add(1))
+			_, err := admin.Exec("CREATE DATABASE " + database)
+			require.NoError(t, err, "failed to create database %s", database)
+
+			storage, err := store.NewPostgresStorage(dsn(database), log, time.Second, testTimeout, opts...)
+			require.NoError(t, err)
+			return storage, migrationDir
+		}
+	}
+
+	// the suite runs in parallel subtests, which only finish once this function has returned
+	t.Run("suite", func(t *testing.T) {
+		storetest.Run(t, factory())
+	})
+	t.Run("suite with zstd compression", func(t *testing.T) {
+		storetest.Run(t, factory(store.WithCompression(store.CodecZstd)))
+	})
+
+	keyring, err := store.NewKeyring(store.MasterKey{ID: "e2e", Key: make([]byte, 32)})
+	require.NoError(t, err)
+	t.Run("suite with encryption", func(t *testing.T) {
+		storetest.Run(t, factory(store.WithEncryption(keyring)))
+	})
+
+	// the primary doubles as its own replica, which exercises the routing without replication lag
+	t.Run("suite with read replica", func(t *testing.T) {
+		storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+			database := fmt.Sprintf("conformance_%d", databases.Add(1))
+			_, err := admin.Exec("CREATE DATABASE " + database)
+			require.NoError(t, err, "failed to create database %s", database)
+
+			storage, err := store.NewPostgresStorage(dsn(database), log, time.Second, testTimeout,
+				store.WithReadReplica(dsn(database)),
+				store.WithPool(store.PoolConfig{MaxOpenConns: 4, MaxIdleConns: 2, ConnMaxLifetime: time.Minute}))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = storage.Close() })
+			return storage, migrationDir
+		})
+	})
+}
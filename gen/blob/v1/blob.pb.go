@@ -9,6 +9,8 @@ package blobv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,29 +23,2081 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Additional encodings the server can return a signed record in.
+type SignedBlobFormat int32
+
+const (
+	SignedBlobFormat_SIGNED_BLOB_FORMAT_UNSPECIFIED SignedBlobFormat = 0 // Only the Protobuf BlobRecord and its signature
+	SignedBlobFormat_SIGNED_BLOB_FORMAT_JWS         SignedBlobFormat = 1 // Also a JWS compact serialisation of the record
+	SignedBlobFormat_SIGNED_BLOB_FORMAT_COSE_SIGN1  SignedBlobFormat = 2 // Also a COSE_Sign1 (RFC 9052) message of the record
+)
+
+// Enum value maps for SignedBlobFormat.
+var (
+	SignedBlobFormat_name = map[int32]string{
+		0: "SIGNED_BLOB_FORMAT_UNSPECIFIED",
+		1: "SIGNED_BLOB_FORMAT_JWS",
+		2: "SIGNED_BLOB_FORMAT_COSE_SIGN1",
+	}
+	SignedBlobFormat_value = map[string]int32{
+		"SIGNED_BLOB_FORMAT_UNSPECIFIED": 0,
+		"SIGNED_BLOB_FORMAT_JWS":         1,
+		"SIGNED_BLOB_FORMAT_COSE_SIGN1":  2,
+	}
+)
+
+func (x SignedBlobFormat) Enum() *SignedBlobFormat {
+	p := new(SignedBlobFormat)
+	*p = x
+	return p
+}
+
+func (x SignedBlobFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SignedBlobFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_blob_v1_blob_proto_enumTypes[0].Descriptor()
+}
+
+func (SignedBlobFormat) Type() protoreflect.EnumType {
+	return &file_blob_v1_blob_proto_enumTypes[0]
+}
+
+func (x SignedBlobFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SignedBlobFormat.Descriptor instead.
+func (SignedBlobFormat) EnumDescriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{0}
+}
+
+// Why a verification failed.
+type VerificationFailure int32
+
+const (
+	VerificationFailure_VERIFICATION_FAILURE_UNSPECIFIED             VerificationFailure = 0 // The verification did not fail
+	VerificationFailure_VERIFICATION_FAILURE_CONTENT_MISMATCH        VerificationFailure = 1 // The content does not match the hash or size of the record
+	VerificationFailure_VERIFICATION_FAILURE_INVALID_INCLUSION_PROOF VerificationFailure = 2 // The inclusion proof is malformed
+	VerificationFailure_VERIFICATION_FAILURE_INVALID_SIGNATURE       VerificationFailure = 3 // No key known to the server made the signature
+)
+
+// Enum value maps for VerificationFailure.
+var (
+	VerificationFailure_name = map[int32]string{
+		0: "VERIFICATION_FAILURE_UNSPECIFIED",
+		1: "VERIFICATION_FAILURE_CONTENT_MISMATCH",
+		2: "VERIFICATION_FAILURE_INVALID_INCLUSION_PROOF",
+		3: "VERIFICATION_FAILURE_INVALID_SIGNATURE",
+	}
+	VerificationFailure_value = map[string]int32{
+		"VERIFICATION_FAILURE_UNSPECIFIED":             0,
+		"VERIFICATION_FAILURE_CONTENT_MISMATCH":        1,
+		"VERIFICATION_FAILURE_INVALID_INCLUSION_PROOF": 2,
+		"VERIFICATION_FAILURE_INVALID_SIGNATURE":       3,
+	}
+)
+
+func (x VerificationFailure) Enum() *VerificationFailure {
+	p := new(VerificationFailure)
+	*p = x
+	return p
+}
+
+func (x VerificationFailure) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VerificationFailure) Descriptor() protoreflect.EnumDescriptor {
+	return file_blob_v1_blob_proto_enumTypes[1].Descriptor()
+}
+
+func (VerificationFailure) Type() protoreflect.EnumType {
+	return &file_blob_v1_blob_proto_enumTypes[1]
+}
+
+func (x VerificationFailure) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VerificationFailure.Descriptor instead.
+func (VerificationFailure) EnumDescriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{1}
+}
+
+// Additional encodings the server can return its public key in.
+type PublicKeyFormat int32
+
+const (
+	PublicKeyFormat_PUBLIC_KEY_FORMAT_UNSPECIFIED PublicKeyFormat = 0 // Only the PEM-encoded public key
+	PublicKeyFormat_PUBLIC_KEY_FORMAT_JWKS        PublicKeyFormat = 1 // Also a JWK Set document
+	PublicKeyFormat_PUBLIC_KEY_FORMAT_COSE_KEY    PublicKeyFormat = 2 // Also a CBOR encoded COSE_Key
+)
+
+// Enum value maps for PublicKeyFormat.
+var (
+	PublicKeyFormat_name = map[int32]string{
+		0: "PUBLIC_KEY_FORMAT_UNSPECIFIED",
+		1: "PUBLIC_KEY_FORMAT_JWKS",
+		2: "PUBLIC_KEY_FORMAT_COSE_KEY",
+	}
+	PublicKeyFormat_value = map[string]int32{
+		"PUBLIC_KEY_FORMAT_UNSPECIFIED": 0,
+		"PUBLIC_KEY_FORMAT_JWKS":        1,
+		"PUBLIC_KEY_FORMAT_COSE_KEY":    2,
+	}
+)
+
+func (x PublicKeyFormat) Enum() *PublicKeyFormat {
+	p := new(PublicKeyFormat)
+	*p = x
+	return p
+}
+
+func (x PublicKeyFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PublicKeyFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_blob_v1_blob_proto_enumTypes[2].Descriptor()
+}
+
+func (PublicKeyFormat) Type() protoreflect.EnumType {
+	return &file_blob_v1_blob_proto_enumTypes[2]
+}
+
+func (x PublicKeyFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PublicKeyFormat.Descriptor instead.
+func (PublicKeyFormat) EnumDescriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{2}
+}
+
 // Client sends a raw text blob to be signed and stored.
 type StoreBlobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Blob  string                 `protobuf:"bytes,1,opt,name=blob,proto3" json:"blob,omitempty"` // Raw user input (UTF-8 text blob)
+	// Optional time to live, the blob is deleted once it has passed.
+	// Unset uses the server default, it cannot exceed the server's maximum.
+	Ttl *durationpb.Duration `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Optional labels such as the git commit, pipeline ID or owner, signed with the blob.
+	// At most 64, keys are letters, digits, '.', '_', '-' or '/' up to 63 bytes, values up to 255 bytes.
+	Labels        map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ContentType   string            `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // Optional media type of the blob, e.g. "application/json"
+	Filename      string            `protobuf:"bytes,5,opt,name=filename,proto3" json:"filename,omitempty"`                          // Optional original filename, base name only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreBlobRequest) Reset() {
+	*x = StoreBlobRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreBlobRequest) ProtoMessage() {}
+
+func (x *StoreBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreBlobRequest.ProtoReflect.Descriptor instead.
+func (*StoreBlobRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StoreBlobRequest) GetBlob() string {
+	if x != nil {
+		return x.Blob
+	}
+	return ""
+}
+
+func (x *StoreBlobRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *StoreBlobRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *StoreBlobRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *StoreBlobRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+// Server responds with the UUID assigned to the stored and signed blob.
+// the UUID is used for future retrieval and verification.
+type StoreBlobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID used to identify and retrieve the blob
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreBlobResponse) Reset() {
+	*x = StoreBlobResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreBlobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreBlobResponse) ProtoMessage() {}
+
+func (x *StoreBlobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreBlobResponse.ProtoReflect.Descriptor instead.
+func (*StoreBlobResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StoreBlobResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+// Client sends several blobs to be signed and stored at once, at most 1000.
+type StoreBlobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blobs         []*StoreBlobRequest    `protobuf:"bytes,1,rep,name=blobs,proto3" json:"blobs,omitempty"` // Each blob is validated and signed like a StoreBlobRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreBlobsRequest) Reset() {
+	*x = StoreBlobsRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreBlobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreBlobsRequest) ProtoMessage() {}
+
+func (x *StoreBlobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreBlobsRequest.ProtoReflect.Descriptor instead.
+func (*StoreBlobsRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StoreBlobsRequest) GetBlobs() []*StoreBlobRequest {
+	if x != nil {
+		return x.Blobs
+	}
+	return nil
+}
+
+// Outcome of one blob of a StoreBlobsRequest.
+type StoreBlobResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`   // UUID of the stored blob, empty when it failed
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // Why the blob was not stored, empty when it succeeded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreBlobResult) Reset() {
+	*x = StoreBlobResult{}
+	mi := &file_blob_v1_blob_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreBlobResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreBlobResult) ProtoMessage() {}
+
+func (x *StoreBlobResult) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreBlobResult.ProtoReflect.Descriptor instead.
+func (*StoreBlobResult) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StoreBlobResult) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *StoreBlobResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Server responds with one result per blob, in the order of the request.
+type StoreBlobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*StoreBlobResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreBlobsResponse) Reset() {
+	*x = StoreBlobsResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreBlobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreBlobsResponse) ProtoMessage() {}
+
+func (x *StoreBlobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreBlobsResponse.ProtoReflect.Descriptor instead.
+func (*StoreBlobsResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StoreBlobsResponse) GetResults() []*StoreBlobResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// The canonical structure representing a stored blob, including:
+// - The original blob content
+// - Its hash (SHA-256, hex-encoded)
+// - The assigned UUID
+// - The timestamp when it was signed (RFC3339, string for canonicalisation)
+// This structure is serialised, signed, and stored in the database as-is.
+// Detached records (see SignDigest) carry no content, only the digest of
+// content stored elsewhere, its optional filename and its size.
+// Records with a time to live carry the time they expire at.
+// The original filename and media type of the content are optional for every record.
+// Map fields are serialised deterministically, entries sorted by key, before signing.
+type BlobRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                                                                               // Server-generated UUID for identification
+	Blob          string                 `protobuf:"bytes,2,opt,name=blob,proto3" json:"blob,omitempty"`                                                                               // Original user-submitted blob, empty for detached records
+	Hash          string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`                                                                               // SHA-256 hash of the blob, hex-encoded
+	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                                                     // RFC3339 formatted timestamp in microseconds (e.g., "2025-07-28T17:42:05.123456Z")
+	Detached      bool                   `protobuf:"varint,5,opt,name=detached,proto3" json:"detached,omitempty"`                                                                      // True when the content is not stored by the service
+	Filename      string                 `protobuf:"bytes,6,opt,name=filename,proto3" json:"filename,omitempty"`                                                                       // Optional original filename, base name only
+	Size          int64                  `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`                                                                              // Content size in bytes as declared by the client (detached records)
+	ExpiresAt     string                 `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                                    // RFC3339 formatted time the record is deleted at, empty when kept forever
+	Labels        map[string]string      `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Labels given by the client when storing the blob
+	ContentType   string                 `protobuf:"bytes,10,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`                                             // Optional media type of the content, e.g. "application/json"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobRecord) Reset() {
+	*x = BlobRecord{}
+	mi := &file_blob_v1_blob_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobRecord) ProtoMessage() {}
+
+func (x *BlobRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobRecord.ProtoReflect.Descriptor instead.
+func (*BlobRecord) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BlobRecord) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *BlobRecord) GetBlob() string {
+	if x != nil {
+		return x.Blob
+	}
+	return ""
+}
+
+func (x *BlobRecord) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *BlobRecord) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *BlobRecord) GetDetached() bool {
+	if x != nil {
+		return x.Detached
+	}
+	return false
+}
+
+func (x *BlobRecord) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *BlobRecord) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BlobRecord) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *BlobRecord) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *BlobRecord) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// Client sends only the digest of content stored elsewhere, to be signed and recorded.
+type SignDigestRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Sha256Digest string                 `protobuf:"bytes,1,opt,name=sha256_digest,json=sha256Digest,proto3" json:"sha256_digest,omitempty"` // SHA-256 of the content, hex-encoded
+	Filename     string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`                             // Optional original filename, base name only
+	Size         int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`                                    // Optional content size in bytes
+	// Optional time to live of the record, bounded by the server like StoreBlobRequest.ttl
+	Ttl           *durationpb.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignDigestRequest) Reset() {
+	*x = SignDigestRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignDigestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignDigestRequest) ProtoMessage() {}
+
+func (x *SignDigestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignDigestRequest.ProtoReflect.Descriptor instead.
+func (*SignDigestRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SignDigestRequest) GetSha256Digest() string {
+	if x != nil {
+		return x.Sha256Digest
+	}
+	return ""
+}
+
+func (x *SignDigestRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *SignDigestRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *SignDigestRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+// Server responds with the UUID assigned to the detached record.
+type SignDigestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID used to identify and retrieve the detached record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignDigestResponse) Reset() {
+	*x = SignDigestResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignDigestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignDigestResponse) ProtoMessage() {}
+
+func (x *SignDigestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignDigestResponse.ProtoReflect.Descriptor instead.
+func (*SignDigestResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SignDigestResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+// Client requests a previously stored blob by UUID.
+type GetSignedBlobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                                    // UUID of the blob to retrieve
+	Format        SignedBlobFormat       `protobuf:"varint,2,opt,name=format,proto3,enum=blob.v1.SignedBlobFormat" json:"format,omitempty"` // Optional additional encoding of the signed record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignedBlobRequest) Reset() {
+	*x = GetSignedBlobRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignedBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignedBlobRequest) ProtoMessage() {}
+
+func (x *GetSignedBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignedBlobRequest.ProtoReflect.Descriptor instead.
+func (*GetSignedBlobRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetSignedBlobRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *GetSignedBlobRequest) GetFormat() SignedBlobFormat {
+	if x != nil {
+		return x.Format
+	}
+	return SignedBlobFormat_SIGNED_BLOB_FORMAT_UNSPECIFIED
+}
+
+// An additional signature over a signed record by a party other than the server.
+// It covers exactly the same bytes as the server signature: the Protobuf-encoded BlobRecord.
+type Countersignature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"` // Identifier of the countersigning key (hex SHA-256 of the DER public key)
+	Signature     []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`      // Signature of the BlobRecord payload by that key
+	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`      // RFC3339 formatted time the server accepted the countersignature
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Countersignature) Reset() {
+	*x = Countersignature{}
+	mi := &file_blob_v1_blob_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Countersignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Countersignature) ProtoMessage() {}
+
+func (x *Countersignature) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Countersignature.ProtoReflect.Descriptor instead.
+func (*Countersignature) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Countersignature) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *Countersignature) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Countersignature) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// A registered party attaches its signature to an existing record.
+type AddCountersignatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                // UUID of the record being countersigned
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"` // Identifier of a countersigning key registered with the server
+	Signature     []byte                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`      // Signature of the Protobuf-encoded BlobRecord payload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCountersignatureRequest) Reset() {
+	*x = AddCountersignatureRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCountersignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCountersignatureRequest) ProtoMessage() {}
+
+func (x *AddCountersignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCountersignatureRequest.ProtoReflect.Descriptor instead.
+func (*AddCountersignatureRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddCountersignatureRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AddCountersignatureRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *AddCountersignatureRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// Server responds once the countersignature is verified and stored.
+type AddCountersignatureResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Countersignature *Countersignature      `protobuf:"bytes,1,opt,name=countersignature,proto3" json:"countersignature,omitempty"` // The stored countersignature
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AddCountersignatureResponse) Reset() {
+	*x = AddCountersignatureResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCountersignatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCountersignatureResponse) ProtoMessage() {}
+
+func (x *AddCountersignatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCountersignatureResponse.ProtoReflect.Descriptor instead.
+func (*AddCountersignatureResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddCountersignatureResponse) GetCountersignature() *Countersignature {
+	if x != nil {
+		return x.Countersignature
+	}
+	return nil
+}
+
+// Server responds with:
+//   - The exact payload it signed (BlobRecord)
+//   - The digital signature over the Protobuf-encoded BlobRecord,
+//     or over the MerkleTreeHead of its batch when the record was Merkle-batch signed
+//   - Optionally the record in a standard envelope (see SignedBlobFormat)
+//   - Every countersignature attached to the record
+type GetSignedBlobResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Payload   *BlobRecord            `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`     // The canonical, signed structure
+	Signature []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"` // RSA signature of the BlobRecord payload
+	// JWS compact serialisation whose payload is the JSON encoded BlobRecord,
+	// set when SIGNED_BLOB_FORMAT_JWS is requested. The "kid" header is the signing key ID.
+	Jws string `protobuf:"bytes,3,opt,name=jws,proto3" json:"jws,omitempty"`
+	// CBOR encoded, tagged COSE_Sign1 message whose payload is the Protobuf encoded BlobRecord,
+	// set when SIGNED_BLOB_FORMAT_COSE_SIGN1 is requested. The "kid" header is the signing key ID.
+	CoseSign1         []byte              `protobuf:"bytes,4,opt,name=cose_sign1,json=coseSign1,proto3" json:"cose_sign1,omitempty"`
+	Countersignatures []*Countersignature `protobuf:"bytes,5,rep,name=countersignatures,proto3" json:"countersignatures,omitempty"` // Countersignatures over the same payload
+	// Set when the record was signed in a Merkle batch, the signature then covers the
+	// MerkleTreeHead whose root the proof leads to from the record.
+	InclusionProof *InclusionProof `protobuf:"bytes,6,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetSignedBlobResponse) Reset() {
+	*x = GetSignedBlobResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignedBlobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignedBlobResponse) ProtoMessage() {}
+
+func (x *GetSignedBlobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignedBlobResponse.ProtoReflect.Descriptor instead.
+func (*GetSignedBlobResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSignedBlobResponse) GetPayload() *BlobRecord {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *GetSignedBlobResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *GetSignedBlobResponse) GetJws() string {
+	if x != nil {
+		return x.Jws
+	}
+	return ""
+}
+
+func (x *GetSignedBlobResponse) GetCoseSign1() []byte {
+	if x != nil {
+		return x.CoseSign1
+	}
+	return nil
+}
+
+func (x *GetSignedBlobResponse) GetCountersignatures() []*Countersignature {
+	if x != nil {
+		return x.Countersignatures
+	}
+	return nil
+}
+
+func (x *GetSignedBlobResponse) GetInclusionProof() *InclusionProof {
+	if x != nil {
+		return x.InclusionProof
+	}
+	return nil
+}
+
+// Proof that a record is a leaf of a Merkle tree whose root the server signed once for
+// a batch of records. Leaves are SHA-256(0x00 || Protobuf-encoded BlobRecord), nodes are
+// SHA-256(0x01 || left || right), following RFC 9162.
+type InclusionProof struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LeafIndex     int64                  `protobuf:"varint,1,opt,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"` // Position of the record among the leaves of the tree
+	TreeSize      int64                  `protobuf:"varint,2,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`    // Number of leaves of the tree
+	Hashes        [][]byte               `protobuf:"bytes,3,rep,name=hashes,proto3" json:"hashes,omitempty"`                         // Sibling hashes on the path from the leaf up to the root
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InclusionProof) Reset() {
+	*x = InclusionProof{}
+	mi := &file_blob_v1_blob_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InclusionProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InclusionProof) ProtoMessage() {}
+
+func (x *InclusionProof) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InclusionProof.ProtoReflect.Descriptor instead.
+func (*InclusionProof) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *InclusionProof) GetLeafIndex() int64 {
+	if x != nil {
+		return x.LeafIndex
+	}
+	return 0
+}
+
+func (x *InclusionProof) GetTreeSize() int64 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
+}
+
+func (x *InclusionProof) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+// The structure the server signs instead of the BlobRecord for records signed in a Merkle batch.
+type MerkleTreeHead struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootHash      []byte                 `protobuf:"bytes,1,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`  // Root of the tree, computed from a record and its InclusionProof
+	TreeSize      int64                  `protobuf:"varint,2,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"` // Number of leaves of the tree, the tree_size of the InclusionProof
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MerkleTreeHead) Reset() {
+	*x = MerkleTreeHead{}
+	mi := &file_blob_v1_blob_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MerkleTreeHead) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MerkleTreeHead) ProtoMessage() {}
+
+func (x *MerkleTreeHead) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MerkleTreeHead.ProtoReflect.Descriptor instead.
+func (*MerkleTreeHead) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MerkleTreeHead) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *MerkleTreeHead) GetTreeSize() int64 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
+}
+
+// Client requests several previously stored blobs by UUID, at most 1000.
+type GetSignedBlobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuids         []string               `protobuf:"bytes,1,rep,name=uuids,proto3" json:"uuids,omitempty"`                                  // UUIDs of the blobs to retrieve
+	Format        SignedBlobFormat       `protobuf:"varint,2,opt,name=format,proto3,enum=blob.v1.SignedBlobFormat" json:"format,omitempty"` // Optional additional encoding of every signed record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignedBlobsRequest) Reset() {
+	*x = GetSignedBlobsRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignedBlobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignedBlobsRequest) ProtoMessage() {}
+
+func (x *GetSignedBlobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignedBlobsRequest.ProtoReflect.Descriptor instead.
+func (*GetSignedBlobsRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetSignedBlobsRequest) GetUuids() []string {
+	if x != nil {
+		return x.Uuids
+	}
+	return nil
+}
+
+func (x *GetSignedBlobsRequest) GetFormat() SignedBlobFormat {
+	if x != nil {
+		return x.Format
+	}
+	return SignedBlobFormat_SIGNED_BLOB_FORMAT_UNSPECIFIED
+}
+
+// Outcome of one UUID of a GetSignedBlobsRequest.
+type GetSignedBlobResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blob          *GetSignedBlobResponse `protobuf:"bytes,1,opt,name=blob,proto3" json:"blob,omitempty"`   // The signed blob, unset when it failed
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // Why the blob was not retrieved, empty when it succeeded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignedBlobResult) Reset() {
+	*x = GetSignedBlobResult{}
+	mi := &file_blob_v1_blob_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignedBlobResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignedBlobResult) ProtoMessage() {}
+
+func (x *GetSignedBlobResult) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignedBlobResult.ProtoReflect.Descriptor instead.
+func (*GetSignedBlobResult) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetSignedBlobResult) GetBlob() *GetSignedBlobResponse {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+func (x *GetSignedBlobResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Server responds with one result per UUID, in the order of the request.
+type GetSignedBlobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*GetSignedBlobResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignedBlobsResponse) Reset() {
+	*x = GetSignedBlobsResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignedBlobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignedBlobsResponse) ProtoMessage() {}
+
+func (x *GetSignedBlobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignedBlobsResponse.ProtoReflect.Descriptor instead.
+func (*GetSignedBlobsResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetSignedBlobsResponse) GetResults() []*GetSignedBlobResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// same as GetSignedBlobResponse, but with a different name for clarity
+type SignedBlobRecord struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Payload        *BlobRecord            `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`                                     // The canonical, signed structure
+	Signature      []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`                                 // RSA signature of the BlobRecord payload
+	KeyId          string                 `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`                            // Identifier of the key that made the signature, not part of the signed payload
+	InclusionProof *InclusionProof        `protobuf:"bytes,4,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"` // Set for records signed in a Merkle batch, not part of the signed payload
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SignedBlobRecord) Reset() {
+	*x = SignedBlobRecord{}
+	mi := &file_blob_v1_blob_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedBlobRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedBlobRecord) ProtoMessage() {}
+
+func (x *SignedBlobRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedBlobRecord.ProtoReflect.Descriptor instead.
+func (*SignedBlobRecord) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SignedBlobRecord) GetPayload() *BlobRecord {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SignedBlobRecord) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *SignedBlobRecord) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *SignedBlobRecord) GetInclusionProof() *InclusionProof {
+	if x != nil {
+		return x.InclusionProof
+	}
+	return nil
+}
+
+// Client asks the server to verify a signature, either over a record it holds or over a
+// stored record and the content it should match.
+type VerifyBlobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The record as returned by GetSignedBlob, including its content unless it is detached.
+	// Exactly one of record and uuid must be set.
+	Record *BlobRecord `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// UUID of a stored record, verified against the content instead of a record held by the client
+	Uuid string `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// The content the stored record must match, its SHA-256 digest must be the hash of the record.
+	// For detached records its size must match too when they were signed with one. Optional for
+	// records that are not detached, their stored content is verified when empty.
+	Content []byte `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// The signature to verify. Optional with uuid, the stored signature is verified when empty.
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Inclusion proof of a record signed in a Merkle batch, with uuid the stored one is used when
+	// the signature is left empty
+	InclusionProof *InclusionProof `protobuf:"bytes,5,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyBlobRequest) Reset() {
+	*x = VerifyBlobRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBlobRequest) ProtoMessage() {}
+
+func (x *VerifyBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBlobRequest.ProtoReflect.Descriptor instead.
+func (*VerifyBlobRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *VerifyBlobRequest) GetRecord() *BlobRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *VerifyBlobRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *VerifyBlobRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *VerifyBlobRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *VerifyBlobRequest) GetInclusionProof() *InclusionProof {
+	if x != nil {
+		return x.InclusionProof
+	}
+	return nil
+}
+
+// Server responds whether the signature is valid and which key made it.
+type VerifyBlobResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Valid bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// Identifier of the key that made the signature: the server signing key or a registered
+	// countersigning key, empty when the signature is invalid
+	KeyId         string              `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Failure       VerificationFailure `protobuf:"varint,3,opt,name=failure,proto3,enum=blob.v1.VerificationFailure" json:"failure,omitempty"` // Why the verification failed, unspecified when valid
+	Error         string              `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                                       // Details of the failure, empty when valid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyBlobResponse) Reset() {
+	*x = VerifyBlobResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBlobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBlobResponse) ProtoMessage() {}
+
+func (x *VerifyBlobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBlobResponse.ProtoReflect.Descriptor instead.
+func (*VerifyBlobResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *VerifyBlobResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyBlobResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *VerifyBlobResponse) GetFailure() VerificationFailure {
+	if x != nil {
+		return x.Failure
+	}
+	return VerificationFailure_VERIFICATION_FAILURE_UNSPECIFIED
+}
+
+func (x *VerifyBlobResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Client asks whether a record exists without retrieving it.
+type BlobExistsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID of the record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobExistsRequest) Reset() {
+	*x = BlobExistsRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobExistsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobExistsRequest) ProtoMessage() {}
+
+func (x *BlobExistsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobExistsRequest.ProtoReflect.Descriptor instead.
+func (*BlobExistsRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BlobExistsRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+// Server responds with whether the record exists.
+type BlobExistsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exists        bool                   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"` // True when a record with the UUID is stored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobExistsResponse) Reset() {
+	*x = BlobExistsResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobExistsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobExistsResponse) ProtoMessage() {}
+
+func (x *BlobExistsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobExistsResponse.ProtoReflect.Descriptor instead.
+func (*BlobExistsResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BlobExistsResponse) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+// Client requests the metadata of a record without its content.
+type GetBlobMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID of the record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlobMetadataRequest) Reset() {
+	*x = GetBlobMetadataRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlobMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlobMetadataRequest) ProtoMessage() {}
+
+func (x *GetBlobMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlobMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetBlobMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetBlobMetadataRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+// Everything known about a stored record except its content and signature.
+type BlobMetadata struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Uuid      string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`           // UUID of the record
+	Hash      string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`           // SHA-256 hash of the content, hex-encoded
+	Timestamp string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // RFC3339 formatted time the record was signed
+	Size      int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`          // Content size in bytes, as declared by the client for detached records
+	// Identifier of the key that signed the record,
+	// empty for records stored before key IDs were recorded
+	KeyId         string            `protobuf:"bytes,5,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Detached      bool              `protobuf:"varint,6,opt,name=detached,proto3" json:"detached,omitempty"`                                                                       // True when the content is not stored by the service
+	Filename      string            `protobuf:"bytes,7,opt,name=filename,proto3" json:"filename,omitempty"`                                                                        // Original filename of the content, if any
+	ExpiresAt     string            `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                                     // RFC3339 formatted time the record is deleted at, empty when kept forever
+	LegalHold     bool              `protobuf:"varint,9,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`                                                    // True while the record is under legal hold and cannot be deleted
+	Labels        map[string]string `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Signed labels of the record
+	ContentType   string            `protobuf:"bytes,11,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`                                              // Media type of the content, if any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobMetadata) Reset() {
+	*x = BlobMetadata{}
+	mi := &file_blob_v1_blob_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobMetadata) ProtoMessage() {}
+
+func (x *BlobMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobMetadata.ProtoReflect.Descriptor instead.
+func (*BlobMetadata) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BlobMetadata) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *BlobMetadata) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *BlobMetadata) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *BlobMetadata) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BlobMetadata) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *BlobMetadata) GetDetached() bool {
+	if x != nil {
+		return x.Detached
+	}
+	return false
+}
+
+func (x *BlobMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *BlobMetadata) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *BlobMetadata) GetLegalHold() bool {
+	if x != nil {
+		return x.LegalHold
+	}
+	return false
+}
+
+func (x *BlobMetadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *BlobMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// Server responds with the metadata of the record.
+type GetBlobMetadataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *BlobMetadata          `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"` // Metadata of the record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlobMetadataResponse) Reset() {
+	*x = GetBlobMetadataResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlobMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlobMetadataResponse) ProtoMessage() {}
+
+func (x *GetBlobMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlobMetadataResponse.ProtoReflect.Descriptor instead.
+func (*GetBlobMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetBlobMetadataResponse) GetMetadata() *BlobMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Client lists the records signed within a time range, oldest first.
+type ListBlobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                                                    // Records signed at or after this time, unset for no lower bound
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                                                          // Records signed before this time, unset for no upper bound
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                                                      // Maximum number of records returned, 100 when unset, at most 1000
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                                                    // next_page_token of the previous page, empty for the first page
+	Labels        map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Only records carrying all of these labels
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlobsRequest) Reset() {
+	*x = ListBlobsRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlobsRequest) ProtoMessage() {}
+
+func (x *ListBlobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlobsRequest.ProtoReflect.Descriptor instead.
+func (*ListBlobsRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListBlobsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ListBlobsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListBlobsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBlobsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListBlobsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// Server responds with a page of record metadata.
+type ListBlobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blobs         []*BlobMetadata        `protobuf:"bytes,1,rep,name=blobs,proto3" json:"blobs,omitempty"`                                        // Metadata of the records, oldest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Token of the next page, empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlobsResponse) Reset() {
+	*x = ListBlobsResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlobsResponse) ProtoMessage() {}
+
+func (x *ListBlobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlobsResponse.ProtoReflect.Descriptor instead.
+func (*ListBlobsResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListBlobsResponse) GetBlobs() []*BlobMetadata {
+	if x != nil {
+		return x.Blobs
+	}
+	return nil
+}
+
+func (x *ListBlobsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// What remains of a record once it has expired: proof that it existed and when it was deleted.
+// This structure is serialised and signed by the server like BlobRecord.
+type Tombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                            // UUID of the deleted record
+	Hash          string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`                            // SHA-256 hash of the deleted content, hex-encoded
+	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                  // RFC3339 formatted time the record was signed
+	ExpiresAt     string                 `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC3339 formatted time the record expired at
+	DeletedAt     string                 `protobuf:"bytes,5,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"` // RFC3339 formatted time the record was deleted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tombstone) Reset() {
+	*x = Tombstone{}
+	mi := &file_blob_v1_blob_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tombstone) ProtoMessage() {}
+
+func (x *Tombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tombstone.ProtoReflect.Descriptor instead.
+func (*Tombstone) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Tombstone) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Tombstone) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Tombstone) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Tombstone) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Tombstone) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+// A tombstone with the server signature over the Protobuf-encoded Tombstone.
+type SignedTombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       *Tombstone             `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`          // The canonical, signed structure
+	Signature     []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`      // Signature of the Tombstone payload
+	KeyId         string                 `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"` // Identifier of the key that made the signature, not part of the signed payload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedTombstone) Reset() {
+	*x = SignedTombstone{}
+	mi := &file_blob_v1_blob_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedTombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedTombstone) ProtoMessage() {}
+
+func (x *SignedTombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedTombstone.ProtoReflect.Descriptor instead.
+func (*SignedTombstone) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SignedTombstone) GetPayload() *Tombstone {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SignedTombstone) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *SignedTombstone) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// Client requests the tombstone of an expired record.
+type GetTombstoneRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Blob          string                 `protobuf:"bytes,1,opt,name=blob,proto3" json:"blob,omitempty"` // Raw user input (UTF-8 text blob)
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID of the expired record
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StoreBlobRequest) Reset() {
-	*x = StoreBlobRequest{}
-	mi := &file_blob_v1_blob_proto_msgTypes[0]
+func (x *GetTombstoneRequest) Reset() {
+	*x = GetTombstoneRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StoreBlobRequest) String() string {
+func (x *GetTombstoneRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StoreBlobRequest) ProtoMessage() {}
+func (*GetTombstoneRequest) ProtoMessage() {}
 
-func (x *StoreBlobRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[0]
+func (x *GetTombstoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -54,42 +2108,41 @@ func (x *StoreBlobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StoreBlobRequest.ProtoReflect.Descriptor instead.
-func (*StoreBlobRequest) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use GetTombstoneRequest.ProtoReflect.Descriptor instead.
+func (*GetTombstoneRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *StoreBlobRequest) GetBlob() string {
+func (x *GetTombstoneRequest) GetUuid() string {
 	if x != nil {
-		return x.Blob
+		return x.Uuid
 	}
 	return ""
 }
 
-// Server responds with the UUID assigned to the stored and signed blob.
-// the UUID is used for future retrieval and verification.
-type StoreBlobResponse struct {
+// Server responds with the signed tombstone of the record.
+type GetTombstoneResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID used to identify and retrieve the blob
+	Tombstone     *SignedTombstone       `protobuf:"bytes,1,opt,name=tombstone,proto3" json:"tombstone,omitempty"` // The tombstone and its signature
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StoreBlobResponse) Reset() {
-	*x = StoreBlobResponse{}
-	mi := &file_blob_v1_blob_proto_msgTypes[1]
+func (x *GetTombstoneResponse) Reset() {
+	*x = GetTombstoneResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StoreBlobResponse) String() string {
+func (x *GetTombstoneResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StoreBlobResponse) ProtoMessage() {}
+func (*GetTombstoneResponse) ProtoMessage() {}
 
-func (x *StoreBlobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[1]
+func (x *GetTombstoneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,49 +2153,45 @@ func (x *StoreBlobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StoreBlobResponse.ProtoReflect.Descriptor instead.
-func (*StoreBlobResponse) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use GetTombstoneResponse.ProtoReflect.Descriptor instead.
+func (*GetTombstoneResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *StoreBlobResponse) GetUuid() string {
+func (x *GetTombstoneResponse) GetTombstone() *SignedTombstone {
 	if x != nil {
-		return x.Uuid
+		return x.Tombstone
 	}
-	return ""
+	return nil
 }
 
-// The canonical structure representing a stored blob, including:
-// - The original blob content
-// - Its hash (SHA-256, hex-encoded)
-// - The assigned UUID
-// - The timestamp when it was signed (RFC3339, string for canonicalisation)
-// This structure is serialised, signed, and stored in the database as-is.
-type BlobRecord struct {
+// A legal hold placed on or released from a record, as recorded in the audit log.
+type LegalHoldEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`           // Server-generated UUID for identification
-	Blob          string                 `protobuf:"bytes,2,opt,name=blob,proto3" json:"blob,omitempty"`           // Original user-submitted blob
-	Hash          string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`           // SHA-256 hash of the blob, hex-encoded
-	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // RFC3339 formatted timestamp (e.g., "2025-07-28T17:42:05Z")
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                                  // UUID of the record
+	Held          bool                   `protobuf:"varint,2,opt,name=held,proto3" json:"held,omitempty"`                                 // True when the hold was placed, false when it was released
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                              // Why the hold was placed or released, e.g. a case reference
+	RequestedBy   string                 `protobuf:"bytes,4,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"` // Who asked for the change, as declared by the client
+	Timestamp     string                 `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                        // RFC3339 formatted time the server recorded the change
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BlobRecord) Reset() {
-	*x = BlobRecord{}
-	mi := &file_blob_v1_blob_proto_msgTypes[2]
+func (x *LegalHoldEvent) Reset() {
+	*x = LegalHoldEvent{}
+	mi := &file_blob_v1_blob_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BlobRecord) String() string {
+func (x *LegalHoldEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobRecord) ProtoMessage() {}
+func (*LegalHoldEvent) ProtoMessage() {}
 
-func (x *BlobRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[2]
+func (x *LegalHoldEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -153,62 +2202,72 @@ func (x *BlobRecord) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobRecord.ProtoReflect.Descriptor instead.
-func (*BlobRecord) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use LegalHoldEvent.ProtoReflect.Descriptor instead.
+func (*LegalHoldEvent) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *BlobRecord) GetUuid() string {
+func (x *LegalHoldEvent) GetUuid() string {
 	if x != nil {
 		return x.Uuid
 	}
 	return ""
 }
 
-func (x *BlobRecord) GetBlob() string {
+func (x *LegalHoldEvent) GetHeld() bool {
 	if x != nil {
-		return x.Blob
+		return x.Held
+	}
+	return false
+}
+
+func (x *LegalHoldEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *BlobRecord) GetHash() string {
+func (x *LegalHoldEvent) GetRequestedBy() string {
 	if x != nil {
-		return x.Hash
+		return x.RequestedBy
 	}
 	return ""
 }
 
-func (x *BlobRecord) GetTimestamp() string {
+func (x *LegalHoldEvent) GetTimestamp() string {
 	if x != nil {
 		return x.Timestamp
 	}
 	return ""
 }
 
-// Client requests a previously stored blob by UUID.
-type GetSignedBlobRequest struct {
+// Client places a legal hold on a record or releases it.
+type SetLegalHoldRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID of the blob to retrieve
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`                                  // UUID of the record
+	Held          bool                   `protobuf:"varint,2,opt,name=held,proto3" json:"held,omitempty"`                                 // True to place the hold, false to release it
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                              // Required reason, kept in the audit log
+	RequestedBy   string                 `protobuf:"bytes,4,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"` // Required name of the person or system asking for the change
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSignedBlobRequest) Reset() {
-	*x = GetSignedBlobRequest{}
-	mi := &file_blob_v1_blob_proto_msgTypes[3]
+func (x *SetLegalHoldRequest) Reset() {
+	*x = SetLegalHoldRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSignedBlobRequest) String() string {
+func (x *SetLegalHoldRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSignedBlobRequest) ProtoMessage() {}
+func (*SetLegalHoldRequest) ProtoMessage() {}
 
-func (x *GetSignedBlobRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[3]
+func (x *SetLegalHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -219,44 +2278,62 @@ func (x *GetSignedBlobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSignedBlobRequest.ProtoReflect.Descriptor instead.
-func (*GetSignedBlobRequest) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use SetLegalHoldRequest.ProtoReflect.Descriptor instead.
+func (*SetLegalHoldRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *GetSignedBlobRequest) GetUuid() string {
+func (x *SetLegalHoldRequest) GetUuid() string {
 	if x != nil {
 		return x.Uuid
 	}
 	return ""
 }
 
-// Server responds with:
-// - The exact payload it signed (BlobRecord)
-// - The digital signature over the Protobuf-encoded BlobRecord
-type GetSignedBlobResponse struct {
+func (x *SetLegalHoldRequest) GetHeld() bool {
+	if x != nil {
+		return x.Held
+	}
+	return false
+}
+
+func (x *SetLegalHoldRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SetLegalHoldRequest) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+// Server responds with the audit log entry of the change.
+type SetLegalHoldResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Payload       *BlobRecord            `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`     // The canonical, signed structure
-	Signature     []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"` // RSA signature of the BlobRecord payload
+	Event         *LegalHoldEvent        `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"` // The recorded change
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSignedBlobResponse) Reset() {
-	*x = GetSignedBlobResponse{}
-	mi := &file_blob_v1_blob_proto_msgTypes[4]
+func (x *SetLegalHoldResponse) Reset() {
+	*x = SetLegalHoldResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSignedBlobResponse) String() string {
+func (x *SetLegalHoldResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSignedBlobResponse) ProtoMessage() {}
+func (*SetLegalHoldResponse) ProtoMessage() {}
 
-func (x *GetSignedBlobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[4]
+func (x *SetLegalHoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,49 +2344,41 @@ func (x *GetSignedBlobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSignedBlobResponse.ProtoReflect.Descriptor instead.
-func (*GetSignedBlobResponse) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{4}
-}
-
-func (x *GetSignedBlobResponse) GetPayload() *BlobRecord {
-	if x != nil {
-		return x.Payload
-	}
-	return nil
+// Deprecated: Use SetLegalHoldResponse.ProtoReflect.Descriptor instead.
+func (*SetLegalHoldResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *GetSignedBlobResponse) GetSignature() []byte {
+func (x *SetLegalHoldResponse) GetEvent() *LegalHoldEvent {
 	if x != nil {
-		return x.Signature
+		return x.Event
 	}
 	return nil
 }
 
-// same as GetSignedBlobResponse, but with a different name for clarity
-type SignedBlobRecord struct {
+// Client requests the legal hold audit log of a record.
+type GetLegalHoldHistoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Payload       *BlobRecord            `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`     // The canonical, signed structure
-	Signature     []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"` // RSA signature of the BlobRecord payload
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // UUID of the record, which may have been deleted since
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SignedBlobRecord) Reset() {
-	*x = SignedBlobRecord{}
-	mi := &file_blob_v1_blob_proto_msgTypes[5]
+func (x *GetLegalHoldHistoryRequest) Reset() {
+	*x = GetLegalHoldHistoryRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SignedBlobRecord) String() string {
+func (x *GetLegalHoldHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignedBlobRecord) ProtoMessage() {}
+func (*GetLegalHoldHistoryRequest) ProtoMessage() {}
 
-func (x *SignedBlobRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[5]
+func (x *GetLegalHoldHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,21 +2389,59 @@ func (x *SignedBlobRecord) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignedBlobRecord.ProtoReflect.Descriptor instead.
-func (*SignedBlobRecord) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use GetLegalHoldHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetLegalHoldHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *SignedBlobRecord) GetPayload() *BlobRecord {
+func (x *GetLegalHoldHistoryRequest) GetUuid() string {
 	if x != nil {
-		return x.Payload
+		return x.Uuid
 	}
-	return nil
+	return ""
 }
 
-func (x *SignedBlobRecord) GetSignature() []byte {
+// Server responds with every legal hold change of the record, oldest first.
+type GetLegalHoldHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*LegalHoldEvent      `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"` // The audit log of the record
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLegalHoldHistoryResponse) Reset() {
+	*x = GetLegalHoldHistoryResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLegalHoldHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLegalHoldHistoryResponse) ProtoMessage() {}
+
+func (x *GetLegalHoldHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[36]
 	if x != nil {
-		return x.Signature
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLegalHoldHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetLegalHoldHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetLegalHoldHistoryResponse) GetEvents() []*LegalHoldEvent {
+	if x != nil {
+		return x.Events
 	}
 	return nil
 }
@@ -342,13 +2449,14 @@ func (x *SignedBlobRecord) GetSignature() []byte {
 // Client requests the public key used for signing blobs.
 type GetPublicKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        PublicKeyFormat        `protobuf:"varint,1,opt,name=format,proto3,enum=blob.v1.PublicKeyFormat" json:"format,omitempty"` // Optional additional encoding of the public key
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetPublicKeyRequest) Reset() {
 	*x = GetPublicKeyRequest{}
-	mi := &file_blob_v1_blob_proto_msgTypes[6]
+	mi := &file_blob_v1_blob_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -360,7 +2468,7 @@ func (x *GetPublicKeyRequest) String() string {
 func (*GetPublicKeyRequest) ProtoMessage() {}
 
 func (x *GetPublicKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[6]
+	mi := &file_blob_v1_blob_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -373,20 +2481,30 @@ func (x *GetPublicKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPublicKeyRequest.ProtoReflect.Descriptor instead.
 func (*GetPublicKeyRequest) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{6}
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetPublicKeyRequest) GetFormat() PublicKeyFormat {
+	if x != nil {
+		return x.Format
+	}
+	return PublicKeyFormat_PUBLIC_KEY_FORMAT_UNSPECIFIED
 }
 
 // Server responds with the public key in PEM format.
 type GetPublicKeyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PublicKey     string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // PEM-encoded RSA public key
+	PublicKey     string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // PEM-encoded public key
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`             // Identifier of the signing key (hex SHA-256 of the DER public key)
+	Jwks          string                 `protobuf:"bytes,3,opt,name=jwks,proto3" json:"jwks,omitempty"`                            // JWK Set document, set when PUBLIC_KEY_FORMAT_JWKS is requested
+	CoseKey       []byte                 `protobuf:"bytes,4,opt,name=cose_key,json=coseKey,proto3" json:"cose_key,omitempty"`       // CBOR encoded COSE_Key, set when PUBLIC_KEY_FORMAT_COSE_KEY is requested
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetPublicKeyResponse) Reset() {
 	*x = GetPublicKeyResponse{}
-	mi := &file_blob_v1_blob_proto_msgTypes[7]
+	mi := &file_blob_v1_blob_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -398,7 +2516,7 @@ func (x *GetPublicKeyResponse) String() string {
 func (*GetPublicKeyResponse) ProtoMessage() {}
 
 func (x *GetPublicKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_blob_v1_blob_proto_msgTypes[7]
+	mi := &file_blob_v1_blob_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -411,7 +2529,7 @@ func (x *GetPublicKeyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPublicKeyResponse.ProtoReflect.Descriptor instead.
 func (*GetPublicKeyResponse) Descriptor() ([]byte, []int) {
-	return file_blob_v1_blob_proto_rawDescGZIP(), []int{7}
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *GetPublicKeyResponse) GetPublicKey() string {
@@ -421,37 +2539,335 @@ func (x *GetPublicKeyResponse) GetPublicKey() string {
 	return ""
 }
 
+func (x *GetPublicKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GetPublicKeyResponse) GetJwks() string {
+	if x != nil {
+		return x.Jwks
+	}
+	return ""
+}
+
+func (x *GetPublicKeyResponse) GetCoseKey() []byte {
+	if x != nil {
+		return x.CoseKey
+	}
+	return nil
+}
+
+// Client requests the X.509 certificate chain of the signing key.
+type GetCertificateChainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCertificateChainRequest) Reset() {
+	*x = GetCertificateChainRequest{}
+	mi := &file_blob_v1_blob_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCertificateChainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificateChainRequest) ProtoMessage() {}
+
+func (x *GetCertificateChainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificateChainRequest.ProtoReflect.Descriptor instead.
+func (*GetCertificateChainRequest) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{39}
+}
+
+// Server responds with the certificate chain of its signing key.
+type GetCertificateChainResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CertificateChain string                 `protobuf:"bytes,1,opt,name=certificate_chain,json=certificateChain,proto3" json:"certificate_chain,omitempty"` // PEM-encoded certificates, leaf (certifying the signing key) first
+	KeyId            string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`                                  // Identifier of the signing key certified by the leaf
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetCertificateChainResponse) Reset() {
+	*x = GetCertificateChainResponse{}
+	mi := &file_blob_v1_blob_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCertificateChainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificateChainResponse) ProtoMessage() {}
+
+func (x *GetCertificateChainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blob_v1_blob_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificateChainResponse.ProtoReflect.Descriptor instead.
+func (*GetCertificateChainResponse) Descriptor() ([]byte, []int) {
+	return file_blob_v1_blob_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetCertificateChainResponse) GetCertificateChain() string {
+	if x != nil {
+		return x.CertificateChain
+	}
+	return ""
+}
+
+func (x *GetCertificateChainResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
 var File_blob_v1_blob_proto protoreflect.FileDescriptor
 
 const file_blob_v1_blob_proto_rawDesc = "" +
 	"\n" +
-	"\x12blob/v1/blob.proto\x12\ablob.v1\"&\n" +
+	"\x12blob/v1/blob.proto\x12\ablob.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8c\x02\n" +
 	"\x10StoreBlobRequest\x12\x12\n" +
-	"\x04blob\x18\x01 \x01(\tR\x04blob\"'\n" +
+	"\x04blob\x18\x01 \x01(\tR\x04blob\x12+\n" +
+	"\x03ttl\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12=\n" +
+	"\x06labels\x18\x03 \x03(\v2%.blob.v1.StoreBlobRequest.LabelsEntryR\x06labels\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1a\n" +
+	"\bfilename\x18\x05 \x01(\tR\bfilename\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"'\n" +
 	"\x11StoreBlobResponse\x12\x12\n" +
-	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"f\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"D\n" +
+	"\x11StoreBlobsRequest\x12/\n" +
+	"\x05blobs\x18\x01 \x03(\v2\x19.blob.v1.StoreBlobRequestR\x05blobs\";\n" +
+	"\x0fStoreBlobResult\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"H\n" +
+	"\x12StoreBlobsResponse\x122\n" +
+	"\aresults\x18\x01 \x03(\v2\x18.blob.v1.StoreBlobResultR\aresults\"\xe8\x02\n" +
 	"\n" +
 	"BlobRecord\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
 	"\x04blob\x18\x02 \x01(\tR\x04blob\x12\x12\n" +
 	"\x04hash\x18\x03 \x01(\tR\x04hash\x12\x1c\n" +
-	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\"*\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12\x1a\n" +
+	"\bdetached\x18\x05 \x01(\bR\bdetached\x12\x1a\n" +
+	"\bfilename\x18\x06 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04size\x18\a \x01(\x03R\x04size\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\tR\texpiresAt\x127\n" +
+	"\x06labels\x18\t \x03(\v2\x1f.blob.v1.BlobRecord.LabelsEntryR\x06labels\x12!\n" +
+	"\fcontent_type\x18\n" +
+	" \x01(\tR\vcontentType\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x95\x01\n" +
+	"\x11SignDigestRequest\x12#\n" +
+	"\rsha256_digest\x18\x01 \x01(\tR\fsha256Digest\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12+\n" +
+	"\x03ttl\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\"(\n" +
+	"\x12SignDigestResponse\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"]\n" +
 	"\x14GetSignedBlobRequest\x12\x12\n" +
-	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"d\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x121\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x19.blob.v1.SignedBlobFormatR\x06format\"e\n" +
+	"\x10Countersignature\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\"e\n" +
+	"\x1aAddCountersignatureRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\fR\tsignature\"d\n" +
+	"\x1bAddCountersignatureResponse\x12E\n" +
+	"\x10countersignature\x18\x01 \x01(\v2\x19.blob.v1.CountersignatureR\x10countersignature\"\xa0\x02\n" +
 	"\x15GetSignedBlobResponse\x12-\n" +
 	"\apayload\x18\x01 \x01(\v2\x13.blob.v1.BlobRecordR\apayload\x12\x1c\n" +
-	"\tsignature\x18\x02 \x01(\fR\tsignature\"_\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12\x10\n" +
+	"\x03jws\x18\x03 \x01(\tR\x03jws\x12\x1d\n" +
+	"\n" +
+	"cose_sign1\x18\x04 \x01(\fR\tcoseSign1\x12G\n" +
+	"\x11countersignatures\x18\x05 \x03(\v2\x19.blob.v1.CountersignatureR\x11countersignatures\x12@\n" +
+	"\x0finclusion_proof\x18\x06 \x01(\v2\x17.blob.v1.InclusionProofR\x0einclusionProof\"d\n" +
+	"\x0eInclusionProof\x12\x1d\n" +
+	"\n" +
+	"leaf_index\x18\x01 \x01(\x03R\tleafIndex\x12\x1b\n" +
+	"\ttree_size\x18\x02 \x01(\x03R\btreeSize\x12\x16\n" +
+	"\x06hashes\x18\x03 \x03(\fR\x06hashes\"J\n" +
+	"\x0eMerkleTreeHead\x12\x1b\n" +
+	"\troot_hash\x18\x01 \x01(\fR\brootHash\x12\x1b\n" +
+	"\ttree_size\x18\x02 \x01(\x03R\btreeSize\"`\n" +
+	"\x15GetSignedBlobsRequest\x12\x14\n" +
+	"\x05uuids\x18\x01 \x03(\tR\x05uuids\x121\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x19.blob.v1.SignedBlobFormatR\x06format\"_\n" +
+	"\x13GetSignedBlobResult\x122\n" +
+	"\x04blob\x18\x01 \x01(\v2\x1e.blob.v1.GetSignedBlobResponseR\x04blob\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"P\n" +
+	"\x16GetSignedBlobsResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.blob.v1.GetSignedBlobResultR\aresults\"\xb8\x01\n" +
 	"\x10SignedBlobRecord\x12-\n" +
 	"\apayload\x18\x01 \x01(\v2\x13.blob.v1.BlobRecordR\apayload\x12\x1c\n" +
-	"\tsignature\x18\x02 \x01(\fR\tsignature\"\x15\n" +
-	"\x13GetPublicKeyRequest\"5\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12\x15\n" +
+	"\x06key_id\x18\x03 \x01(\tR\x05keyId\x12@\n" +
+	"\x0finclusion_proof\x18\x04 \x01(\v2\x17.blob.v1.InclusionProofR\x0einclusionProof\"\xce\x01\n" +
+	"\x11VerifyBlobRequest\x12+\n" +
+	"\x06record\x18\x01 \x01(\v2\x13.blob.v1.BlobRecordR\x06record\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\x12\x1c\n" +
+	"\tsignature\x18\x04 \x01(\fR\tsignature\x12@\n" +
+	"\x0finclusion_proof\x18\x05 \x01(\v2\x17.blob.v1.InclusionProofR\x0einclusionProof\"\x8f\x01\n" +
+	"\x12VerifyBlobResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x126\n" +
+	"\afailure\x18\x03 \x01(\x0e2\x1c.blob.v1.VerificationFailureR\afailure\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"'\n" +
+	"\x11BlobExistsRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\",\n" +
+	"\x12BlobExistsResponse\x12\x16\n" +
+	"\x06exists\x18\x01 \x01(\bR\x06exists\",\n" +
+	"\x16GetBlobMetadataRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x8e\x03\n" +
+	"\fBlobMetadata\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x15\n" +
+	"\x06key_id\x18\x05 \x01(\tR\x05keyId\x12\x1a\n" +
+	"\bdetached\x18\x06 \x01(\bR\bdetached\x12\x1a\n" +
+	"\bfilename\x18\a \x01(\tR\bfilename\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"legal_hold\x18\t \x01(\bR\tlegalHold\x129\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2!.blob.v1.BlobMetadata.LabelsEntryR\x06labels\x12!\n" +
+	"\fcontent_type\x18\v \x01(\tR\vcontentType\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"L\n" +
+	"\x17GetBlobMetadataResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.blob.v1.BlobMetadataR\bmetadata\"\xba\x02\n" +
+	"\x10ListBlobsRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12=\n" +
+	"\x06labels\x18\x05 \x03(\v2%.blob.v1.ListBlobsRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"h\n" +
+	"\x11ListBlobsResponse\x12+\n" +
+	"\x05blobs\x18\x01 \x03(\v2\x15.blob.v1.BlobMetadataR\x05blobs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x8f\x01\n" +
+	"\tTombstone\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\x05 \x01(\tR\tdeletedAt\"t\n" +
+	"\x0fSignedTombstone\x12,\n" +
+	"\apayload\x18\x01 \x01(\v2\x12.blob.v1.TombstoneR\apayload\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12\x15\n" +
+	"\x06key_id\x18\x03 \x01(\tR\x05keyId\")\n" +
+	"\x13GetTombstoneRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"N\n" +
+	"\x14GetTombstoneResponse\x126\n" +
+	"\ttombstone\x18\x01 \x01(\v2\x18.blob.v1.SignedTombstoneR\ttombstone\"\x91\x01\n" +
+	"\x0eLegalHoldEvent\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04held\x18\x02 \x01(\bR\x04held\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12!\n" +
+	"\frequested_by\x18\x04 \x01(\tR\vrequestedBy\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\tR\ttimestamp\"x\n" +
+	"\x13SetLegalHoldRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04held\x18\x02 \x01(\bR\x04held\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12!\n" +
+	"\frequested_by\x18\x04 \x01(\tR\vrequestedBy\"E\n" +
+	"\x14SetLegalHoldResponse\x12-\n" +
+	"\x05event\x18\x01 \x01(\v2\x17.blob.v1.LegalHoldEventR\x05event\"0\n" +
+	"\x1aGetLegalHoldHistoryRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"N\n" +
+	"\x1bGetLegalHoldHistoryResponse\x12/\n" +
+	"\x06events\x18\x01 \x03(\v2\x17.blob.v1.LegalHoldEventR\x06events\"G\n" +
+	"\x13GetPublicKeyRequest\x120\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x18.blob.v1.PublicKeyFormatR\x06format\"{\n" +
 	"\x14GetPublicKeyResponse\x12\x1d\n" +
 	"\n" +
-	"public_key\x18\x01 \x01(\tR\tpublicKey2\xee\x01\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12\x12\n" +
+	"\x04jwks\x18\x03 \x01(\tR\x04jwks\x12\x19\n" +
+	"\bcose_key\x18\x04 \x01(\fR\acoseKey\"\x1c\n" +
+	"\x1aGetCertificateChainRequest\"a\n" +
+	"\x1bGetCertificateChainResponse\x12+\n" +
+	"\x11certificate_chain\x18\x01 \x01(\tR\x10certificateChain\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId*u\n" +
+	"\x10SignedBlobFormat\x12\"\n" +
+	"\x1eSIGNED_BLOB_FORMAT_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16SIGNED_BLOB_FORMAT_JWS\x10\x01\x12!\n" +
+	"\x1dSIGNED_BLOB_FORMAT_COSE_SIGN1\x10\x02*\xc4\x01\n" +
+	"\x13VerificationFailure\x12$\n" +
+	" VERIFICATION_FAILURE_UNSPECIFIED\x10\x00\x12)\n" +
+	"%VERIFICATION_FAILURE_CONTENT_MISMATCH\x10\x01\x120\n" +
+	",VERIFICATION_FAILURE_INVALID_INCLUSION_PROOF\x10\x02\x12*\n" +
+	"&VERIFICATION_FAILURE_INVALID_SIGNATURE\x10\x03*p\n" +
+	"\x0fPublicKeyFormat\x12!\n" +
+	"\x1dPUBLIC_KEY_FORMAT_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PUBLIC_KEY_FORMAT_JWKS\x10\x01\x12\x1e\n" +
+	"\x1aPUBLIC_KEY_FORMAT_COSE_KEY\x10\x022\xb7\t\n" +
 	"\vBlobService\x12B\n" +
-	"\tStoreBlob\x12\x19.blob.v1.StoreBlobRequest\x1a\x1a.blob.v1.StoreBlobResponse\x12N\n" +
-	"\rGetSignedBlob\x12\x1d.blob.v1.GetSignedBlobRequest\x1a\x1e.blob.v1.GetSignedBlobResponse\x12K\n" +
-	"\fGetPublicKey\x12\x1c.blob.v1.GetPublicKeyRequest\x1a\x1d.blob.v1.GetPublicKeyResponseB\x90\x01\n" +
+	"\tStoreBlob\x12\x19.blob.v1.StoreBlobRequest\x1a\x1a.blob.v1.StoreBlobResponse\x12E\n" +
+	"\n" +
+	"StoreBlobs\x12\x1a.blob.v1.StoreBlobsRequest\x1a\x1b.blob.v1.StoreBlobsResponse\x12E\n" +
+	"\n" +
+	"SignDigest\x12\x1a.blob.v1.SignDigestRequest\x1a\x1b.blob.v1.SignDigestResponse\x12`\n" +
+	"\x13AddCountersignature\x12#.blob.v1.AddCountersignatureRequest\x1a$.blob.v1.AddCountersignatureResponse\x12N\n" +
+	"\rGetSignedBlob\x12\x1d.blob.v1.GetSignedBlobRequest\x1a\x1e.blob.v1.GetSignedBlobResponse\x12Q\n" +
+	"\x0eGetSignedBlobs\x12\x1e.blob.v1.GetSignedBlobsRequest\x1a\x1f.blob.v1.GetSignedBlobsResponse\x12E\n" +
+	"\n" +
+	"VerifyBlob\x12\x1a.blob.v1.VerifyBlobRequest\x1a\x1b.blob.v1.VerifyBlobResponse\x12E\n" +
+	"\n" +
+	"BlobExists\x12\x1a.blob.v1.BlobExistsRequest\x1a\x1b.blob.v1.BlobExistsResponse\x12T\n" +
+	"\x0fGetBlobMetadata\x12\x1f.blob.v1.GetBlobMetadataRequest\x1a .blob.v1.GetBlobMetadataResponse\x12B\n" +
+	"\tListBlobs\x12\x19.blob.v1.ListBlobsRequest\x1a\x1a.blob.v1.ListBlobsResponse\x12K\n" +
+	"\fGetTombstone\x12\x1c.blob.v1.GetTombstoneRequest\x1a\x1d.blob.v1.GetTombstoneResponse\x12K\n" +
+	"\fSetLegalHold\x12\x1c.blob.v1.SetLegalHoldRequest\x1a\x1d.blob.v1.SetLegalHoldResponse\x12`\n" +
+	"\x13GetLegalHoldHistory\x12#.blob.v1.GetLegalHoldHistoryRequest\x1a$.blob.v1.GetLegalHoldHistoryResponse\x12K\n" +
+	"\fGetPublicKey\x12\x1c.blob.v1.GetPublicKeyRequest\x1a\x1d.blob.v1.GetPublicKeyResponse\x12`\n" +
+	"\x13GetCertificateChain\x12#.blob.v1.GetCertificateChainRequest\x1a$.blob.v1.GetCertificateChainResponseB\x90\x01\n" +
 	"\vcom.blob.v1B\tBlobProtoP\x01Z9github.com/prit342/signed-blob-service/gen/blob/v1;blobv1\xa2\x02\x03BXX\xaa\x02\aBlob.V1\xca\x02\aBlob\\V1\xe2\x02\x13Blob\\V1\\GPBMetadata\xea\x02\bBlob::V1b\x06proto3"
 
 var (
@@ -466,31 +2882,126 @@ func file_blob_v1_blob_proto_rawDescGZIP() []byte {
 	return file_blob_v1_blob_proto_rawDescData
 }
 
-var file_blob_v1_blob_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_blob_v1_blob_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_blob_v1_blob_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_blob_v1_blob_proto_goTypes = []any{
-	(*StoreBlobRequest)(nil),      // 0: blob.v1.StoreBlobRequest
-	(*StoreBlobResponse)(nil),     // 1: blob.v1.StoreBlobResponse
-	(*BlobRecord)(nil),            // 2: blob.v1.BlobRecord
-	(*GetSignedBlobRequest)(nil),  // 3: blob.v1.GetSignedBlobRequest
-	(*GetSignedBlobResponse)(nil), // 4: blob.v1.GetSignedBlobResponse
-	(*SignedBlobRecord)(nil),      // 5: blob.v1.SignedBlobRecord
-	(*GetPublicKeyRequest)(nil),   // 6: blob.v1.GetPublicKeyRequest
-	(*GetPublicKeyResponse)(nil),  // 7: blob.v1.GetPublicKeyResponse
+	(SignedBlobFormat)(0),               // 0: blob.v1.SignedBlobFormat
+	(VerificationFailure)(0),            // 1: blob.v1.VerificationFailure
+	(PublicKeyFormat)(0),                // 2: blob.v1.PublicKeyFormat
+	(*StoreBlobRequest)(nil),            // 3: blob.v1.StoreBlobRequest
+	(*StoreBlobResponse)(nil),           // 4: blob.v1.StoreBlobResponse
+	(*StoreBlobsRequest)(nil),           // 5: blob.v1.StoreBlobsRequest
+	(*StoreBlobResult)(nil),             // 6: blob.v1.StoreBlobResult
+	(*StoreBlobsResponse)(nil),          // 7: blob.v1.StoreBlobsResponse
+	(*BlobRecord)(nil),                  // 8: blob.v1.BlobRecord
+	(*SignDigestRequest)(nil),           // 9: blob.v1.SignDigestRequest
+	(*SignDigestResponse)(nil),          // 10: blob.v1.SignDigestResponse
+	(*GetSignedBlobRequest)(nil),        // 11: blob.v1.GetSignedBlobRequest
+	(*Countersignature)(nil),            // 12: blob.v1.Countersignature
+	(*AddCountersignatureRequest)(nil),  // 13: blob.v1.AddCountersignatureRequest
+	(*AddCountersignatureResponse)(nil), // 14: blob.v1.AddCountersignatureResponse
+	(*GetSignedBlobResponse)(nil),       // 15: blob.v1.GetSignedBlobResponse
+	(*InclusionProof)(nil),              // 16: blob.v1.InclusionProof
+	(*MerkleTreeHead)(nil),              // 17: blob.v1.MerkleTreeHead
+	(*GetSignedBlobsRequest)(nil),       // 18: blob.v1.GetSignedBlobsRequest
+	(*GetSignedBlobResult)(nil),         // 19: blob.v1.GetSignedBlobResult
+	(*GetSignedBlobsResponse)(nil),      // 20: blob.v1.GetSignedBlobsResponse
+	(*SignedBlobRecord)(nil),            // 21: blob.v1.SignedBlobRecord
+	(*VerifyBlobRequest)(nil),           // 22: blob.v1.VerifyBlobRequest
+	(*VerifyBlobResponse)(nil),          // 23: blob.v1.VerifyBlobResponse
+	(*BlobExistsRequest)(nil),           // 24: blob.v1.BlobExistsRequest
+	(*BlobExistsResponse)(nil),          // 25: blob.v1.BlobExistsResponse
+	(*GetBlobMetadataRequest)(nil),      // 26: blob.v1.GetBlobMetadataRequest
+	(*BlobMetadata)(nil),                // 27: blob.v1.BlobMetadata
+	(*GetBlobMetadataResponse)(nil),     // 28: blob.v1.GetBlobMetadataResponse
+	(*ListBlobsRequest)(nil),            // 29: blob.v1.ListBlobsRequest
+	(*ListBlobsResponse)(nil),           // 30: blob.v1.ListBlobsResponse
+	(*Tombstone)(nil),                   // 31: blob.v1.Tombstone
+	(*SignedTombstone)(nil),             // 32: blob.v1.SignedTombstone
+	(*GetTombstoneRequest)(nil),         // 33: blob.v1.GetTombstoneRequest
+	(*GetTombstoneResponse)(nil),        // 34: blob.v1.GetTombstoneResponse
+	(*LegalHoldEvent)(nil),              // 35: blob.v1.LegalHoldEvent
+	(*SetLegalHoldRequest)(nil),         // 36: blob.v1.SetLegalHoldRequest
+	(*SetLegalHoldResponse)(nil),        // 37: blob.v1.SetLegalHoldResponse
+	(*GetLegalHoldHistoryRequest)(nil),  // 38: blob.v1.GetLegalHoldHistoryRequest
+	(*GetLegalHoldHistoryResponse)(nil), // 39: blob.v1.GetLegalHoldHistoryResponse
+	(*GetPublicKeyRequest)(nil),         // 40: blob.v1.GetPublicKeyRequest
+	(*GetPublicKeyResponse)(nil),        // 41: blob.v1.GetPublicKeyResponse
+	(*GetCertificateChainRequest)(nil),  // 42: blob.v1.GetCertificateChainRequest
+	(*GetCertificateChainResponse)(nil), // 43: blob.v1.GetCertificateChainResponse
+	nil,                                 // 44: blob.v1.StoreBlobRequest.LabelsEntry
+	nil,                                 // 45: blob.v1.BlobRecord.LabelsEntry
+	nil,                                 // 46: blob.v1.BlobMetadata.LabelsEntry
+	nil,                                 // 47: blob.v1.ListBlobsRequest.LabelsEntry
+	(*durationpb.Duration)(nil),         // 48: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),       // 49: google.protobuf.Timestamp
 }
 var file_blob_v1_blob_proto_depIdxs = []int32{
-	2, // 0: blob.v1.GetSignedBlobResponse.payload:type_name -> blob.v1.BlobRecord
-	2, // 1: blob.v1.SignedBlobRecord.payload:type_name -> blob.v1.BlobRecord
-	0, // 2: blob.v1.BlobService.StoreBlob:input_type -> blob.v1.StoreBlobRequest
-	3, // 3: blob.v1.BlobService.GetSignedBlob:input_type -> blob.v1.GetSignedBlobRequest
-	6, // 4: blob.v1.BlobService.GetPublicKey:input_type -> blob.v1.GetPublicKeyRequest
-	1, // 5: blob.v1.BlobService.StoreBlob:output_type -> blob.v1.StoreBlobResponse
-	4, // 6: blob.v1.BlobService.GetSignedBlob:output_type -> blob.v1.GetSignedBlobResponse
-	7, // 7: blob.v1.BlobService.GetPublicKey:output_type -> blob.v1.GetPublicKeyResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	48, // 0: blob.v1.StoreBlobRequest.ttl:type_name -> google.protobuf.Duration
+	44, // 1: blob.v1.StoreBlobRequest.labels:type_name -> blob.v1.StoreBlobRequest.LabelsEntry
+	3,  // 2: blob.v1.StoreBlobsRequest.blobs:type_name -> blob.v1.StoreBlobRequest
+	6,  // 3: blob.v1.StoreBlobsResponse.results:type_name -> blob.v1.StoreBlobResult
+	45, // 4: blob.v1.BlobRecord.labels:type_name -> blob.v1.BlobRecord.LabelsEntry
+	48, // 5: blob.v1.SignDigestRequest.ttl:type_name -> google.protobuf.Duration
+	0,  // 6: blob.v1.GetSignedBlobRequest.format:type_name -> blob.v1.SignedBlobFormat
+	12, // 7: blob.v1.AddCountersignatureResponse.countersignature:type_name -> blob.v1.Countersignature
+	8,  // 8: blob.v1.GetSignedBlobResponse.payload:type_name -> blob.v1.BlobRecord
+	12, // 9: blob.v1.GetSignedBlobResponse.countersignatures:type_name -> blob.v1.Countersignature
+	16, // 10: blob.v1.GetSignedBlobResponse.inclusion_proof:type_name -> blob.v1.InclusionProof
+	0,  // 11: blob.v1.GetSignedBlobsRequest.format:type_name -> blob.v1.SignedBlobFormat
+	15, // 12: blob.v1.GetSignedBlobResult.blob:type_name -> blob.v1.GetSignedBlobResponse
+	19, // 13: blob.v1.GetSignedBlobsResponse.results:type_name -> blob.v1.GetSignedBlobResult
+	8,  // 14: blob.v1.SignedBlobRecord.payload:type_name -> blob.v1.BlobRecord
+	16, // 15: blob.v1.SignedBlobRecord.inclusion_proof:type_name -> blob.v1.InclusionProof
+	8,  // 16: blob.v1.VerifyBlobRequest.record:type_name -> blob.v1.BlobRecord
+	16, // 17: blob.v1.VerifyBlobRequest.inclusion_proof:type_name -> blob.v1.InclusionProof
+	1,  // 18: blob.v1.VerifyBlobResponse.failure:type_name -> blob.v1.VerificationFailure
+	46, // 19: blob.v1.BlobMetadata.labels:type_name -> blob.v1.BlobMetadata.LabelsEntry
+	27, // 20: blob.v1.GetBlobMetadataResponse.metadata:type_name -> blob.v1.BlobMetadata
+	49, // 21: blob.v1.ListBlobsRequest.start_time:type_name -> google.protobuf.Timestamp
+	49, // 22: blob.v1.ListBlobsRequest.end_time:type_name -> google.protobuf.Timestamp
+	47, // 23: blob.v1.ListBlobsRequest.labels:type_name -> blob.v1.ListBlobsRequest.LabelsEntry
+	27, // 24: blob.v1.ListBlobsResponse.blobs:type_name -> blob.v1.BlobMetadata
+	31, // 25: blob.v1.SignedTombstone.payload:type_name -> blob.v1.Tombstone
+	32, // 26: blob.v1.GetTombstoneResponse.tombstone:type_name -> blob.v1.SignedTombstone
+	35, // 27: blob.v1.SetLegalHoldResponse.event:type_name -> blob.v1.LegalHoldEvent
+	35, // 28: blob.v1.GetLegalHoldHistoryResponse.events:type_name -> blob.v1.LegalHoldEvent
+	2,  // 29: blob.v1.GetPublicKeyRequest.format:type_name -> blob.v1.PublicKeyFormat
+	3,  // 30: blob.v1.BlobService.StoreBlob:input_type -> blob.v1.StoreBlobRequest
+	5,  // 31: blob.v1.BlobService.StoreBlobs:input_type -> blob.v1.StoreBlobsRequest
+	9,  // 32: blob.v1.BlobService.SignDigest:input_type -> blob.v1.SignDigestRequest
+	13, // 33: blob.v1.BlobService.AddCountersignature:input_type -> blob.v1.AddCountersignatureRequest
+	11, // 34: blob.v1.BlobService.GetSignedBlob:input_type -> blob.v1.GetSignedBlobRequest
+	18, // 35: blob.v1.BlobService.GetSignedBlobs:input_type -> blob.v1.GetSignedBlobsRequest
+	22, // 36: blob.v1.BlobService.VerifyBlob:input_type -> blob.v1.VerifyBlobRequest
+	24, // 37: blob.v1.BlobService.BlobExists:input_type -> blob.v1.BlobExistsRequest
+	26, // 38: blob.v1.BlobService.GetBlobMetadata:input_type -> blob.v1.GetBlobMetadataRequest
+	29, // 39: blob.v1.BlobService.ListBlobs:input_type -> blob.v1.ListBlobsRequest
+	33, // 40: blob.v1.BlobService.GetTombstone:input_type -> blob.v1.GetTombstoneRequest
+	36, // 41: blob.v1.BlobService.SetLegalHold:input_type -> blob.v1.SetLegalHoldRequest
+	38, // 42: blob.v1.BlobService.GetLegalHoldHistory:input_type -> blob.v1.GetLegalHoldHistoryRequest
+	40, // 43: blob.v1.BlobService.GetPublicKey:input_type -> blob.v1.GetPublicKeyRequest
+	42, // 44: blob.v1.BlobService.GetCertificateChain:input_type -> blob.v1.GetCertificateChainRequest
+	4,  // 45: blob.v1.BlobService.StoreBlob:output_type -> blob.v1.StoreBlobResponse
+	7,  // 46: blob.v1.BlobService.StoreBlobs:output_type -> blob.v1.StoreBlobsResponse
+	10, // 47: blob.v1.BlobService.SignDigest:output_type -> blob.v1.SignDigestResponse
+	14, // 48: blob.v1.BlobService.AddCountersignature:output_type -> blob.v1.AddCountersignatureResponse
+	15, // 49: blob.v1.BlobService.GetSignedBlob:output_type -> blob.v1.GetSignedBlobResponse
+	20, // 50: blob.v1.BlobService.GetSignedBlobs:output_type -> blob.v1.GetSignedBlobsResponse
+	23, // 51: blob.v1.BlobService.VerifyBlob:output_type -> blob.v1.VerifyBlobResponse
+	25, // 52: blob.v1.BlobService.BlobExists:output_type -> blob.v1.BlobExistsResponse
+	28, // 53: blob.v1.BlobService.GetBlobMetadata:output_type -> blob.v1.GetBlobMetadataResponse
+	30, // 54: blob.v1.BlobService.ListBlobs:output_type -> blob.v1.ListBlobsResponse
+	34, // 55: blob.v1.BlobService.GetTombstone:output_type -> blob.v1.GetTombstoneResponse
+	37, // 56: blob.v1.BlobService.SetLegalHold:output_type -> blob.v1.SetLegalHoldResponse
+	39, // 57: blob.v1.BlobService.GetLegalHoldHistory:output_type -> blob.v1.GetLegalHoldHistoryResponse
+	41, // 58: blob.v1.BlobService.GetPublicKey:output_type -> blob.v1.GetPublicKeyResponse
+	43, // 59: blob.v1.BlobService.GetCertificateChain:output_type -> blob.v1.GetCertificateChainResponse
+	45, // [45:60] is the sub-list for method output_type
+	30, // [30:45] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_blob_v1_blob_proto_init() }
@@ -503,13 +3014,14 @@ func file_blob_v1_blob_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_blob_v1_blob_proto_rawDesc), len(file_blob_v1_blob_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      3,
+			NumMessages:   45,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_blob_v1_blob_proto_goTypes,
 		DependencyIndexes: file_blob_v1_blob_proto_depIdxs,
+		EnumInfos:         file_blob_v1_blob_proto_enumTypes,
 		MessageInfos:      file_blob_v1_blob_proto_msgTypes,
 	}.Build()
 	File_blob_v1_blob_proto = out.File
@@ -19,9 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BlobService_StoreBlob_FullMethodName     = "/blob.v1.BlobService/StoreBlob"
-	BlobService_GetSignedBlob_FullMethodName = "/blob.v1.BlobService/GetSignedBlob"
-	BlobService_GetPublicKey_FullMethodName  = "/blob.v1.BlobService/GetPublicKey"
+	BlobService_StoreBlob_FullMethodName           = "/blob.v1.BlobService/StoreBlob"
+	BlobService_StoreBlobs_FullMethodName          = "/blob.v1.BlobService/StoreBlobs"
+	BlobService_SignDigest_FullMethodName          = "/blob.v1.BlobService/SignDigest"
+	BlobService_AddCountersignature_FullMethodName = "/blob.v1.BlobService/AddCountersignature"
+	BlobService_GetSignedBlob_FullMethodName       = "/blob.v1.BlobService/GetSignedBlob"
+	BlobService_GetSignedBlobs_FullMethodName      = "/blob.v1.BlobService/GetSignedBlobs"
+	BlobService_VerifyBlob_FullMethodName          = "/blob.v1.BlobService/VerifyBlob"
+	BlobService_BlobExists_FullMethodName          = "/blob.v1.BlobService/BlobExists"
+	BlobService_GetBlobMetadata_FullMethodName     = "/blob.v1.BlobService/GetBlobMetadata"
+	BlobService_ListBlobs_FullMethodName           = "/blob.v1.BlobService/ListBlobs"
+	BlobService_GetTombstone_FullMethodName        = "/blob.v1.BlobService/GetTombstone"
+	BlobService_SetLegalHold_FullMethodName        = "/blob.v1.BlobService/SetLegalHold"
+	BlobService_GetLegalHoldHistory_FullMethodName = "/blob.v1.BlobService/GetLegalHoldHistory"
+	BlobService_GetPublicKey_FullMethodName        = "/blob.v1.BlobService/GetPublicKey"
+	BlobService_GetCertificateChain_FullMethodName = "/blob.v1.BlobService/GetCertificateChain"
 )
 
 // BlobServiceClient is the client API for BlobService service.
@@ -33,12 +45,44 @@ type BlobServiceClient interface {
 	// Accepts a raw text blob, returns a UUID.
 	// Server computes hash, timestamp, UUID, and signs the full BlobRecord before storing.
 	StoreBlob(ctx context.Context, in *StoreBlobRequest, opts ...grpc.CallOption) (*StoreBlobResponse, error)
+	// Signs and stores several blobs in one call and one transaction, returns a result per blob.
+	// A blob that fails validation does not prevent the others from being stored.
+	StoreBlobs(ctx context.Context, in *StoreBlobsRequest, opts ...grpc.CallOption) (*StoreBlobsResponse, error)
+	// Accepts a SHA-256 digest instead of content, returns a UUID.
+	// Server signs and records a detached BlobRecord without any content, for artifacts
+	// too large or too sensitive to upload. Verification needs the original content.
+	SignDigest(ctx context.Context, in *SignDigestRequest, opts ...grpc.CallOption) (*SignDigestResponse, error)
+	// Attaches the signature of a registered countersigning key to an existing record.
+	// The server verifies it over the stored payload before accepting it.
+	AddCountersignature(ctx context.Context, in *AddCountersignatureRequest, opts ...grpc.CallOption) (*AddCountersignatureResponse, error)
 	// Retrieves the previously signed payload and its signature by UUID.
 	// Client can then verify the signature over the returned payload.
 	GetSignedBlob(ctx context.Context, in *GetSignedBlobRequest, opts ...grpc.CallOption) (*GetSignedBlobResponse, error)
+	// Retrieves several signed blobs in one call, returns a result per UUID.
+	GetSignedBlobs(ctx context.Context, in *GetSignedBlobsRequest, opts ...grpc.CallOption) (*GetSignedBlobsResponse, error)
+	// Verifies a signature over a record, or over a stored record and the content it should match,
+	// under the server signing key and the registered countersigning keys. Reports which key made it
+	// or why it is invalid, so clients need no signature implementation of their own.
+	VerifyBlob(ctx context.Context, in *VerifyBlobRequest, opts ...grpc.CallOption) (*VerifyBlobResponse, error)
+	// Reports whether a record exists, a cheap check before storing or downloading.
+	BlobExists(ctx context.Context, in *BlobExistsRequest, opts ...grpc.CallOption) (*BlobExistsResponse, error)
+	// Returns the uuid, hash, timestamp, size and signing key ID of a record without its content.
+	GetBlobMetadata(ctx context.Context, in *GetBlobMetadataRequest, opts ...grpc.CallOption) (*GetBlobMetadataResponse, error)
+	// Lists the metadata of the records signed within a time range, oldest first, a page at a time.
+	ListBlobs(ctx context.Context, in *ListBlobsRequest, opts ...grpc.CallOption) (*ListBlobsResponse, error)
+	// Returns the signed tombstone left behind when a record expired and was deleted.
+	GetTombstone(ctx context.Context, in *GetTombstoneRequest, opts ...grpc.CallOption) (*GetTombstoneResponse, error)
+	// Places or releases a legal hold on a record. While held, the record is neither
+	// deleted nor expired. Every change is recorded in an audit log.
+	SetLegalHold(ctx context.Context, in *SetLegalHoldRequest, opts ...grpc.CallOption) (*SetLegalHoldResponse, error)
+	// Returns the legal hold audit log of a record.
+	GetLegalHoldHistory(ctx context.Context, in *GetLegalHoldHistoryRequest, opts ...grpc.CallOption) (*GetLegalHoldHistoryResponse, error)
 	// Returns the public key used for signing blobs.
 	// useful for clients to verify signatures.
 	GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error)
+	// Returns the X.509 certificate chain of the signing key, when the server is configured with one.
+	// Clients can validate it against their trusted roots instead of trusting a bare public key.
+	GetCertificateChain(ctx context.Context, in *GetCertificateChainRequest, opts ...grpc.CallOption) (*GetCertificateChainResponse, error)
 }
 
 type blobServiceClient struct {
@@ -59,6 +103,36 @@ func (c *blobServiceClient) StoreBlob(ctx context.Context, in *StoreBlobRequest,
 	return out, nil
 }
 
+func (c *blobServiceClient) StoreBlobs(ctx context.Context, in *StoreBlobsRequest, opts ...grpc.CallOption) (*StoreBlobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoreBlobsResponse)
+	err := c.cc.Invoke(ctx, BlobService_StoreBlobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) SignDigest(ctx context.Context, in *SignDigestRequest, opts ...grpc.CallOption) (*SignDigestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignDigestResponse)
+	err := c.cc.Invoke(ctx, BlobService_SignDigest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) AddCountersignature(ctx context.Context, in *AddCountersignatureRequest, opts ...grpc.CallOption) (*AddCountersignatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddCountersignatureResponse)
+	err := c.cc.Invoke(ctx, BlobService_AddCountersignature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *blobServiceClient) GetSignedBlob(ctx context.Context, in *GetSignedBlobRequest, opts ...grpc.CallOption) (*GetSignedBlobResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetSignedBlobResponse)
@@ -69,6 +143,86 @@ func (c *blobServiceClient) GetSignedBlob(ctx context.Context, in *GetSignedBlob
 	return out, nil
 }
 
+func (c *blobServiceClient) GetSignedBlobs(ctx context.Context, in *GetSignedBlobsRequest, opts ...grpc.CallOption) (*GetSignedBlobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSignedBlobsResponse)
+	err := c.cc.Invoke(ctx, BlobService_GetSignedBlobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) VerifyBlob(ctx context.Context, in *VerifyBlobRequest, opts ...grpc.CallOption) (*VerifyBlobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyBlobResponse)
+	err := c.cc.Invoke(ctx, BlobService_VerifyBlob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) BlobExists(ctx context.Context, in *BlobExistsRequest, opts ...grpc.CallOption) (*BlobExistsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlobExistsResponse)
+	err := c.cc.Invoke(ctx, BlobService_BlobExists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) GetBlobMetadata(ctx context.Context, in *GetBlobMetadataRequest, opts ...grpc.CallOption) (*GetBlobMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBlobMetadataResponse)
+	err := c.cc.Invoke(ctx, BlobService_GetBlobMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) ListBlobs(ctx context.Context, in *ListBlobsRequest, opts ...grpc.CallOption) (*ListBlobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBlobsResponse)
+	err := c.cc.Invoke(ctx, BlobService_ListBlobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) GetTombstone(ctx context.Context, in *GetTombstoneRequest, opts ...grpc.CallOption) (*GetTombstoneResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTombstoneResponse)
+	err := c.cc.Invoke(ctx, BlobService_GetTombstone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) SetLegalHold(ctx context.Context, in *SetLegalHoldRequest, opts ...grpc.CallOption) (*SetLegalHoldResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLegalHoldResponse)
+	err := c.cc.Invoke(ctx, BlobService_SetLegalHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobServiceClient) GetLegalHoldHistory(ctx context.Context, in *GetLegalHoldHistoryRequest, opts ...grpc.CallOption) (*GetLegalHoldHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLegalHoldHistoryResponse)
+	err := c.cc.Invoke(ctx, BlobService_GetLegalHoldHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *blobServiceClient) GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetPublicKeyResponse)
@@ -79,6 +233,16 @@ func (c *blobServiceClient) GetPublicKey(ctx context.Context, in *GetPublicKeyRe
 	return out, nil
 }
 
+func (c *blobServiceClient) GetCertificateChain(ctx context.Context, in *GetCertificateChainRequest, opts ...grpc.CallOption) (*GetCertificateChainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCertificateChainResponse)
+	err := c.cc.Invoke(ctx, BlobService_GetCertificateChain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BlobServiceServer is the server API for BlobService service.
 // All implementations must embed UnimplementedBlobServiceServer
 // for forward compatibility.
@@ -88,12 +252,44 @@ type BlobServiceServer interface {
 	// Accepts a raw text blob, returns a UUID.
 	// Server computes hash, timestamp, UUID, and signs the full BlobRecord before storing.
 	StoreBlob(context.Context, *StoreBlobRequest) (*StoreBlobResponse, error)
+	// Signs and stores several blobs in one call and one transaction, returns a result per blob.
+	// A blob that fails validation does not prevent the others from being stored.
+	StoreBlobs(context.Context, *StoreBlobsRequest) (*StoreBlobsResponse, error)
+	// Accepts a SHA-256 digest instead of content, returns a UUID.
+	// Server signs and records a detached BlobRecord without any content, for artifacts
+	// too large or too sensitive to upload. Verification needs the original content.
+	SignDigest(context.Context, *SignDigestRequest) (*SignDigestResponse, error)
+	// Attaches the signature of a registered countersigning key to an existing record.
+	// The server verifies it over the stored payload before accepting it.
+	AddCountersignature(context.Context, *AddCountersignatureRequest) (*AddCountersignatureResponse, error)
 	// Retrieves the previously signed payload and its signature by UUID.
 	// Client can then verify the signature over the returned payload.
 	GetSignedBlob(context.Context, *GetSignedBlobRequest) (*GetSignedBlobResponse, error)
+	// Retrieves several signed blobs in one call, returns a result per UUID.
+	GetSignedBlobs(context.Context, *GetSignedBlobsRequest) (*GetSignedBlobsResponse, error)
+	// Verifies a signature over a record, or over a stored record and the content it should match,
+	// under the server signing key and the registered countersigning keys. Reports which key made it
+	// or why it is invalid, so clients need no signature implementation of their own.
+	VerifyBlob(context.Context, *VerifyBlobRequest) (*VerifyBlobResponse, error)
+	// Reports whether a record exists, a cheap check before storing or downloading.
+	BlobExists(context.Context, *BlobExistsRequest) (*BlobExistsResponse, error)
+	// Returns the uuid, hash, timestamp, size and signing key ID of a record without its content.
+	GetBlobMetadata(context.Context, *GetBlobMetadataRequest) (*GetBlobMetadataResponse, error)
+	// Lists the metadata of the records signed within a time range, oldest first, a page at a time.
+	ListBlobs(context.Context, *ListBlobsRequest) (*ListBlobsResponse, error)
+	// Returns the signed tombstone left behind when a record expired and was deleted.
+	GetTombstone(context.Context, *GetTombstoneRequest) (*GetTombstoneResponse, error)
+	// Places or releases a legal hold on a record. While held, the record is neither
+	// deleted nor expired. Every change is recorded in an audit log.
+	SetLegalHold(context.Context, *SetLegalHoldRequest) (*SetLegalHoldResponse, error)
+	// Returns the legal hold audit log of a record.
+	GetLegalHoldHistory(context.Context, *GetLegalHoldHistoryRequest) (*GetLegalHoldHistoryResponse, error)
 	// Returns the public key used for signing blobs.
 	// useful for clients to verify signatures.
 	GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error)
+	// Returns the X.509 certificate chain of the signing key, when the server is configured with one.
+	// Clients can validate it against their trusted roots instead of trusting a bare public key.
+	GetCertificateChain(context.Context, *GetCertificateChainRequest) (*GetCertificateChainResponse, error)
 	mustEmbedUnimplementedBlobServiceServer()
 }
 
@@ -107,12 +303,48 @@ type UnimplementedBlobServiceServer struct{}
 func (UnimplementedBlobServiceServer) StoreBlob(context.Context, *StoreBlobRequest) (*StoreBlobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StoreBlob not implemented")
 }
+func (UnimplementedBlobServiceServer) StoreBlobs(context.Context, *StoreBlobsRequest) (*StoreBlobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StoreBlobs not implemented")
+}
+func (UnimplementedBlobServiceServer) SignDigest(context.Context, *SignDigestRequest) (*SignDigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignDigest not implemented")
+}
+func (UnimplementedBlobServiceServer) AddCountersignature(context.Context, *AddCountersignatureRequest) (*AddCountersignatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCountersignature not implemented")
+}
 func (UnimplementedBlobServiceServer) GetSignedBlob(context.Context, *GetSignedBlobRequest) (*GetSignedBlobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSignedBlob not implemented")
 }
+func (UnimplementedBlobServiceServer) GetSignedBlobs(context.Context, *GetSignedBlobsRequest) (*GetSignedBlobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSignedBlobs not implemented")
+}
+func (UnimplementedBlobServiceServer) VerifyBlob(context.Context, *VerifyBlobRequest) (*VerifyBlobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyBlob not implemented")
+}
+func (UnimplementedBlobServiceServer) BlobExists(context.Context, *BlobExistsRequest) (*BlobExistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlobExists not implemented")
+}
+func (UnimplementedBlobServiceServer) GetBlobMetadata(context.Context, *GetBlobMetadataRequest) (*GetBlobMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlobMetadata not implemented")
+}
+func (UnimplementedBlobServiceServer) ListBlobs(context.Context, *ListBlobsRequest) (*ListBlobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlobs not implemented")
+}
+func (UnimplementedBlobServiceServer) GetTombstone(context.Context, *GetTombstoneRequest) (*GetTombstoneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTombstone not implemented")
+}
+func (UnimplementedBlobServiceServer) SetLegalHold(context.Context, *SetLegalHoldRequest) (*SetLegalHoldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLegalHold not implemented")
+}
+func (UnimplementedBlobServiceServer) GetLegalHoldHistory(context.Context, *GetLegalHoldHistoryRequest) (*GetLegalHoldHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLegalHoldHistory not implemented")
+}
 func (UnimplementedBlobServiceServer) GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
 }
+func (UnimplementedBlobServiceServer) GetCertificateChain(context.Context, *GetCertificateChainRequest) (*GetCertificateChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCertificateChain not implemented")
+}
 func (UnimplementedBlobServiceServer) mustEmbedUnimplementedBlobServiceServer() {}
 func (UnimplementedBlobServiceServer) testEmbeddedByValue()                     {}
 
@@ -152,6 +384,60 @@ func _BlobService_StoreBlob_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlobService_StoreBlobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreBlobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).StoreBlobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_StoreBlobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).StoreBlobs(ctx, req.(*StoreBlobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_SignDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).SignDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_SignDigest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).SignDigest(ctx, req.(*SignDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_AddCountersignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCountersignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).AddCountersignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_AddCountersignature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).AddCountersignature(ctx, req.(*AddCountersignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BlobService_GetSignedBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSignedBlobRequest)
 	if err := dec(in); err != nil {
@@ -170,6 +456,150 @@ func _BlobService_GetSignedBlob_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlobService_GetSignedBlobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSignedBlobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).GetSignedBlobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_GetSignedBlobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).GetSignedBlobs(ctx, req.(*GetSignedBlobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_VerifyBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).VerifyBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_VerifyBlob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).VerifyBlob(ctx, req.(*VerifyBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_BlobExists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlobExistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).BlobExists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_BlobExists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).BlobExists(ctx, req.(*BlobExistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_GetBlobMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlobMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).GetBlobMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_GetBlobMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).GetBlobMetadata(ctx, req.(*GetBlobMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_ListBlobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).ListBlobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_ListBlobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).ListBlobs(ctx, req.(*ListBlobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_GetTombstone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTombstoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).GetTombstone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_GetTombstone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).GetTombstone(ctx, req.(*GetTombstoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_SetLegalHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLegalHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).SetLegalHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_SetLegalHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).SetLegalHold(ctx, req.(*SetLegalHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobService_GetLegalHoldHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLegalHoldHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).GetLegalHoldHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_GetLegalHoldHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).GetLegalHoldHistory(ctx, req.(*GetLegalHoldHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BlobService_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetPublicKeyRequest)
 	if err := dec(in); err != nil {
@@ -188,6 +618,24 @@ func _BlobService_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlobService_GetCertificateChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCertificateChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobServiceServer).GetCertificateChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobService_GetCertificateChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobServiceServer).GetCertificateChain(ctx, req.(*GetCertificateChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BlobService_ServiceDesc is the grpc.ServiceDesc for BlobService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -199,14 +647,62 @@ var BlobService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StoreBlob",
 			Handler:    _BlobService_StoreBlob_Handler,
 		},
+		{
+			MethodName: "StoreBlobs",
+			Handler:    _BlobService_StoreBlobs_Handler,
+		},
+		{
+			MethodName: "SignDigest",
+			Handler:    _BlobService_SignDigest_Handler,
+		},
+		{
+			MethodName: "AddCountersignature",
+			Handler:    _BlobService_AddCountersignature_Handler,
+		},
 		{
 			MethodName: "GetSignedBlob",
 			Handler:    _BlobService_GetSignedBlob_Handler,
 		},
+		{
+			MethodName: "GetSignedBlobs",
+			Handler:    _BlobService_GetSignedBlobs_Handler,
+		},
+		{
+			MethodName: "VerifyBlob",
+			Handler:    _BlobService_VerifyBlob_Handler,
+		},
+		{
+			MethodName: "BlobExists",
+			Handler:    _BlobService_BlobExists_Handler,
+		},
+		{
+			MethodName: "GetBlobMetadata",
+			Handler:    _BlobService_GetBlobMetadata_Handler,
+		},
+		{
+			MethodName: "ListBlobs",
+			Handler:    _BlobService_ListBlobs_Handler,
+		},
+		{
+			MethodName: "GetTombstone",
+			Handler:    _BlobService_GetTombstone_Handler,
+		},
+		{
+			MethodName: "SetLegalHold",
+			Handler:    _BlobService_SetLegalHold_Handler,
+		},
+		{
+			MethodName: "GetLegalHoldHistory",
+			Handler:    _BlobService_GetLegalHoldHistory_Handler,
+		},
 		{
 			MethodName: "GetPublicKey",
 			Handler:    _BlobService_GetPublicKey_Handler,
 		},
+		{
+			MethodName: "GetCertificateChain",
+			Handler:    _BlobService_GetCertificateChain_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "blob/v1/blob.proto",
@@ -0,0 +1,75 @@
+package jose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/signature/signaturetest"
+)
+
+func TestSignCompactAndJWKSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		algorithm   signature.Algorithm
+		expectedAlg string
+		expectedKty string
+	}{
+		{name: "RSA signer uses PS256", algorithm: signature.AlgorithmRSAPSSSHA256, expectedAlg: AlgorithmPS256, expectedKty: "RSA"},
+		{name: "ECDSA signer uses ES256", algorithm: signature.AlgorithmECDSAP256SHA256, expectedAlg: AlgorithmES256, expectedKty: "EC"},
+		{name: "Ed25519 signer uses EdDSA", algorithm: signature.AlgorithmEd25519, expectedAlg: AlgorithmEdDSA, expectedKty: "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			signer := signaturetest.NewSignerFor(t, tt.algorithm)
+			payload := []byte(`{"uuid":"10315b7a-0000-0000-0000-000000000000","blob":"hello"}`)
+
+			jws, err := SignCompact(signer, payload)
+			if err != nil {
+				t.Fatalf("failed to sign JWS: %v", err)
+			}
+
+			// verify using the key from the JWK Set as a JOSE library would
+			set, err := PublicJWKSet(signer)
+			if err != nil {
+				t.Fatalf("failed to build JWK Set: %v", err)
+			}
+			if len(set.Keys) != 1 {
+				t.Fatalf("expected exactly one key in the JWK Set, got %d", len(set.Keys))
+			}
+			jwk := set.Keys[0]
+			if jwk.KeyType != tt.expectedKty || jwk.Algorithm != tt.expectedAlg || jwk.KeyID != signer.KeyID() {
+				t.Fatalf("unexpected JWK %+v", jwk)
+			}
+			pub, err := jwk.PublicKey()
+			if err != nil {
+				t.Fatalf("failed to decode JWK: %v", err)
+			}
+
+			header, got, err := VerifyCompact(jws, pub)
+			if err != nil {
+				t.Fatalf("failed to verify JWS: %v", err)
+			}
+			if header.Algorithm != tt.expectedAlg {
+				t.Fatalf("expected alg %q but got %q", tt.expectedAlg, header.Algorithm)
+			}
+			if header.KeyID != signer.KeyID() {
+				t.Fatalf("expected kid %q but got %q", signer.KeyID(), header.KeyID)
+			}
+			if string(got) != string(payload) {
+				t.Fatalf("payload mismatch: %q", got)
+			}
+
+			// swapping the payload must invalidate the signature
+			parts := strings.Split(jws, ".")
+			tampered := parts[0] + "." + encodeSegment([]byte(`{"blob":"tampered"}`)) + "." + parts[2]
+			if _, _, err := VerifyCompact(tampered, pub); err == nil {
+				t.Fatal("expected verification of a tampered JWS to fail")
+			}
+		})
+	}
+}
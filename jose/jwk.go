@@ -0,0 +1,160 @@
+package jose
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/prit342/signed-blob-service/signature"
+)
+
+// JWK is a JSON Web Key holding a public signing key (RFC 7517, RFC 7518 and RFC 8037).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	// RSA public key members
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC and OKP public key members
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JWKSet is a JWK Set document, the format served at a JWKS endpoint.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK builds the public JWK for the given key.
+// alg is the signer algorithm the key is used with and is mapped to its JWS name.
+func NewJWK(publicKey crypto.PublicKey, kid string, alg signature.Algorithm) (*JWK, error) {
+	jwsAlg, err := AlgorithmFor(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk := &JWK{
+		KeyID:     kid,
+		Use:       "sig",
+		Algorithm: jwsAlg,
+	}
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeSegment(pub.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported elliptic curve %s", pub.Curve.Params().Name)
+		}
+		// coordinates are encoded as fixed size big-endian integers
+		x := make([]byte, p256CoordinateSize)
+		y := make([]byte, p256CoordinateSize)
+		pub.X.FillBytes(x)
+		pub.Y.FillBytes(y)
+		jwk.KeyType = "EC"
+		jwk.Curve = "P-256"
+		jwk.X = encodeSegment(x)
+		jwk.Y = encodeSegment(y)
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeSegment(pub)
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return jwk, nil
+}
+
+// PublicKey returns the Go public key described by the JWK.
+func (j *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := decodeSegment(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeSegment(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		if len(n) == 0 || len(e) == 0 {
+			return nil, errors.New("RSA JWK is missing its modulus or exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if j.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported EC curve %q", j.Curve)
+		}
+		x, err := decodeSegment(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeSegment(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		// validate that the point is on the curve before using it
+		point := append([]byte{0x04}, append(x, y...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid EC public key: %w", err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", j.Curve)
+		}
+		x, err := decodeSegment(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Ed25519 public key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key length")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type %q", j.KeyType)
+	}
+}
+
+// PublicJWKSet returns a JWK Set containing the signer's public key.
+func PublicJWKSet(signer signature.Signer) (*JWKSet, error) {
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+
+	pemBytes, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve public key: %w", err)
+	}
+
+	publicKey, err := signature.ParsePublicKeyPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk, err := NewJWK(publicKey, signer.KeyID(), signer.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWKSet{Keys: []JWK{*jwk}}, nil
+}
@@ -0,0 +1,149 @@
+// Package jose encodes signed blob records and public keys using JOSE
+// (JWS compact serialisation, RFC 7515, and JWK sets, RFC 7517) so that
+// off-the-shelf JOSE libraries can verify them.
+package jose
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prit342/signed-blob-service/signature"
+)
+
+// JWS algorithm names (RFC 7518 and RFC 8037)
+const (
+	AlgorithmPS256 = "PS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// p256CoordinateSize is the size in bytes of each P-256 coordinate in a JWK.
+const p256CoordinateSize = 32
+
+// Header is the protected header of the JWS objects produced by the service.
+type Header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// AlgorithmFor maps a signer algorithm to its JWS "alg" name.
+func AlgorithmFor(alg signature.Algorithm) (string, error) {
+	switch alg {
+	case signature.AlgorithmRSAPSSSHA256:
+		return AlgorithmPS256, nil
+	case signature.AlgorithmECDSAP256SHA256:
+		return AlgorithmES256, nil
+	case signature.AlgorithmEd25519:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", fmt.Errorf("signature algorithm %q has no JWS equivalent", alg)
+	}
+}
+
+// SignCompact signs the payload with the given signer and returns the JWS compact serialisation.
+// The protected header carries the algorithm matching the signer and its key ID.
+func SignCompact(signer signature.Signer, payload []byte) (string, error) {
+	if signer == nil {
+		return "", errors.New("signer cannot be nil")
+	}
+
+	alg, err := AlgorithmFor(signer.Algorithm())
+	if err != nil {
+		return "", err
+	}
+
+	headerBytes, err := json.Marshal(Header{
+		Algorithm: alg,
+		KeyID:     signer.KeyID(),
+		Type:      "JOSE",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWS header: %w", err)
+	}
+
+	// the JWS signing input is the ASCII string BASE64URL(header) || '.' || BASE64URL(payload)
+	signingInput := encodeSegment(headerBytes) + "." + encodeSegment(payload)
+
+	sig, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWS: %w", err)
+	}
+
+	// JWS uses the raw R || S form for ECDSA rather than ASN.1 DER
+	if alg == AlgorithmES256 {
+		if sig, err = signature.ECDSASignatureToRaw(sig); err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + encodeSegment(sig), nil
+}
+
+// VerifyCompact verifies a JWS compact serialisation against the given public key
+// and returns its protected header and payload.
+func VerifyCompact(jws string, publicKey crypto.PublicKey) (*Header, []byte, error) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("JWS compact serialisation must have exactly three parts")
+	}
+
+	headerBytes, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JWS header encoding: %w", err)
+	}
+	var header Header
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, nil, fmt.Errorf("invalid JWS header: %w", err)
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JWS payload encoding: %w", err)
+	}
+
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JWS signature encoding: %w", err)
+	}
+
+	// the algorithm in the header must match the key, never trust the header alone
+	keyAlg, err := signature.AlgorithmForPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	expectedAlg, err := AlgorithmFor(keyAlg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Algorithm != expectedAlg {
+		return nil, nil, fmt.Errorf("JWS algorithm %q does not match the %s public key", header.Algorithm, expectedAlg)
+	}
+
+	if header.Algorithm == AlgorithmES256 {
+		if sig, err = signature.ECDSASignatureFromRaw(sig); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+	if err := signature.VerifyWithPublicKey(publicKey, signingInput, sig); err != nil {
+		return nil, nil, fmt.Errorf("JWS signature verification failed: %w", err)
+	}
+
+	return &header, payload, nil
+}
+
+// encodeSegment returns the unpadded base64url encoding used by JOSE.
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeSegment decodes an unpadded base64url JOSE segment.
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,116 @@
+// Package merkle builds the Merkle trees of batch-signed records and checks inclusion proofs
+// against their root. Trees and proofs follow RFC 9162 (Certificate Transparency 2.0) with
+// SHA-256, so leaves and interior nodes are hashed with different prefixes and a leaf can
+// never be passed off as a subtree.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// domain separation prefixes of RFC 9162, section 2.1.1
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// ErrInvalidProof is returned when an inclusion proof does not lead to the expected root
+var ErrInvalidProof = errors.New("invalid inclusion proof")
+
+// LeafHash returns the hash of a leaf of the tree, the data being a serialised record
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// nodeHash returns the hash of an interior node from the hashes of its children
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// Build returns the root of the tree whose leaves have the given hashes, in order, and the
+// inclusion proof of every leaf: the sibling hashes on the path from the leaf up to the root.
+func Build(leaves [][]byte) ([]byte, [][][]byte, error) {
+	if len(leaves) == 0 {
+		return nil, nil, errors.New("a tree needs at least one leaf")
+	}
+	proofs := make([][][]byte, len(leaves))
+	return build(leaves, proofs), proofs, nil
+}
+
+// build returns the root of the subtree of the leaves and appends the sibling of the
+// subtree's leaves at each level to their proofs, the lowest level first
+func build(leaves [][]byte, proofs [][][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+
+	// the left subtree holds the largest power of two strictly smaller than the leaf count
+	k := 1 << (bits.Len(uint(len(leaves)-1)) - 1)
+	left := build(leaves[:k], proofs[:k])
+	right := build(leaves[k:], proofs[k:])
+	for i := range proofs[:k] {
+		proofs[i] = append(proofs[i], right)
+	}
+	for i := range proofs[k:] {
+		proofs[k+i] = append(proofs[k+i], left)
+	}
+	return nodeHash(left, right)
+}
+
+// RootFromInclusionProof returns the root of a tree of treeSize leaves computed from the hash of
+// the leaf at leafIndex and its inclusion proof, following RFC 9162 section 2.1.3.2
+func RootFromInclusionProof(leafIndex, treeSize int64, leafHash []byte, proof [][]byte) ([]byte, error) {
+	if treeSize <= 0 || leafIndex < 0 || leafIndex >= treeSize {
+		return nil, fmt.Errorf("%w: leaf %d is outside a tree of %d leaves", ErrInvalidProof, leafIndex, treeSize)
+	}
+
+	fn, sn := leafIndex, treeSize-1
+	root := leafHash
+	for _, sibling := range proof {
+		if len(sibling) != sha256.Size {
+			return nil, fmt.Errorf("%w: hash of %d bytes", ErrInvalidProof, len(sibling))
+		}
+		if sn == 0 {
+			return nil, fmt.Errorf("%w: longer than the tree is high", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			root = nodeHash(sibling, root)
+			// a right-most node without a sibling moves up unchanged
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			root = nodeHash(root, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return nil, fmt.Errorf("%w: shorter than the tree is high", ErrInvalidProof)
+	}
+	return root, nil
+}
+
+// VerifyInclusion checks the inclusion proof leads from the leaf to the root
+func VerifyInclusion(leafIndex, treeSize int64, leafHash []byte, proof [][]byte, root []byte) error {
+	computed, err := RootFromInclusionProof(leafIndex, treeSize, leafHash, proof)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(computed, root) {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// referenceRoot computes the Merkle tree hash of RFC 9162 section 2.1.1 straight from its definition
+func referenceRoot(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := 1
+	for k*2 < len(leaves) {
+		k *= 2
+	}
+	return nodeHash(referenceRoot(leaves[:k]), referenceRoot(leaves[k:]))
+}
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = LeafHash([]byte(fmt.Sprintf("record %d", i)))
+	}
+	return leaves
+}
+
+func TestBuildAndVerifyInclusion(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 8, 9, 31, 64, 100} {
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			t.Parallel()
+			leaves := testLeaves(n)
+
+			root, proofs, err := Build(leaves)
+			if err != nil {
+				t.Fatalf("failed to build tree: %v", err)
+			}
+			if want := referenceRoot(leaves); !bytes.Equal(root, want) {
+				t.Fatalf("unexpected root %x, want %x", root, want)
+			}
+
+			for i, proof := range proofs {
+				if err := VerifyInclusion(int64(i), int64(n), leaves[i], proof, root); err != nil {
+					t.Fatalf("failed to verify proof of leaf %d: %v", i, err)
+				}
+				// the proof is bound to the position of the leaf
+				if n > 1 {
+					if err := VerifyInclusion(int64((i+1)%n), int64(n), leaves[i], proof, root); !errors.Is(err, ErrInvalidProof) {
+						t.Fatalf("expected ErrInvalidProof for leaf %d at another index but got %v", i, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyInclusionRejects(t *testing.T) {
+	t.Parallel()
+	leaves := testLeaves(5)
+	root, proofs, err := Build(leaves)
+	if err != nil {
+		t.Fatalf("failed to build tree: %v", err)
+	}
+
+	tampered := bytes.Clone(proofs[2][0])
+	tampered[0] ^= 0xff
+
+	tests := []struct {
+		name      string
+		leafIndex int64
+		treeSize  int64
+		leaf      []byte
+		proof     [][]byte
+	}{
+		{name: "other leaf", leafIndex: 2, treeSize: 5, leaf: leaves[3], proof: proofs[2]},
+		{name: "tampered sibling", leafIndex: 2, treeSize: 5, leaf: leaves[2], proof: [][]byte{tampered, proofs[2][1], proofs[2][2]}},
+		{name: "truncated proof", leafIndex: 2, treeSize: 5, leaf: leaves[2], proof: proofs[2][:2]},
+		{name: "extended proof", leafIndex: 2, treeSize: 5, leaf: leaves[2], proof: append(proofs[2][:3:3], root)},
+		{name: "short hash", leafIndex: 2, treeSize: 5, leaf: leaves[2], proof: [][]byte{proofs[2][0][:31], proofs[2][1], proofs[2][2]}},
+		{name: "index outside the tree", leafIndex: 5, treeSize: 5, leaf: leaves[2], proof: proofs[2]},
+		{name: "negative index", leafIndex: -1, treeSize: 5, leaf: leaves[2], proof: proofs[2]},
+		{name: "empty tree", leafIndex: 0, treeSize: 0, leaf: leaves[2], proof: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := VerifyInclusion(tt.leafIndex, tt.treeSize, tt.leaf, tt.proof, root); !errors.Is(err, ErrInvalidProof) {
+				t.Fatalf("expected ErrInvalidProof but got %v", err)
+			}
+		})
+	}
+
+	if _, _, err := Build(nil); err == nil {
+		t.Fatal("expected error for a tree without leaves")
+	}
+}
+
+func TestLeafHash(t *testing.T) {
+	t.Parallel()
+	// the hash of the empty leaf from RFC 6962, SHA-256 of the single byte 0x00
+	want := "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d"
+	if got := hex.EncodeToString(LeafHash(nil)); got != want {
+		t.Fatalf("unexpected leaf hash %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package signature
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LoadCertificateChain reads a PEM bundle of X.509 certificates from a file.
+// The first certificate is the leaf certifying the signing key, followed by any intermediates.
+func LoadCertificateChain(pemFile string) ([]*x509.Certificate, error) {
+	pemBytes, err := os.ReadFile(pemFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate chain file: %w", err)
+	}
+
+	return ParseCertificateChain(pemBytes)
+}
+
+// ParseCertificateChain parses a PEM bundle of X.509 certificates, leaf first.
+func ParseCertificateChain(pemBytes []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q in certificate chain", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("no certificates found in certificate chain")
+	}
+
+	return chain, nil
+}
+
+// EncodeCertificateChain returns the PEM bundle of the certificate chain.
+func EncodeCertificateChain(chain []*x509.Certificate) []byte {
+	var out []byte
+	for _, cert := range chain {
+		out = append(out, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		})...)
+	}
+	return out
+}
+
+// CheckCertificateMatchesSigner makes sure the leaf certificate certifies the signer's public key.
+func CheckCertificateMatchesSigner(chain []*x509.Certificate, signer Signer) error {
+	if len(chain) == 0 {
+		return errors.New("certificate chain is empty")
+	}
+	if signer == nil {
+		return errors.New("signer cannot be nil")
+	}
+
+	if KeyIDForPublicKey(chain[0].PublicKey) != signer.KeyID() {
+		return errors.New("leaf certificate does not certify the signing key")
+	}
+	return nil
+}
+
+// VerifyCertificateChain validates the chain against the trusted roots as of the given time,
+// typically the timestamp of a signed record. The leaf certificate must be valid at that time
+// and chain up to one of the roots through the intermediates in the chain.
+func VerifyCertificateChain(chain []*x509.Certificate, roots *x509.CertPool, at time.Time) error {
+	if len(chain) == 0 {
+		return errors.New("certificate chain is empty")
+	}
+	if roots == nil {
+		return errors.New("root certificate pool cannot be nil")
+	}
+
+	leaf := chain[0]
+	// check the validity period explicitly for a clearer error than the chain verification gives
+	if at.Before(leaf.NotBefore) || at.After(leaf.NotAfter) {
+		return fmt.Errorf("time %s is outside the certificate validity period %s to %s",
+			at.Format(time.RFC3339), leaf.NotBefore.Format(time.RFC3339), leaf.NotAfter.Format(time.RFC3339))
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		// signing certificates rarely carry an extended key usage relevant to this service
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("certificate chain verification failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// issueCertificate creates a certificate for the public key signed by the parent (self-signed when parent is nil).
+func issueCertificate(
+	t *testing.T,
+	name string,
+	publicKey any,
+	parent *x509.Certificate,
+	parentKey any,
+	isCA bool,
+	notBefore time.Time,
+	notAfter time.Time,
+) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	if parent == nil {
+		parent = template
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func TestVerifyCertificateChain(t *testing.T) {
+	t.Parallel()
+
+	generateKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		return key
+	}
+
+	now := time.Now().UTC()
+	rootKey, intermediateKey, leafKey, otherRootKey := generateKey(), generateKey(), generateKey(), generateKey()
+
+	root := issueCertificate(t, "root", &rootKey.PublicKey, nil, rootKey, true,
+		now.Add(-48*time.Hour), now.Add(365*24*time.Hour))
+	intermediate := issueCertificate(t, "intermediate", &intermediateKey.PublicKey, root, rootKey, true,
+		now.Add(-48*time.Hour), now.Add(365*24*time.Hour))
+	leaf := issueCertificate(t, "signer", &leafKey.PublicKey, intermediate, intermediateKey, false,
+		now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	otherRoot := issueCertificate(t, "other root", &otherRootKey.PublicKey, nil, otherRootKey, true,
+		now.Add(-48*time.Hour), now.Add(365*24*time.Hour))
+
+	// the chain must survive a PEM round trip as served by GetCertificateChain
+	chain, err := ParseCertificateChain(EncodeCertificateChain([]*x509.Certificate{leaf, intermediate}))
+	if err != nil {
+		t.Fatalf("failed to parse certificate chain: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherRoot)
+
+	tests := []struct {
+		name        string
+		roots       *x509.CertPool
+		at          time.Time
+		expectError bool
+	}{
+		{name: "valid at record time", roots: roots, at: now},
+		{name: "record before leaf validity", roots: roots, at: now.Add(-36 * time.Hour), expectError: true},
+		{name: "record after leaf validity", roots: roots, at: now.Add(36 * time.Hour), expectError: true},
+		{name: "untrusted root", roots: otherRoots, at: now, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := VerifyCertificateChain(chain, tt.roots, tt.at)
+			if tt.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckCertificateMatchesSigner(t *testing.T) {
+	t.Parallel()
+
+	signerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer, err := newECDSASignerService(signerKey)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	now := time.Now()
+	matching := issueCertificate(t, "signer", &signerKey.PublicKey, nil, signerKey, false, now, now.Add(time.Hour))
+	other := issueCertificate(t, "other", &otherKey.PublicKey, nil, otherKey, false, now, now.Add(time.Hour))
+
+	if err := CheckCertificateMatchesSigner([]*x509.Certificate{matching}, signer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckCertificateMatchesSigner([]*x509.Certificate{other}, signer); err == nil {
+		t.Fatal("expected error for a certificate of a different key")
+	}
+}
@@ -0,0 +1,113 @@
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ECDSASignerService handles signing and verifying content using ECDSA P-256 keys.
+type ECDSASignerService struct {
+	privateKey *ecdsa.PrivateKey // Server's private key (used for signing)
+	publicKey  *ecdsa.PublicKey  // Server's public key (used for verification)
+}
+
+var _ Signer = (*ECDSASignerService)(nil)
+
+// newECDSASignerService derives the public key from the private key and returns a signer service.
+// Only the P-256 curve is supported as it is the only curve paired with SHA-256 in JOSE and COSE.
+func newECDSASignerService(privateKey *ecdsa.PrivateKey) (*ECDSASignerService, error) {
+	if privateKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("unsupported elliptic curve %s, only P-256 is supported",
+			privateKey.Curve.Params().Name)
+	}
+	return &ECDSASignerService{
+		privateKey: privateKey,
+		publicKey:  &privateKey.PublicKey,
+	}, nil
+}
+
+// Sign - signs the SHA-256 hash of the input payload using ECDSA and returns an ASN.1 DER encoded signature.
+func (s *ECDSASignerService) Sign(blobContent []byte) ([]byte, error) {
+	if err := eCDSASignerServiceCheckInit(s); err != nil {
+		return nil, fmt.Errorf("failed to sign content: %w", err)
+	}
+
+	if len(blobContent) == 0 {
+		return nil, errors.New("blob content cannot be nil or empty")
+	}
+
+	hashed := sha256.Sum256(blobContent)
+
+	signature, err := ecdsa.SignASN1(rand.Reader, s.privateKey, hashed[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign blob content using ECDSA: %w", err)
+	}
+
+	return signature, nil
+}
+
+// ComputeHash - computes the SHA-256 hash of the given blob content.
+func (s *ECDSASignerService) ComputeHash(blobContent []byte) []byte {
+	hash := sha256.Sum256(blobContent)
+	return hash[:]
+}
+
+// VerifySignature - checks whether the given ASN.1 DER encoded signature is valid
+// for the provided blobContent using the server's ECDSA public key.
+func (s *ECDSASignerService) VerifySignature(blobContent []byte, signature []byte) error {
+	if err := eCDSASignerServiceCheckInit(s); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	if len(blobContent) == 0 {
+		return errors.New("blob content cannot be nil or empty")
+	}
+
+	if len(signature) == 0 {
+		return errors.New("signature content cannot be nil or empty")
+	}
+
+	hashed := sha256.Sum256(blobContent)
+	if !ecdsa.VerifyASN1(s.publicKey, hashed[:], signature) {
+		return errors.New("signature verification failed using ECDSA")
+	}
+
+	return nil
+}
+
+// GetPublicKey returns the PEM-encoded public key in PKIX format.
+func (s *ECDSASignerService) GetPublicKey() ([]byte, error) {
+	if s.publicKey == nil {
+		return nil, errors.New("signer service is not properly initialised with keys")
+	}
+
+	return encodePublicKeyPEM(s.publicKey)
+}
+
+// Algorithm returns the signature algorithm used by the ECDSA signer (P-256 with SHA-256).
+func (s *ECDSASignerService) Algorithm() Algorithm {
+	return AlgorithmECDSAP256SHA256
+}
+
+// KeyID returns the identifier of the signing key derived from its public key.
+func (s *ECDSASignerService) KeyID() string {
+	if s == nil || s.publicKey == nil {
+		return ""
+	}
+	return KeyIDForPublicKey(s.publicKey)
+}
+
+// eCDSASignerServiceCheckInit - checks to see if the ECDSA signer service is initialised properly
+func eCDSASignerServiceCheckInit(s *ECDSASignerService) error {
+	if s == nil {
+		return errors.New("ECDSASignerService has not been initialised properly")
+	}
+	if s.privateKey == nil || s.publicKey == nil {
+		return errors.New("signer service is not properly initialised with keys")
+	}
+	return nil
+}
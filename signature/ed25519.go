@@ -0,0 +1,101 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// Ed25519SignerService handles signing and verifying content using Ed25519 keys.
+type Ed25519SignerService struct {
+	privateKey ed25519.PrivateKey // Server's private key (used for signing)
+	publicKey  ed25519.PublicKey  // Server's public key (used for verification)
+}
+
+var _ Signer = (*Ed25519SignerService)(nil)
+
+// newEd25519SignerService derives the public key from the private key and returns a signer service.
+func newEd25519SignerService(privateKey ed25519.PrivateKey) *Ed25519SignerService {
+	publicKey, _ := privateKey.Public().(ed25519.PublicKey)
+	return &Ed25519SignerService{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}
+}
+
+// Sign - signs the input payload using pure Ed25519.
+// Unlike RSA-PSS and ECDSA the message itself is signed, Ed25519 hashes it internally.
+func (s *Ed25519SignerService) Sign(blobContent []byte) ([]byte, error) {
+	if err := ed25519SignerServiceCheckInit(s); err != nil {
+		return nil, fmt.Errorf("failed to sign content: %w", err)
+	}
+
+	if len(blobContent) == 0 {
+		return nil, errors.New("blob content cannot be nil or empty")
+	}
+
+	return ed25519.Sign(s.privateKey, blobContent), nil
+}
+
+// ComputeHash - computes the SHA-256 hash of the given blob content.
+// The blob hash is independent of the signature algorithm.
+func (s *Ed25519SignerService) ComputeHash(blobContent []byte) []byte {
+	hash := sha256.Sum256(blobContent)
+	return hash[:]
+}
+
+// VerifySignature - checks whether the given signature is valid for the provided blobContent
+// using the server's Ed25519 public key.
+func (s *Ed25519SignerService) VerifySignature(blobContent []byte, signature []byte) error {
+	if err := ed25519SignerServiceCheckInit(s); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	if len(blobContent) == 0 {
+		return errors.New("blob content cannot be nil or empty")
+	}
+
+	if len(signature) == 0 {
+		return errors.New("signature content cannot be nil or empty")
+	}
+
+	if !ed25519.Verify(s.publicKey, blobContent, signature) {
+		return errors.New("signature verification failed using Ed25519")
+	}
+
+	return nil
+}
+
+// GetPublicKey returns the PEM-encoded public key in PKIX format.
+func (s *Ed25519SignerService) GetPublicKey() ([]byte, error) {
+	if s.publicKey == nil {
+		return nil, errors.New("signer service is not properly initialised with keys")
+	}
+
+	return encodePublicKeyPEM(s.publicKey)
+}
+
+// Algorithm returns the signature algorithm used by the Ed25519 signer.
+func (s *Ed25519SignerService) Algorithm() Algorithm {
+	return AlgorithmEd25519
+}
+
+// KeyID returns the identifier of the signing key derived from its public key.
+func (s *Ed25519SignerService) KeyID() string {
+	if s == nil || s.publicKey == nil {
+		return ""
+	}
+	return KeyIDForPublicKey(s.publicKey)
+}
+
+// ed25519SignerServiceCheckInit - checks to see if the Ed25519 signer service is initialised properly
+func ed25519SignerServiceCheckInit(s *Ed25519SignerService) error {
+	if s == nil {
+		return errors.New("Ed25519SignerService has not been initialised properly")
+	}
+	if s.privateKey == nil || s.publicKey == nil {
+		return errors.New("signer service is not properly initialised with keys")
+	}
+	return nil
+}
@@ -1,5 +1,18 @@
 package signature
 
+// Algorithm identifies the signature scheme produced by a Signer.
+type Algorithm string
+
+// Supported signature algorithms
+const (
+	// AlgorithmRSAPSSSHA256 - RSASSA-PSS using SHA-256 and a salt length equal to the hash length
+	AlgorithmRSAPSSSHA256 Algorithm = "RSASSA-PSS-SHA256"
+	// AlgorithmECDSAP256SHA256 - ECDSA on the P-256 curve using SHA-256 (ASN.1 DER encoded signatures)
+	AlgorithmECDSAP256SHA256 Algorithm = "ECDSA-P256-SHA256"
+	// AlgorithmEd25519 - pure Ed25519 (the message is signed directly, without pre-hashing)
+	AlgorithmEd25519 Algorithm = "Ed25519"
+)
+
 // Signer interface defines the methods for signing and verifying data.
 type Signer interface {
 	// Sign - signs the given blob content and returns the signature
@@ -10,4 +23,8 @@ type Signer interface {
 	GetPublicKey() ([]byte, error)
 	// ComputeHash - computes the hash of the given blob content
 	ComputeHash(blobContent []byte) []byte
+	// Algorithm - returns the signature algorithm used by the signer
+	Algorithm() Algorithm
+	// KeyID - returns a stable identifier for the signing key
+	KeyID() string
 }
@@ -0,0 +1,137 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// NewSignerFromFile loads a PEM-encoded private key from a file and returns
+// the signer matching its type:
+//   - "RSA PRIVATE KEY" (PKCS#1) or an RSA PKCS#8 key -> RSASSA-PSS
+//   - "EC PRIVATE KEY" (SEC 1) or a P-256 PKCS#8 key -> ECDSA P-256
+//   - an Ed25519 PKCS#8 key -> Ed25519
+func NewSignerFromFile(pemFile string) (Signer, error) {
+	keyBytes, err := os.ReadFile(pemFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key file: %w", err)
+	}
+
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block containing private key")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		return newRSASignerService(privateKey), nil
+	case "EC PRIVATE KEY":
+		privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		return newECDSASignerService(privateKey)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKCS#8 private key: %w", err)
+		}
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return newRSASignerService(k), nil
+		case *ecdsa.PrivateKey:
+			return newECDSASignerService(k)
+		case ed25519.PrivateKey:
+			return newEd25519SignerService(k), nil
+		default:
+			return nil, fmt.Errorf("unsupported PKCS#8 private key type %T", key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// KeyIDForPublicKey derives a stable key identifier from a public key.
+// The identifier is the hex-encoded SHA-256 digest of the DER-encoded PKIX public key,
+// so clients holding the PEM public key can compute it independently.
+func KeyIDForPublicKey(publicKey crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return ""
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:])
+}
+
+// encodePublicKeyPEM returns the PEM-encoded public key in PKIX format.
+func encodePublicKeyPEM(publicKey crypto.PublicKey) ([]byte, error) {
+	// Marshal the public key to ASN.1 DER-encoded PKIX format
+	pubASN1, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	// Encode it to PEM format
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: pubASN1,
+	}), nil
+}
+
+// ParsePublicKeyPEM parses a PEM-encoded PKIX public key as returned by GetPublicKey.
+func ParsePublicKeyPEM(pemBytes []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("invalid PEM format for public key")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// LoadPublicKeys reads a PEM bundle of PKIX public keys from a file, e.g. the keys of registered countersigners.
+func LoadPublicKeys(pemFile string) ([]crypto.PublicKey, error) {
+	pemBytes, err := os.ReadFile(pemFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public keys file: %w", err)
+	}
+
+	var publicKeys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unexpected PEM block type %q in public keys file", block.Type)
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no public keys found in file")
+	}
+
+	return publicKeys, nil
+}
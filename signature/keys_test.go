@@ -0,0 +1,198 @@
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePEMToFile writes a single PEM block to a file in a temporary directory.
+func writePEMToFile(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "key.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filename, pemBytes, 0600); err != nil {
+		t.Fatalf("failed to write key to file: %v", err)
+	}
+	return filename
+}
+
+func TestNewSignerFromFile(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, _ := generateRsaKeyPair(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+
+	marshalPKCS8 := func(key any) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("failed to marshal PKCS#8 key: %v", err)
+		}
+		return der
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("failed to marshal EC key: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		blockType     string
+		der           []byte
+		expectedAlg   Algorithm
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "PKCS#1 RSA key",
+			blockType:   "RSA PRIVATE KEY",
+			der:         x509.MarshalPKCS1PrivateKey(rsaKey),
+			expectedAlg: AlgorithmRSAPSSSHA256,
+		},
+		{
+			name:        "PKCS#8 RSA key",
+			blockType:   "PRIVATE KEY",
+			der:         marshalPKCS8(rsaKey),
+			expectedAlg: AlgorithmRSAPSSSHA256,
+		},
+		{
+			name:        "SEC 1 EC key",
+			blockType:   "EC PRIVATE KEY",
+			der:         ecDER,
+			expectedAlg: AlgorithmECDSAP256SHA256,
+		},
+		{
+			name:        "PKCS#8 Ed25519 key",
+			blockType:   "PRIVATE KEY",
+			der:         marshalPKCS8(edKey),
+			expectedAlg: AlgorithmEd25519,
+		},
+		{
+			name:        "P-384 key is rejected",
+			blockType:   "PRIVATE KEY",
+			der:         marshalPKCS8(p384Key),
+			expectError: true,
+		},
+		{
+			name:          "unknown PEM type",
+			blockType:     "CERTIFICATE",
+			der:           []byte("not a key"),
+			expectError:   true,
+			errorContains: "unsupported PEM block type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			filename := writePEMToFile(t, tt.blockType, tt.der)
+
+			signer, err := NewSignerFromFile(filename)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if signer.Algorithm() != tt.expectedAlg {
+				t.Fatalf("expected algorithm %q but got %q", tt.expectedAlg, signer.Algorithm())
+			}
+
+			if len(signer.KeyID()) != 64 {
+				t.Fatalf("expected a hex encoded SHA-256 key ID but got %q", signer.KeyID())
+			}
+
+			content := []byte("hello world")
+			sig, err := signer.Sign(content)
+			if err != nil {
+				t.Fatalf("failed to sign content: %v", err)
+			}
+			if err := signer.VerifySignature(content, sig); err != nil {
+				t.Fatalf("failed to verify signature: %v", err)
+			}
+			if err := signer.VerifySignature([]byte("hello world!"), sig); err == nil {
+				t.Fatal("signature should not be valid for modified content")
+			}
+
+			// the public key must round trip through PEM
+			pemBytes, err := signer.GetPublicKey()
+			if err != nil {
+				t.Fatalf("failed to get public key: %v", err)
+			}
+			pub, err := ParsePublicKeyPEM(pemBytes)
+			if err != nil {
+				t.Fatalf("failed to parse public key: %v", err)
+			}
+			if KeyIDForPublicKey(pub) != signer.KeyID() {
+				t.Fatal("key ID of the parsed public key does not match the signer key ID")
+			}
+		})
+	}
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ECDSA key: %v", err)
+	}
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+
+	var bundle []byte
+	for _, publicKey := range []any{&ecKey.PublicKey, edPublicKey} {
+		pemBytes, err := encodePublicKeyPEM(publicKey)
+		if err != nil {
+			t.Fatalf("failed to encode public key: %v", err)
+		}
+		bundle = append(bundle, pemBytes...)
+	}
+	filename := filepath.Join(t.TempDir(), "keys.pem")
+	if err := os.WriteFile(filename, bundle, 0600); err != nil {
+		t.Fatalf("failed to write public keys: %v", err)
+	}
+
+	publicKeys, err := LoadPublicKeys(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(publicKeys) != 2 {
+		t.Fatalf("expected 2 public keys but got %d", len(publicKeys))
+	}
+	if KeyIDForPublicKey(publicKeys[0]) != KeyIDForPublicKey(&ecKey.PublicKey) {
+		t.Fatal("first public key does not match")
+	}
+
+	// private keys must never be accepted as a public key bundle
+	der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	if _, err := LoadPublicKeys(writePEMToFile(t, "PRIVATE KEY", der)); err == nil {
+		t.Fatal("expected error for a private key")
+	}
+}
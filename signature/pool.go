@@ -0,0 +1,143 @@
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// ErrSignerSaturated is returned by a Pool whose workers are busy and whose queue is full,
+	// callers should back off and retry
+	ErrSignerSaturated = errors.New("signer is saturated")
+	// ErrSignerClosed is returned by a Pool once it is closed
+	ErrSignerClosed = errors.New("signer is closed")
+)
+
+// Signer pool metrics, registered with the default Prometheus registry
+var (
+	signerQueueWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "signed_blob_service",
+		Subsystem: "signer",
+		Name:      "queue_wait_seconds",
+		Help:      "Time sign requests waited in the queue for a worker.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+	})
+	signerSignDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "signed_blob_service",
+		Subsystem: "signer",
+		Name:      "sign_duration_seconds",
+		Help:      "Time the private key operation of a sign request took.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+	})
+	signerRejectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "signed_blob_service",
+		Subsystem: "signer",
+		Name:      "rejected_total",
+		Help:      "Number of sign requests rejected because the queue was full.",
+	})
+)
+
+// Pool is a Signer running the signatures of another Signer on a fixed number of workers.
+// Requests wait in a bounded queue for a free worker and are rejected with ErrSignerSaturated
+// when it is full, so a burst of requests can neither use more CPUs than the workers nor
+// pile up without bound. Every other method is served by the wrapped Signer directly.
+type Pool struct {
+	Signer
+
+	jobs chan *signJob
+	wg   sync.WaitGroup
+
+	mu     sync.RWMutex // guards closed against jobs being closed while a request is queued
+	closed bool
+}
+
+var _ Signer = (*Pool)(nil)
+
+// signJob is a request waiting for a worker
+type signJob struct {
+	content []byte
+	queued  time.Time
+	result  chan signResult
+}
+
+// signResult is the outcome of a signJob
+type signResult struct {
+	signature []byte
+	err       error
+}
+
+// NewPool starts workers signing with the signer. queueDepth requests may wait for a free
+// worker, with zero a request is rejected unless a worker is idle. Close stops the workers.
+func NewPool(signer Signer, workers, queueDepth int) (*Pool, error) {
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+	if workers <= 0 {
+		return nil, fmt.Errorf("number of workers must be positive, got %d", workers)
+	}
+	if queueDepth < 0 {
+		return nil, fmt.Errorf("queue depth cannot be negative, got %d", queueDepth)
+	}
+
+	p := &Pool{
+		Signer: signer,
+		jobs:   make(chan *signJob, queueDepth),
+	}
+	p.wg.Add(workers)
+	for range workers {
+		go p.work()
+	}
+	return p, nil
+}
+
+// work signs the queued requests until the pool is closed and its queue drained
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		start := time.Now()
+		signerQueueWait.Observe(start.Sub(job.queued).Seconds())
+
+		signature, err := p.Signer.Sign(job.content)
+		signerSignDuration.Observe(time.Since(start).Seconds())
+		job.result <- signResult{signature: signature, err: err}
+	}
+}
+
+// Sign queues the content for a worker and waits for its signature.
+// It returns ErrSignerSaturated at once when the queue is full.
+func (p *Pool) Sign(blobContent []byte) ([]byte, error) {
+	job := &signJob{content: blobContent, queued: time.Now(), result: make(chan signResult, 1)}
+
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrSignerClosed
+	}
+	select {
+	case p.jobs <- job:
+	default:
+		p.mu.RUnlock()
+		signerRejectedTotal.Inc()
+		return nil, ErrSignerSaturated
+	}
+	p.mu.RUnlock()
+
+	result := <-job.result
+	return result.signature, result.err
+}
+
+// Close rejects new requests and waits for the workers to sign the queued ones
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
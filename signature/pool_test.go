@@ -0,0 +1,138 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingSigner signs once release is closed, reporting every call on started
+type blockingSigner struct {
+	Signer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSigner) Sign(blobContent []byte) ([]byte, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return s.Signer.Sign(blobContent)
+}
+
+func newEd25519Signer(t *testing.T) Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+	return newEd25519SignerService(key)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+	signer := newEd25519Signer(t)
+
+	pool, err := NewPool(signer, 2, 4)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	// every other method is the wrapped signer's
+	if pool.KeyID() != signer.KeyID() || pool.Algorithm() != signer.Algorithm() {
+		t.Fatalf("unexpected key ID %s and algorithm %s", pool.KeyID(), pool.Algorithm())
+	}
+
+	errs := make(chan error, 20)
+	for range cap(errs) {
+		go func() {
+			sig, err := pool.Sign([]byte("hello world"))
+			if err == nil {
+				err = signer.VerifySignature([]byte("hello world"), sig)
+			}
+			errs <- err
+		}()
+	}
+	for range cap(errs) {
+		// requests beyond the queue depth may be rejected, the others must be signed
+		if err := <-errs; err != nil && !errors.Is(err, ErrSignerSaturated) {
+			t.Fatalf("failed to sign: %v", err)
+		}
+	}
+
+	pool.Close()
+	pool.Close() // closing twice is harmless
+	if _, err := pool.Sign([]byte("hello world")); !errors.Is(err, ErrSignerClosed) {
+		t.Fatalf("expected ErrSignerClosed but got %v", err)
+	}
+}
+
+func TestPoolSaturated(t *testing.T) {
+	t.Parallel()
+	signer := &blockingSigner{
+		Signer:  newEd25519Signer(t),
+		started: make(chan struct{}, 2),
+		release: make(chan struct{}),
+	}
+
+	pool, err := NewPool(signer, 1, 1)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+
+	errs := make(chan error, 2)
+	sign := func() {
+		_, err := pool.Sign([]byte("hello world"))
+		errs <- err
+	}
+
+	// the first request occupies the worker, the second one the queue
+	go sign()
+	<-signer.started
+	go sign()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(pool.jobs) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the request to be queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := pool.Sign([]byte("hello world")); !errors.Is(err, ErrSignerSaturated) {
+		t.Fatalf("expected ErrSignerSaturated but got %v", err)
+	}
+
+	close(signer.release)
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+	}
+}
+
+func TestNewPoolInvalid(t *testing.T) {
+	t.Parallel()
+	signer := newEd25519Signer(t)
+
+	tests := []struct {
+		name       string
+		signer     Signer
+		workers    int
+		queueDepth int
+	}{
+		{name: "no signer", workers: 1},
+		{name: "no workers", signer: signer, workers: 0},
+		{name: "negative queue depth", signer: signer, workers: 1, queueDepth: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := NewPool(tt.signer, tt.workers, tt.queueDepth); err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
@@ -49,13 +49,15 @@ func NewRSASignerServiceFromFile(pemFile string) (*RSASignerService, error) {
 		return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
 	}
 
-	// Derive the public key from the private key
-	publicKey := &privateKey.PublicKey
+	return newRSASignerService(privateKey), nil
+}
 
+// newRSASignerService derives the public key from the private key and returns a signer service.
+func newRSASignerService(privateKey *rsa.PrivateKey) *RSASignerService {
 	return &RSASignerService{
 		privateKey: privateKey,
-		publicKey:  publicKey,
-	}, nil
+		publicKey:  &privateKey.PublicKey,
+	}
 }
 
 // Sign - signs the input payload using RSASSA-PSS with SHA-256.
@@ -134,19 +136,21 @@ func (s *RSASignerService) GetPublicKey() ([]byte, error) {
 	if s.publicKey == nil {
 		return nil, errors.New("signer service is not properly initialised with keys")
 	}
-	// Marshal the public key to ASN.1 DER-encoded PKIX format
-	pubASN1, err := x509.MarshalPKIXPublicKey(s.publicKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal public key: %w", err)
-	}
 
-	// Encode it to PEM format
-	pubPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: pubASN1,
-	})
+	return encodePublicKeyPEM(s.publicKey)
+}
 
-	return pubPEM, nil
+// Algorithm returns the signature algorithm used by the RSA signer (RSASSA-PSS with SHA-256).
+func (s *RSASignerService) Algorithm() Algorithm {
+	return AlgorithmRSAPSSSHA256
+}
+
+// KeyID returns the identifier of the signing key derived from its public key.
+func (s *RSASignerService) KeyID() string {
+	if s == nil || s.publicKey == nil {
+		return ""
+	}
+	return KeyIDForPublicKey(s.publicKey)
 }
 
 // getPrivateKey returns the PEM-encoded private key in PKCS#1 format.
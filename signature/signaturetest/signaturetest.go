@@ -0,0 +1,88 @@
+// Package signaturetest provides keys and signers for testing code built on the signature package
+// against every algorithm the service signs with.
+package signaturetest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prit342/signed-blob-service/signature"
+)
+
+// Algorithms lists every algorithm the service signs with
+var Algorithms = []signature.Algorithm{
+	signature.AlgorithmRSAPSSSHA256,
+	signature.AlgorithmECDSAP256SHA256,
+	signature.AlgorithmEd25519,
+}
+
+// GenerateKey generates a private key for the algorithm, RSA keys are of 2048 bits
+func GenerateKey(t testing.TB, algorithm signature.Algorithm) crypto.Signer {
+	t.Helper()
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch algorithm {
+	case signature.AlgorithmRSAPSSSHA256:
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case signature.AlgorithmECDSAP256SHA256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case signature.AlgorithmEd25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		t.Fatalf("unsupported algorithm %q", algorithm)
+	}
+	if err != nil {
+		t.Fatalf("failed to generate %s key: %v", algorithm, err)
+	}
+	return key
+}
+
+// NewSigner writes the private key to a temporary PEM file and loads a signer from it,
+// the way the server loads its signing key
+func NewSigner(t testing.TB, key crypto.Signer) signature.Signer {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write private key: %v", err)
+	}
+	signer, err := signature.NewSignerFromFile(filename)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	return signer
+}
+
+// NewSignerFor generates a key for the algorithm and returns a signer using it
+func NewSignerFor(t testing.TB, algorithm signature.Algorithm) signature.Signer {
+	t.Helper()
+	return NewSigner(t, GenerateKey(t, algorithm))
+}
+
+// PublicKey returns the public key of the signer the way clients parse it from GetPublicKey
+func PublicKey(t testing.TB, signer signature.Signer) crypto.PublicKey {
+	t.Helper()
+	pemBytes, err := signer.GetPublicKey()
+	if err != nil {
+		t.Fatalf("failed to get public key: %v", err)
+	}
+	publicKey, err := signature.ParsePublicKeyPEM(pemBytes)
+	if err != nil {
+		t.Fatalf("failed to parse public key: %v", err)
+	}
+	return publicKey
+}
@@ -0,0 +1,103 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// p256CoordinateSize is the size in bytes of each of R and S in a raw P-256 signature.
+const p256CoordinateSize = 32
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature as produced by ecdsa.SignASN1.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// AlgorithmForPublicKey returns the algorithm the service signs with for the given public key type.
+func AlgorithmForPublicKey(publicKey crypto.PublicKey) (Algorithm, error) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRSAPSSSHA256, nil
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported elliptic curve %s", pub.Curve.Params().Name)
+		}
+		return AlgorithmECDSAP256SHA256, nil
+	case ed25519.PublicKey:
+		return AlgorithmEd25519, nil
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// VerifyWithPublicKey verifies a signature produced by one of the service signers
+// using only the public key. The algorithm is selected from the key type:
+// RSASSA-PSS for RSA keys, ASN.1 DER ECDSA for P-256 keys and Ed25519 for Ed25519 keys.
+func VerifyWithPublicKey(publicKey crypto.PublicKey, message []byte, signature []byte) error {
+	if len(message) == 0 {
+		return errors.New("message cannot be nil or empty")
+	}
+
+	if len(signature) == 0 {
+		return errors.New("signature content cannot be nil or empty")
+	}
+
+	hashed := sha256.Sum256(message)
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPSS(pub, cryptoHash(), hashed[:], signature, &rsaPSSOptions); err != nil {
+			return fmt.Errorf("signature verification failed using RSASSA-PSS: %w", err)
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, hashed[:], signature) {
+			return errors.New("signature verification failed using ECDSA")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, message, signature) {
+			return errors.New("signature verification failed using Ed25519")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return nil
+}
+
+// ECDSASignatureToRaw converts an ASN.1 DER encoded P-256 signature to the fixed size R || S
+// form used by JOSE (ES256) and COSE.
+func ECDSASignatureToRaw(der []byte) ([]byte, error) {
+	var sig ecdsaSignature
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ECDSA signature: %w", err)
+	}
+	if len(rest) != 0 || sig.R == nil || sig.S == nil {
+		return nil, errors.New("malformed ECDSA signature")
+	}
+
+	raw := make([]byte, 2*p256CoordinateSize)
+	sig.R.FillBytes(raw[:p256CoordinateSize])
+	sig.S.FillBytes(raw[p256CoordinateSize:])
+	return raw, nil
+}
+
+// ECDSASignatureFromRaw converts a fixed size R || S P-256 signature to ASN.1 DER.
+func ECDSASignatureFromRaw(raw []byte) ([]byte, error) {
+	if len(raw) != 2*p256CoordinateSize {
+		return nil, fmt.Errorf("invalid raw ECDSA signature length %d", len(raw))
+	}
+
+	return asn1.Marshal(ecdsaSignature{
+		R: new(big.Int).SetBytes(raw[:p256CoordinateSize]),
+		S: new(big.Int).SetBytes(raw[p256CoordinateSize:]),
+	})
+}
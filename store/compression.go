@@ -0,0 +1,115 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is a compression algorithm for blob content kept in the database.
+// The hash and signature always cover the original content, compression is invisible to readers.
+type Codec string
+
+// Supported codecs
+const (
+	CodecNone Codec = ""     // content is stored as it is
+	CodecGzip Codec = "gzip" // RFC 1952 gzip, available everywhere
+	CodecZstd Codec = "zstd" // Zstandard, faster and usually smaller than gzip
+)
+
+// ParseCodec returns the codec with the name, "" and "none" mean no compression.
+func ParseCodec(name string) (Codec, error) {
+	switch codec := Codec(strings.ToLower(name)); codec {
+	case CodecNone, "none":
+		return CodecNone, nil
+	case CodecGzip, CodecZstd:
+		return codec, nil
+	default:
+		return CodecNone, fmt.Errorf("unsupported compression codec %q, expected gzip or zstd", name)
+	}
+}
+
+// WithCompression compresses blob content with the codec before writing it to the database.
+// Content that does not shrink is stored as it is. Records are decompressed with the codec
+// they were written with, so the codec can be changed at any time.
+func WithCompression(codec Codec) Option {
+	return func(o *options) {
+		o.compression = codec
+	}
+}
+
+// zstd encoders and decoders are expensive to create and safe for concurrent EncodeAll and DecodeAll calls
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) { return zstd.NewWriter(nil) })
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) { return zstd.NewReader(nil) })
+)
+
+// compressContent compresses the blob with the codec when that makes it smaller.
+// It returns the codec actually used, CodecNone when the blob is to be stored as it is.
+func compressContent(codec Codec, blob string) (Codec, []byte, error) {
+	if codec == CodecNone || blob == "" {
+		return CodecNone, nil, nil
+	}
+
+	var compressed []byte
+	switch codec {
+	case CodecGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := io.WriteString(w, blob); err != nil {
+			return CodecNone, nil, fmt.Errorf("failed to compress blob content: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return CodecNone, nil, fmt.Errorf("failed to compress blob content: %w", err)
+		}
+		compressed = buf.Bytes()
+	case CodecZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return CodecNone, nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		compressed = encoder.EncodeAll([]byte(blob), nil)
+	default:
+		return CodecNone, nil, fmt.Errorf("unsupported compression codec %q", codec)
+	}
+
+	// small or random content does not shrink, storing it compressed only costs CPU on every read
+	if len(compressed) >= len(blob) {
+		return CodecNone, nil, nil
+	}
+
+	return codec, compressed, nil
+}
+
+// decompressContent reverses compressContent
+func decompressContent(codec Codec, compressed []byte) ([]byte, error) {
+	switch codec {
+	case CodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress blob content: %w", err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress blob content: %w", err)
+		}
+		return content, nil
+	case CodecZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		content, err := decoder.DecodeAll(compressed, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress blob content: %w", err)
+		}
+		return content, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %q", codec)
+	}
+}
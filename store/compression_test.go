@@ -0,0 +1,80 @@
+package store
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestParseCodec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expected    Codec
+		expectError bool
+	}{
+		{name: "", expected: CodecNone},
+		{name: "none", expected: CodecNone},
+		{name: "gzip", expected: CodecGzip},
+		{name: "ZSTD", expected: CodecZstd},
+		{name: "brotli", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			codec, err := ParseCodec(tt.name)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if codec != tt.expected {
+				t.Fatalf("expected codec %q but got %q", tt.expected, codec)
+			}
+		})
+	}
+}
+
+func TestCompressContent(t *testing.T) {
+	t.Parallel()
+
+	yaml := strings.Repeat("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: example\n", 500)
+	random := make([]byte, 4096)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatalf("failed to generate random content: %v", err)
+	}
+
+	for _, codec := range []Codec{CodecGzip, CodecZstd} {
+		t.Run(string(codec), func(t *testing.T) {
+			t.Parallel()
+
+			used, compressed, err := compressContent(codec, yaml)
+			if err != nil {
+				t.Fatalf("failed to compress content: %v", err)
+			}
+			if used != codec || len(compressed)*5 > len(yaml) {
+				t.Fatalf("expected %s to shrink repetitive content at least 5x, got %d of %d bytes with %q",
+					codec, len(compressed), len(yaml), used)
+			}
+
+			content, err := decompressContent(used, compressed)
+			if err != nil {
+				t.Fatalf("failed to decompress content: %v", err)
+			}
+			if string(content) != yaml {
+				t.Fatal("decompressed content does not match the original")
+			}
+
+			// content that does not shrink is stored as it is
+			if used, compressed, err := compressContent(codec, string(random)); err != nil || used != CodecNone || compressed != nil {
+				t.Fatalf("expected random content to be stored uncompressed: %q %v", used, err)
+			}
+		})
+	}
+}
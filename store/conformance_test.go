@@ -0,0 +1,89 @@
+package store_test
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/prit342/signed-blob-service/store"
+	"github.com/prit342/signed-blob-service/store/storetest"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestMemoryStorageConformance(t *testing.T) {
+	t.Parallel()
+	storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+		return store.NewMemoryStorage(), ""
+	})
+}
+
+func TestSQLiteStorageConformance(t *testing.T) {
+	t.Parallel()
+	storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+		s, err := store.NewSQLiteStorage("sqlite://"+filepath.Join(t.TempDir(), "blobs.db"), discardLogger())
+		if err != nil {
+			t.Fatalf("failed to create SQLite storage: %v", err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		return s, "" // embedded migrations
+	})
+}
+
+func TestCompressedSQLiteStorageConformance(t *testing.T) {
+	t.Parallel()
+	for _, codec := range []store.Codec{store.CodecGzip, store.CodecZstd} {
+		t.Run(string(codec), func(t *testing.T) {
+			t.Parallel()
+			storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+				s, err := store.NewSQLiteStorage("sqlite://"+filepath.Join(t.TempDir(), "blobs.db"), discardLogger(),
+					store.WithCompression(codec))
+				if err != nil {
+					t.Fatalf("failed to create SQLite storage: %v", err)
+				}
+				t.Cleanup(func() { _ = s.Close() })
+				return s, "" // embedded migrations
+			})
+		})
+	}
+}
+
+func TestEncryptedSQLiteStorageConformance(t *testing.T) {
+	t.Parallel()
+	keyring, err := store.NewKeyring(store.MasterKey{ID: "test", Key: make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	for _, codec := range []store.Codec{store.CodecNone, store.CodecZstd} {
+		t.Run("compression="+string(codec), func(t *testing.T) {
+			t.Parallel()
+			storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+				s, err := store.NewSQLiteStorage("sqlite://"+filepath.Join(t.TempDir(), "blobs.db"), discardLogger(),
+					store.WithCompression(codec), store.WithEncryption(keyring))
+				if err != nil {
+					t.Fatalf("failed to create SQLite storage: %v", err)
+				}
+				t.Cleanup(func() { _ = s.Close() })
+				return s, "" // embedded migrations
+			})
+		})
+	}
+}
+
+func TestContentAddressedStorageConformance(t *testing.T) {
+	t.Parallel()
+	storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+		content, err := store.NewFileContentStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create file content store: %v", err)
+		}
+		s, err := store.NewContentAddressedStorage(store.NewMemoryStorage(), content, discardLogger())
+		if err != nil {
+			t.Fatalf("failed to create storage: %v", err)
+		}
+		return s, ""
+	})
+}
@@ -0,0 +1,404 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Content store errors
+var (
+	ErrContentNotFound     = errors.New("blob content not found")
+	ErrContentHashMismatch = errors.New("blob content does not match its hash")
+)
+
+// ContentStore keeps blob content outside the database, addressed by its hex-encoded SHA-256 hash.
+// Content is immutable: storing the same content twice is a no-op.
+type ContentStore interface {
+	// Put stores the content under its hash
+	Put(ctx context.Context, hash string, content []byte) error
+	// Get retrieves the content stored under the hash
+	Get(ctx context.Context, hash string) ([]byte, error)
+	// Size returns the size in bytes of the content stored under the hash without reading it
+	Size(ctx context.Context, hash string) (int64, error)
+	// Delete removes the content stored under the hash, deleting missing content is a no-op
+	Delete(ctx context.Context, hash string) error
+}
+
+// NewContentStore creates the content store selected by the URL scheme:
+// file:///var/lib/signed-blob-service/content for a local directory tree or
+// s3://bucket/prefix for an S3-compatible bucket, configured by s3Config.
+func NewContentStore(rawURL string, s3Config S3Config) (ContentStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content store URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return NewFileContentStore(u.Path)
+	case "s3":
+		s3Config.Bucket = u.Host
+		s3Config.Prefix = u.Path
+		return NewS3ContentStore(s3Config)
+	default:
+		return nil, fmt.Errorf("unsupported content store URL scheme %q", u.Scheme)
+	}
+}
+
+// validateHash makes sure the hash is a hex-encoded SHA-256, it becomes part of file paths and object keys
+func validateHash(hash string) error {
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("invalid content hash %q", hash)
+	}
+	return nil
+}
+
+// checkContentHash re-computes the hash of the content and compares it with the expected one
+func checkContentHash(hash string, content []byte) error {
+	digest := sha256.Sum256(content)
+	if computed := hex.EncodeToString(digest[:]); computed != hash {
+		return fmt.Errorf("%w: expected %s, computed %s", ErrContentHashMismatch, hash, computed)
+	}
+	return nil
+}
+
+// FileContentStore stores content in a directory tree, e.g. <root>/ab/cd/abcd... for hash abcd...
+type FileContentStore struct {
+	root string
+}
+
+// NewFileContentStore creates a content store rooted at the directory, which is created if missing
+func NewFileContentStore(root string) (*FileContentStore, error) {
+	if root == "" {
+		return nil, errors.New("content store directory cannot be empty")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create content store directory: %w", err)
+	}
+	return &FileContentStore{root: root}, nil
+}
+
+// path returns the file the content with the hash is stored in
+func (f *FileContentStore) path(hash string) string {
+	return filepath.Join(f.root, hash[0:2], hash[2:4], hash)
+}
+
+// Put writes the content to a temporary file and renames it into place, so readers never see partial content
+func (f *FileContentStore) Put(_ context.Context, hash string, content []byte) error {
+	if err := validateHash(hash); err != nil {
+		return err
+	}
+
+	path := f.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		return nil // already stored
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create content directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary content file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name()) // no-op once renamed
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write content: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync content: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close content file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move content into place: %w", err)
+	}
+
+	return nil
+}
+
+// Get reads the content stored under the hash
+func (f *FileContentStore) Get(_ context.Context, hash string) ([]byte, error) {
+	if err := validateHash(hash); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(f.path(hash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrContentNotFound
+		}
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	return content, nil
+}
+
+// Size returns the size of the file holding the content
+func (f *FileContentStore) Size(_ context.Context, hash string) (int64, error) {
+	if err := validateHash(hash); err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(f.path(hash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, ErrContentNotFound
+		}
+		return 0, fmt.Errorf("failed to stat content: %w", err)
+	}
+
+	return info.Size(), nil
+}
+
+// Delete removes the file holding the content
+func (f *FileContentStore) Delete(_ context.Context, hash string) error {
+	if err := validateHash(hash); err != nil {
+		return err
+	}
+
+	if err := os.Remove(f.path(hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete content: %w", err)
+	}
+
+	return nil
+}
+
+// ContentAddressedStorage keeps blob content in a ContentStore and only the metadata and
+// signatures in the wrapped Storage. The content hash is re-checked on every read.
+// Records stored before the content store was enabled keep their content inline and are
+// returned as they are. Uploads hold their content in the metadata storage until their record is
+// stored, so replicas sharing the content store never delete content a record is about to reference.
+type ContentAddressedStorage struct {
+	Storage // metadata and signatures, content is stored with an empty blob
+	content ContentStore
+	log     *slog.Logger
+}
+
+// NewContentAddressedStorage wraps the metadata storage with the content store
+func NewContentAddressedStorage(metadata Storage, content ContentStore, log *slog.Logger) (*ContentAddressedStorage, error) {
+	if metadata == nil {
+		return nil, errors.New("metadata storage cannot be nil")
+	}
+	if content == nil {
+		return nil, errors.New("content store cannot be nil")
+	}
+	if log == nil {
+		return nil, errors.New("log parameter cannot be nil")
+	}
+	return &ContentAddressedStorage{Storage: metadata, content: content, log: log}, nil
+}
+
+// Store writes the content to the content store, then the record without its content to the metadata storage
+func (s *ContentAddressedStorage) Store(ctx context.Context, record *blobv1.SignedBlobRecord) error {
+	// detached records have no content to store
+	if record.GetPayload().GetDetached() {
+		return s.Storage.Store(ctx, record)
+	}
+
+	content := []byte(record.Payload.Blob)
+	if err := checkContentHash(record.Payload.Hash, content); err != nil {
+		return err
+	}
+
+	release, err := s.acquireContent(ctx, record.Payload.Hash)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := s.content.Put(ctx, record.Payload.Hash, content); err != nil {
+		s.log.Error("failed to store blob content", "error", err, "hash", record.Payload.Hash)
+		return fmt.Errorf("failed to store blob content: %w", err)
+	}
+
+	// the caller's record is left untouched, it still holds the signed payload
+	metadata := proto.Clone(record).(*blobv1.SignedBlobRecord)
+	metadata.Payload.Blob = ""
+
+	return s.Storage.Store(ctx, metadata)
+}
+
+// StoreBatch writes the content of the records to the content store, then the records without
+// their content to the metadata storage in one batch. As with Store, content written for a
+// batch the metadata storage then refuses is left in the content store.
+func (s *ContentAddressedStorage) StoreBatch(ctx context.Context, records []*blobv1.SignedBlobRecord) error {
+	metadata := make([]*blobv1.SignedBlobRecord, 0, len(records))
+	for _, record := range records {
+		if record.GetPayload().GetDetached() {
+			metadata = append(metadata, record)
+			continue
+		}
+		if err := checkContentHash(record.Payload.Hash, []byte(record.Payload.Blob)); err != nil {
+			return err
+		}
+		// the caller's records are left untouched, they still hold the signed payloads
+		withoutContent := proto.Clone(record).(*blobv1.SignedBlobRecord)
+		withoutContent.Payload.Blob = ""
+		metadata = append(metadata, withoutContent)
+	}
+
+	for _, record := range records {
+		if record.GetPayload().GetDetached() {
+			continue
+		}
+		release, err := s.acquireContent(ctx, record.Payload.Hash)
+		if err != nil {
+			return err
+		}
+		defer release()
+		if err := s.content.Put(ctx, record.Payload.Hash, []byte(record.Payload.Blob)); err != nil {
+			s.log.Error("failed to store blob content", "error", err, "hash", record.Payload.Hash)
+			return fmt.Errorf("failed to store blob content: %w", err)
+		}
+	}
+
+	return s.Storage.StoreBatch(ctx, metadata)
+}
+
+// GetByUUID retrieves the record from the metadata storage and its content from the content store
+func (s *ContentAddressedStorage) GetByUUID(ctx context.Context, uuid uuid.UUID) (*blobv1.SignedBlobRecord, error) {
+	record, err := s.Storage.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// detached records have no content and older records keep theirs inline
+	if record.Payload.Detached || record.Payload.Blob != "" {
+		return record, nil
+	}
+
+	content, err := s.content.Get(ctx, record.Payload.Hash)
+	if err != nil {
+		s.log.Error("failed to retrieve blob content", "error", err, "uuid", uuid, "hash", record.Payload.Hash)
+		return nil, fmt.Errorf("failed to retrieve blob content: %w", err)
+	}
+
+	// the content store is outside the database, so it is never trusted blindly
+	if err := checkContentHash(record.Payload.Hash, content); err != nil {
+		s.log.Error("blob content is corrupted", "error", err, "uuid", uuid)
+		return nil, err
+	}
+
+	record.Payload.Blob = string(content)
+	return record, nil
+}
+
+// GetMetadata retrieves the metadata from the metadata storage, the size of external content
+// comes from the content store without reading the content
+func (s *ContentAddressedStorage) GetMetadata(ctx context.Context, uuid uuid.UUID) (*blobv1.BlobMetadata, error) {
+	metadata, err := s.Storage.GetMetadata(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.contentSize(ctx, metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// ListBlobs lists the blobs of the metadata storage, with the size of external content from the content store
+func (s *ContentAddressedStorage) ListBlobs(ctx context.Context, query ListQuery) ([]*blobv1.BlobMetadata, error) {
+	blobs, err := s.Storage.ListBlobs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, metadata := range blobs {
+		if err := s.contentSize(ctx, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return blobs, nil
+}
+
+// contentSize sets the size of content kept in the content store
+func (s *ContentAddressedStorage) contentSize(ctx context.Context, metadata *blobv1.BlobMetadata) error {
+	// uploaded content is never empty, a zero size means the content is in the content store
+	if metadata.Detached || metadata.Size != 0 {
+		return nil
+	}
+
+	size, err := s.content.Size(ctx, metadata.Hash)
+	if err != nil {
+		s.log.Error("failed to retrieve blob content size", "error", err, "uuid", metadata.Uuid, "hash", metadata.Hash)
+		return fmt.Errorf("failed to retrieve blob content size: %w", err)
+	}
+	metadata.Size = size
+
+	return nil
+}
+
+// acquireContent holds the content with the hash until the returned function releases it,
+// which the caller defers until the record referencing the content is stored or refused
+func (s *ContentAddressedStorage) acquireContent(ctx context.Context, hash string) (func(), error) {
+	if err := s.Storage.AcquireContent(ctx, hash); err != nil {
+		return nil, fmt.Errorf("failed to acquire blob content: %w", err)
+	}
+	return func() {
+		// released even when the request is cancelled, a leaked hold only keeps the content longer
+		if err := s.Storage.ReleaseContent(context.WithoutCancel(ctx), hash); err != nil {
+			s.log.Error("failed to release blob content", "error", err, "hash", hash)
+		}
+	}, nil
+}
+
+// Delete removes the record from the metadata storage, then its content from the content
+// store unless another record has the same content or an upload of it is in progress
+func (s *ContentAddressedStorage) Delete(ctx context.Context, uuid uuid.UUID) error {
+	// the hash is gone with the record, so it is looked up first
+	metadata, err := s.Storage.GetMetadata(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if err := s.Storage.Delete(ctx, uuid); err != nil {
+		return err
+	}
+
+	return s.deleteContent(ctx, uuid, metadata.Hash)
+}
+
+// Expire removes the expired record from the metadata storage, then its content from the content
+// store unless another record has the same content or an upload of it is in progress
+func (s *ContentAddressedStorage) Expire(ctx context.Context, uuid uuid.UUID, tombstone *blobv1.SignedTombstone) error {
+	if err := s.Storage.Expire(ctx, uuid, tombstone); err != nil {
+		return err
+	}
+
+	return s.deleteContent(ctx, uuid, tombstone.Payload.Hash)
+}
+
+// deleteContent deletes the content of the removed record unless another record references it.
+// Detached records and records with inline content have nothing in the content store, deleting is a no-op.
+func (s *ContentAddressedStorage) deleteContent(ctx context.Context, uuid uuid.UUID, hash string) error {
+	if _, err := s.Storage.DeleteContent(ctx, hash, func(ctx context.Context) error {
+		return s.content.Delete(ctx, hash)
+	}); err != nil {
+		s.log.Error("failed to delete blob content", "error", err, "uuid", uuid, "hash", hash)
+		return fmt.Errorf("failed to delete blob content: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configures the connection to an S3-compatible bucket such as AWS S3 or MinIO
+type S3Config struct {
+	Endpoint        string // host[:port] of the S3 API, e.g. s3.eu-west-2.amazonaws.com or localhost:9000
+	Region          string // bucket region, defaults to us-east-1
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool   // connect over HTTPS
+	Bucket          string // bucket name
+	Prefix          string // optional object key prefix
+}
+
+// S3ContentStore stores content as objects in an S3-compatible bucket, e.g. <prefix>/ab/abcd... for hash abcd...
+type S3ContentStore struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3ContentStore creates a content store backed by an S3-compatible bucket, which must already exist
+func NewS3ContentStore(cfg S3Config) (*S3ContentStore, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("S3 endpoint cannot be empty")
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("S3 bucket cannot be empty")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		// a known region avoids a bucket location lookup before every first request
+		Region:       cfg.Region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3ContentStore{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+	}, nil
+}
+
+// key returns the object key the content with the hash is stored under
+func (s *S3ContentStore) key(hash string) string {
+	return path.Join(s.prefix, hash[0:2], hash)
+}
+
+// Put uploads the content unless an object with the hash already exists
+func (s *S3ContentStore) Put(ctx context.Context, hash string, content []byte) error {
+	if err := validateHash(hash); err != nil {
+		return err
+	}
+
+	key := s.key(hash)
+	if _, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{}); err == nil {
+		return nil // already stored
+	} else if minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		return fmt.Errorf("failed to check for existing content: %w", err)
+	}
+
+	if _, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(content), int64(len(content)),
+		minio.PutObjectOptions{ContentType: "application/octet-stream"}); err != nil {
+		return fmt.Errorf("failed to upload content: %w", err)
+	}
+
+	return nil
+}
+
+// Get downloads the content stored under the hash
+func (s *S3ContentStore) Get(ctx context.Context, hash string) ([]byte, error) {
+	if err := validateHash(hash); err != nil {
+		return nil, err
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, s.key(hash), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download content: %w", err)
+	}
+	defer object.Close()
+
+	// GetObject is lazy, errors such as a missing key only show up on the first read
+	content, err := io.ReadAll(object)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrContentNotFound
+		}
+		return nil, fmt.Errorf("failed to download content: %w", err)
+	}
+
+	return content, nil
+}
+
+// Size returns the size of the object holding the content
+func (s *S3ContentStore) Size(ctx context.Context, hash string) (int64, error) {
+	if err := validateHash(hash); err != nil {
+		return 0, err
+	}
+
+	info, err := s.client.StatObject(ctx, s.bucket, s.key(hash), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return 0, ErrContentNotFound
+		}
+		return 0, fmt.Errorf("failed to stat content: %w", err)
+	}
+
+	return info.Size, nil
+}
+
+// Delete removes the object holding the content, S3 reports success for missing objects
+func (s *S3ContentStore) Delete(ctx context.Context, hash string) error {
+	if err := validateHash(hash); err != nil {
+		return err
+	}
+
+	if err := s.client.RemoveObject(ctx, s.bucket, s.key(hash), minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete content: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+)
+
+// fakeS3 is a minimal stand-in for an S3-compatible server such as MinIO,
+// supporting path-style HEAD, GET, PUT and DELETE object requests.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// plain HTTP uploads are signed per chunk, strip the chunk framing like a real server
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			if body, err = decodeAWSChunked(body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		f.objects[r.URL.Path] = body
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				_, _ = fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 28 Jul 2025 17:42:05 GMT")
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// decodeAWSChunked strips the "<hex size>;chunk-signature=...\r\n<data>\r\n" framing of a streaming upload.
+func decodeAWSChunked(body []byte) ([]byte, error) {
+	var out []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return nil, errors.New("truncated chunk header")
+		}
+		sizeHex, _, _ := bytes.Cut(header, []byte(";"))
+		size, err := strconv.ParseInt(string(sizeHex), 16, 64)
+		if err != nil || int64(len(rest)) < size+2 {
+			return nil, errors.New("invalid chunk size")
+		}
+		if size == 0 {
+			return out, nil
+		}
+		out = append(out, rest[:size]...)
+		body = rest[size+2:]
+	}
+}
+
+func TestContentStores(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	t.Cleanup(server.Close)
+
+	fileStore, err := NewContentStore("file://"+t.TempDir(), S3Config{})
+	if err != nil {
+		t.Fatalf("failed to create file content store: %v", err)
+	}
+	s3Store, err := NewContentStore("s3://blobs/content", S3Config{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio123",
+	})
+	if err != nil {
+		t.Fatalf("failed to create S3 content store: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content ContentStore
+	}{
+		{name: "file", content: fileStore},
+		{name: "s3", content: s3Store},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			content := []byte("content kept outside the database")
+			digest := sha256.Sum256(content)
+			hash := hex.EncodeToString(digest[:])
+
+			if _, err := tt.content.Get(ctx, hash); !errors.Is(err, ErrContentNotFound) {
+				t.Fatalf("expected ErrContentNotFound but got %v", err)
+			}
+			// storing the same content twice is a no-op
+			for range 2 {
+				if err := tt.content.Put(ctx, hash, content); err != nil {
+					t.Fatalf("failed to put content: %v", err)
+				}
+			}
+			got, err := tt.content.Get(ctx, hash)
+			if err != nil {
+				t.Fatalf("failed to get content: %v", err)
+			}
+			if string(got) != string(content) {
+				t.Fatalf("unexpected content %q", got)
+			}
+			size, err := tt.content.Size(ctx, hash)
+			if err != nil {
+				t.Fatalf("failed to get content size: %v", err)
+			}
+			if size != int64(len(content)) {
+				t.Fatalf("expected size %d but got %d", len(content), size)
+			}
+			// deleting missing content is a no-op
+			for range 2 {
+				if err := tt.content.Delete(ctx, hash); err != nil {
+					t.Fatalf("failed to delete content: %v", err)
+				}
+			}
+			if _, err := tt.content.Size(ctx, hash); !errors.Is(err, ErrContentNotFound) {
+				t.Fatalf("expected ErrContentNotFound after delete but got %v", err)
+			}
+			if err := tt.content.Put(ctx, "../../etc/passwd", content); err == nil {
+				t.Fatal("expected error for a hash that is not a SHA-256")
+			}
+		})
+	}
+}
+
+func TestContentAddressedStorage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	root := t.TempDir()
+	content, err := NewFileContentStore(root)
+	if err != nil {
+		t.Fatalf("failed to create file content store: %v", err)
+	}
+	metadata := NewMemoryStorage()
+	s, err := NewContentAddressedStorage(metadata, content, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	blob := "hello world"
+	digest := sha256.Sum256([]byte(blob))
+	hash := hex.EncodeToString(digest[:])
+	id := uuid.New()
+	record := &blobv1.SignedBlobRecord{
+		Payload:   &blobv1.BlobRecord{Uuid: id.String(), Blob: blob, Hash: hash, Timestamp: "2025-07-28T17:42:05.123456Z"},
+		Signature: []byte{0x01},
+	}
+
+	if err := s.Store(ctx, record); err != nil {
+		t.Fatalf("failed to store record: %v", err)
+	}
+	if record.Payload.Blob != blob {
+		t.Fatal("the caller's record must not be modified")
+	}
+
+	// only the metadata is kept in the wrapped storage
+	stored, err := metadata.GetByUUID(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve metadata: %v", err)
+	}
+	if stored.Payload.Blob != "" {
+		t.Fatalf("expected no content in the metadata storage, got %q", stored.Payload.Blob)
+	}
+
+	got, err := s.GetByUUID(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if got.Payload.Blob != blob {
+		t.Fatalf("unexpected blob %q", got.Payload.Blob)
+	}
+
+	// content that does not match its hash is rejected on store and on read
+	mismatched := &blobv1.SignedBlobRecord{
+		Payload:   &blobv1.BlobRecord{Uuid: uuid.NewString(), Blob: "other", Hash: hash},
+		Signature: []byte{0x01},
+	}
+	if err := s.Store(ctx, mismatched); !errors.Is(err, ErrContentHashMismatch) {
+		t.Fatalf("expected ErrContentHashMismatch but got %v", err)
+	}
+	if err := os.WriteFile(content.path(hash), []byte("tampered"), 0o600); err != nil {
+		t.Fatalf("failed to tamper with content: %v", err)
+	}
+	if _, err := s.GetByUUID(ctx, id); !errors.Is(err, ErrContentHashMismatch) {
+		t.Fatalf("expected ErrContentHashMismatch but got %v", err)
+	}
+	if err := os.WriteFile(content.path(hash), []byte(blob), 0o600); err != nil {
+		t.Fatalf("failed to restore content: %v", err)
+	}
+
+	// expired content is only deleted once no other record has the same content
+	duplicate := &blobv1.SignedBlobRecord{
+		Payload:   &blobv1.BlobRecord{Uuid: uuid.NewString(), Blob: blob, Hash: hash, Timestamp: "2025-07-28T17:42:05.123456Z"},
+		Signature: []byte{0x02},
+	}
+	if err := s.Store(ctx, duplicate); err != nil {
+		t.Fatalf("failed to store duplicate content: %v", err)
+	}
+	for i, expired := range []*blobv1.SignedBlobRecord{record, duplicate} {
+		tombstone := &blobv1.SignedTombstone{
+			Payload:   &blobv1.Tombstone{Uuid: expired.Payload.Uuid, Hash: hash},
+			Signature: []byte{0x03},
+		}
+		if err := s.Expire(ctx, uuid.MustParse(expired.Payload.Uuid), tombstone); err != nil {
+			t.Fatalf("failed to expire record: %v", err)
+		}
+		_, err := content.Size(ctx, hash)
+		if last := i == 1; last != errors.Is(err, ErrContentNotFound) {
+			t.Fatalf("unexpected content state after expiring record %d: %v", i+1, err)
+		}
+	}
+
+	// deleted content is only deleted once no other record has the same content either
+	kept := &blobv1.SignedBlobRecord{
+		Payload:   &blobv1.BlobRecord{Uuid: uuid.NewString(), Blob: blob, Hash: hash, Timestamp: "2025-07-28T17:42:05.123456Z"},
+		Signature: []byte{0x04},
+	}
+	for _, stored := range []*blobv1.SignedBlobRecord{record, kept} {
+		if err := s.Store(ctx, stored); err != nil {
+			t.Fatalf("failed to store record: %v", err)
+		}
+	}
+	for i, deleted := range []*blobv1.SignedBlobRecord{record, kept} {
+		if err := s.Delete(ctx, uuid.MustParse(deleted.Payload.Uuid)); err != nil {
+			t.Fatalf("failed to delete record: %v", err)
+		}
+		_, err := content.Size(ctx, hash)
+		if last := i == 1; last != errors.Is(err, ErrContentNotFound) {
+			t.Fatalf("unexpected content state after deleting record %d: %v", i+1, err)
+		}
+	}
+	if err := s.Delete(ctx, id); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+}
+
+// pausedDeleteStore pauses Delete once it is called, until proceed is closed
+type pausedDeleteStore struct {
+	ContentStore
+	deleting chan struct{} // closed when Delete is called
+	proceed  chan struct{}
+}
+
+func (p *pausedDeleteStore) Delete(ctx context.Context, hash string) error {
+	close(p.deleting)
+	<-p.proceed
+	return p.ContentStore.Delete(ctx, hash)
+}
+
+func TestContentAddressedStorageReplicas(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// two replicas sharing the database and the content store, each with its own connection
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "blobs.db")
+	content, err := NewFileContentStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file content store: %v", err)
+	}
+	paused := &pausedDeleteStore{ContentStore: content, deleting: make(chan struct{}), proceed: make(chan struct{})}
+	replica := func(content ContentStore) *ContentAddressedStorage {
+		metadata, err := NewSQLiteStorage(dsn, log)
+		if err != nil {
+			t.Fatalf("failed to create SQLite storage: %v", err)
+		}
+		t.Cleanup(func() { _ = metadata.Close() })
+		if err := metadata.Migrate(ctx, ""); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+		s, err := NewContentAddressedStorage(metadata, content, log)
+		if err != nil {
+			t.Fatalf("failed to create storage: %v", err)
+		}
+		return s
+	}
+	reaper, uploader := replica(paused), replica(content)
+
+	blob := "shared content"
+	digest := sha256.Sum256([]byte(blob))
+	hash := hex.EncodeToString(digest[:])
+	newRecord := func() *blobv1.SignedBlobRecord {
+		return &blobv1.SignedBlobRecord{
+			Payload:   &blobv1.BlobRecord{Uuid: uuid.NewString(), Blob: blob, Hash: hash, Timestamp: "2025-07-28T17:42:05.123456Z"},
+			Signature: []byte{0x01},
+		}
+	}
+
+	expired := newRecord()
+	if err := reaper.Store(ctx, expired); err != nil {
+		t.Fatalf("failed to store record: %v", err)
+	}
+
+	// the upload of the same content starts while the reaper deletes it
+	expiring := make(chan error, 1)
+	go func() {
+		tombstone := &blobv1.SignedTombstone{Payload: &blobv1.Tombstone{Uuid: expired.Payload.Uuid, Hash: hash}, Signature: []byte{0x02}}
+		expiring <- reaper.Expire(ctx, uuid.MustParse(expired.Payload.Uuid), tombstone)
+	}()
+	select {
+	case err := <-expiring:
+		t.Fatalf("expired without deleting the content: %v", err)
+	case <-paused.deleting:
+	}
+
+	uploaded := newRecord()
+	storing := make(chan error, 1)
+	go func() {
+		storing <- uploader.Store(ctx, uploaded)
+	}()
+	select {
+	case err := <-storing:
+		t.Fatalf("upload finished while its content was being deleted: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(paused.proceed)
+
+	if err := <-expiring; err != nil {
+		t.Fatalf("failed to expire record: %v", err)
+	}
+	if err := <-storing; err != nil {
+		t.Fatalf("failed to store record: %v", err)
+	}
+
+	got, err := uploader.GetByUUID(ctx, uuid.MustParse(uploaded.Payload.Uuid))
+	if err != nil {
+		t.Fatalf("failed to retrieve the uploaded record: %v", err)
+	}
+	if got.Payload.Blob != blob {
+		t.Fatalf("unexpected blob %q", got.Payload.Blob)
+	}
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// masterKeySize is the size of master and data keys, both are AES-256 keys
+const masterKeySize = 32
+
+// Encryption errors
+var (
+	// ErrMasterKeyNotFound is returned when a data key was wrapped by a master key missing from the keyring
+	ErrMasterKeyNotFound = errors.New("master key not found in keyring")
+	// ErrEncryptionDisabled is returned when encrypted content is read, or keys re-wrapped, without a keyring
+	ErrEncryptionDisabled = errors.New("encryption at rest is not enabled")
+)
+
+// KeyRewrapper is implemented by the storages that can encrypt content at rest
+type KeyRewrapper interface {
+	// RewrapKeys re-wraps the data keys of up to batchSize records under the active master key
+	// and returns how many were re-wrapped. Once it returns 0, no record depends on a previous
+	// master key any more and those can be removed from the keyring.
+	RewrapKeys(ctx context.Context, batchSize int) (int, error)
+}
+
+// staleKey is a data key wrapped by a previous master key, listed for re-wrapping
+type staleKey struct {
+	uuid        string
+	masterKeyID string
+	wrapped     []byte
+}
+
+// MasterKey is an AES-256 key wrapping the data keys of encrypted records
+type MasterKey struct {
+	ID  string // kept next to every data key it wraps, so the key can be found again after a rotation
+	Key []byte // 32 random bytes
+}
+
+// Keyring holds the active master key, which wraps the data keys of new records,
+// and the previous master keys still needed to read records written before a rotation
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from the active master key and any previous ones
+func NewKeyring(active MasterKey, previous ...MasterKey) (*Keyring, error) {
+	k := &Keyring{
+		active: active.ID,
+		keys:   make(map[string]cipher.AEAD),
+	}
+
+	for _, key := range append([]MasterKey{active}, previous...) {
+		if key.ID == "" || strings.ContainsAny(key.ID, " \t\r\n") {
+			return nil, fmt.Errorf("invalid master key ID %q", key.ID)
+		}
+		if len(key.Key) != masterKeySize {
+			return nil, fmt.Errorf("master key %s must be %d bytes, got %d", key.ID, masterKeySize, len(key.Key))
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate master key ID %s", key.ID)
+		}
+		aead, err := newAEAD(key.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key %s: %w", key.ID, err)
+		}
+		k.keys[key.ID] = aead
+	}
+
+	return k, nil
+}
+
+// LoadKeyring reads a keyring file with one "<key ID> <base64 encoded 32 byte key>" per line.
+// The first key is the active one, the following ones are previous keys kept for reading.
+// Blank lines and lines starting with # are ignored.
+func LoadKeyring(path string) (*Keyring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring file: %w", err)
+	}
+	defer f.Close()
+
+	var keys []MasterKey
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("keyring file line %d: expected a key ID and a base64 encoded key", line)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("keyring file line %d: failed to decode key: %w", line, err)
+		}
+		keys = append(keys, MasterKey{ID: fields[0], Key: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("keyring file contains no keys")
+	}
+
+	return NewKeyring(keys[0], keys[1:]...)
+}
+
+// ActiveKeyID returns the ID of the master key wrapping the data keys of new records
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// WithEncryption encrypts blob content written to the database with a fresh data key per record,
+// stored wrapped by the active master key of the keyring. The hash and signature cover the
+// original content, which is re-checked against the hash after every decryption.
+func WithEncryption(keyring *Keyring) Option {
+	return func(o *options) {
+		o.keyring = keyring
+	}
+}
+
+// encryptContent seals the content with a new data key and wraps that key with the active master key.
+// The record UUID is authenticated with both, so neither can be moved to another record.
+func (k *Keyring) encryptContent(uuid string, content []byte) (masterKeyID string, wrappedKey, sealed []byte, err error) {
+	dataKey := make([]byte, masterKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if sealed, err = seal(aead, content, []byte(uuid)); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to encrypt blob content: %w", err)
+	}
+
+	if wrappedKey, err = k.wrapKey(uuid, dataKey); err != nil {
+		return "", nil, nil, err
+	}
+
+	return k.active, wrappedKey, sealed, nil
+}
+
+// decryptContent reverses encryptContent
+func (k *Keyring) decryptContent(uuid, masterKeyID string, wrappedKey, sealed []byte) ([]byte, error) {
+	dataKey, err := k.unwrapKey(uuid, masterKeyID, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	content, err := open(aead, sealed, []byte(uuid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt blob content: %w", err)
+	}
+
+	return content, nil
+}
+
+// rewrapKey unwraps a data key with the master key it was wrapped with and wraps it with the active one
+func (k *Keyring) rewrapKey(uuid, masterKeyID string, wrappedKey []byte) ([]byte, error) {
+	dataKey, err := k.unwrapKey(uuid, masterKeyID, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	return k.wrapKey(uuid, dataKey)
+}
+
+// wrapKey encrypts a data key with the active master key
+func (k *Keyring) wrapKey(uuid string, dataKey []byte) ([]byte, error) {
+	wrapped, err := seal(k.keys[k.active], dataKey, wrapAAD(uuid, k.active))
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return wrapped, nil
+}
+
+// unwrapKey decrypts a data key with the master key it was wrapped with
+func (k *Keyring) unwrapKey(uuid, masterKeyID string, wrappedKey []byte) ([]byte, error) {
+	aead, ok := k.keys[masterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMasterKeyNotFound, masterKeyID)
+	}
+	dataKey, err := open(aead, wrappedKey, wrapAAD(uuid, masterKeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with master key %s: %w", masterKeyID, err)
+	}
+	return dataKey, nil
+}
+
+// wrapAAD binds a wrapped data key to its record and master key
+func wrapAAD(uuid, masterKeyID string) []byte {
+	return []byte(uuid + "/" + masterKeyID)
+}
+
+// newAEAD creates an AES-GCM cipher with the key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// seal encrypts the plaintext with a random nonce, which is prepended to the ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open reverses seal
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
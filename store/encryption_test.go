@@ -0,0 +1,219 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+)
+
+// newTestMasterKey returns a random master key with the ID
+func newTestMasterKey(t *testing.T, id string) MasterKey {
+	t.Helper()
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate master key: %v", err)
+	}
+	return MasterKey{ID: id, Key: key}
+}
+
+// newTestRecord returns an unsigned record with the blob and its hash
+func newTestRecord(blob string) *blobv1.SignedBlobRecord {
+	digest := sha256.Sum256([]byte(blob))
+	return &blobv1.SignedBlobRecord{
+		Payload: &blobv1.BlobRecord{
+			Uuid:      uuid.NewString(),
+			Blob:      blob,
+			Hash:      hex.EncodeToString(digest[:]),
+			Timestamp: "2025-07-28T17:42:05.123456Z",
+		},
+		Signature: []byte{0x01},
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	t.Parallel()
+
+	active, previous := newTestMasterKey(t, "2025-06"), newTestMasterKey(t, "2025-01")
+	encode := base64.StdEncoding.EncodeToString
+
+	tests := []struct {
+		name        string
+		contents    string
+		expectError bool
+	}{
+		{
+			name: "active and previous keys",
+			contents: "# rotated in June\n" + active.ID + " " + encode(active.Key) + "\n\n" +
+				previous.ID + " " + encode(previous.Key) + "\n",
+		},
+		{name: "empty", contents: "# no keys\n", expectError: true},
+		{name: "short key", contents: "short " + encode([]byte("too short")) + "\n", expectError: true},
+		{name: "not base64", contents: "bad !!!\n", expectError: true},
+		{name: "missing key", contents: active.ID + "\n", expectError: true},
+		{
+			name: "duplicate ID",
+			contents: active.ID + " " + encode(active.Key) + "\n" +
+				active.ID + " " + encode(previous.Key) + "\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "keyring")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatalf("failed to write keyring file: %v", err)
+			}
+
+			keyring, err := LoadKeyring(path)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if keyring.ActiveKeyID() != active.ID {
+				t.Fatalf("expected active key %s but got %s", active.ID, keyring.ActiveKeyID())
+			}
+		})
+	}
+}
+
+func TestEncodeContent(t *testing.T) {
+	t.Parallel()
+
+	keyring, err := NewKeyring(newTestMasterKey(t, "active"))
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	yaml := strings.Repeat("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: example\n", 500)
+
+	for _, codec := range []Codec{CodecNone, CodecZstd} {
+		t.Run("compression="+string(codec), func(t *testing.T) {
+			t.Parallel()
+			opts := options{compression: codec, keyring: keyring}
+			record := newTestRecord(yaml)
+
+			e, err := encodeContent(opts, record)
+			if err != nil {
+				t.Fatalf("failed to encode content: %v", err)
+			}
+			if e.blob != "" || e.codec != codec || e.masterKeyID != "active" || e.size != int64(len(yaml)) {
+				t.Fatalf("unexpected encoded content: codec %q, master key %q, size %d", e.codec, e.masterKeyID, e.size)
+			}
+			if strings.Contains(string(e.data), "ConfigMap") {
+				t.Fatal("encoded content contains the plaintext")
+			}
+
+			content, err := decodeContent(opts, record.Payload.Uuid, record.Payload.Hash, e)
+			if err != nil {
+				t.Fatalf("failed to decode content: %v", err)
+			}
+			if content != yaml {
+				t.Fatal("decoded content does not match the original")
+			}
+
+			// the content is bound to its record
+			if _, err := decodeContent(opts, uuid.NewString(), record.Payload.Hash, e); err == nil {
+				t.Fatal("expected content moved to another record to be rejected")
+			}
+
+			// content decoding to something other than what was signed is rejected
+			otherDigest := sha256.Sum256([]byte("other"))
+			if _, err := decodeContent(opts, record.Payload.Uuid, hex.EncodeToString(otherDigest[:]), e); !errors.Is(err, ErrContentHashMismatch) {
+				t.Fatalf("expected ErrContentHashMismatch but got %v", err)
+			}
+
+			// tampered content fails authentication
+			tampered := *e
+			tampered.data = append([]byte(nil), e.data...)
+			tampered.data[len(tampered.data)-1] ^= 0xff
+			if _, err := decodeContent(opts, record.Payload.Uuid, record.Payload.Hash, &tampered); err == nil {
+				t.Fatal("expected tampered content to be rejected")
+			}
+
+			// reading needs the master key the data key was wrapped with
+			other, err := NewKeyring(newTestMasterKey(t, "other"))
+			if err != nil {
+				t.Fatalf("failed to create keyring: %v", err)
+			}
+			if _, err := decodeContent(options{keyring: other}, record.Payload.Uuid, record.Payload.Hash, e); !errors.Is(err, ErrMasterKeyNotFound) {
+				t.Fatalf("expected ErrMasterKeyNotFound but got %v", err)
+			}
+			if _, err := decodeContent(options{}, record.Payload.Uuid, record.Payload.Hash, e); !errors.Is(err, ErrEncryptionDisabled) {
+				t.Fatalf("expected ErrEncryptionDisabled but got %v", err)
+			}
+		})
+	}
+}
+
+func TestRewrapKeys(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "blobs.db")
+	oldKey, newKey := newTestMasterKey(t, "old"), newTestMasterKey(t, "new")
+
+	openStorage := func(active MasterKey, previous ...MasterKey) *SQLiteStorage {
+		keyring, err := NewKeyring(active, previous...)
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+		s, err := NewSQLiteStorage(dsn, logger, WithEncryption(keyring))
+		if err != nil {
+			t.Fatalf("failed to create SQLite storage: %v", err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	}
+
+	s := openStorage(oldKey)
+	if err := s.Migrate(ctx, "../db-migrations/sqlite"); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	record := newTestRecord("hello world")
+	if err := s.Store(ctx, record); err != nil {
+		t.Fatalf("failed to store record: %v", err)
+	}
+	_ = s.Close()
+
+	// after the rotation the previous key still reads the record until its data key is re-wrapped
+	s = openStorage(newKey, oldKey)
+	rewrapped, err := s.RewrapKeys(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to re-wrap keys: %v", err)
+	}
+	if rewrapped != 1 {
+		t.Fatalf("expected 1 data key to be re-wrapped but got %d", rewrapped)
+	}
+	if rewrapped, err = s.RewrapKeys(ctx, 10); err != nil || rewrapped != 0 {
+		t.Fatalf("expected nothing left to re-wrap, got %d: %v", rewrapped, err)
+	}
+	_ = s.Close()
+
+	// the previous key can now be dropped
+	s = openStorage(newKey)
+	got, err := s.GetByUUID(ctx, uuid.MustParse(record.Payload.Uuid))
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if got.Payload.Blob != record.Payload.Blob {
+		t.Fatalf("unexpected blob %q", got.Payload.Blob)
+	}
+}
@@ -0,0 +1,409 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryStorage implements the Storage interface in memory.
+// It is safe for concurrent use and meant for tests and ephemeral runs, nothing survives a restart.
+type MemoryStorage struct {
+	mu                sync.RWMutex
+	records           map[uuid.UUID]*blobv1.SignedBlobRecord
+	countersignatures map[uuid.UUID][]*blobv1.Countersignature
+	tombstones        map[uuid.UUID]*blobv1.SignedTombstone
+	legalHolds        map[uuid.UUID]bool
+	legalHoldEvents   map[uuid.UUID][]*blobv1.LegalHoldEvent // kept after the blob is deleted
+	pendingContent    map[string]int                         // uploads holding each content hash
+	deletingContent   map[string]chan struct{}               // closed once the content hash is deleted
+}
+
+// NewMemoryStorage creates a new, empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		records:           make(map[uuid.UUID]*blobv1.SignedBlobRecord),
+		countersignatures: make(map[uuid.UUID][]*blobv1.Countersignature),
+		tombstones:        make(map[uuid.UUID]*blobv1.SignedTombstone),
+		legalHolds:        make(map[uuid.UUID]bool),
+		legalHoldEvents:   make(map[uuid.UUID][]*blobv1.LegalHoldEvent),
+		pendingContent:    make(map[string]int),
+		deletingContent:   make(map[string]chan struct{}),
+	}
+}
+
+// Store saves a copy of the record, so later changes by the caller are not reflected
+func (s *MemoryStorage) Store(_ context.Context, record *blobv1.SignedBlobRecord) error {
+	id, err := uuid.Parse(record.GetPayload().GetUuid())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := createdAt(record.Payload.Timestamp); err != nil {
+		return err
+	}
+	if _, _, err := expiry(record.Payload.ExpiresAt); err != nil {
+		return err
+	}
+	if _, ok := s.records[id]; ok {
+		return ErrBlobExists
+	}
+	s.records[id] = proto.Clone(record).(*blobv1.SignedBlobRecord)
+
+	return nil
+}
+
+// StoreBatch saves copies of the records, none of them when one cannot be stored
+func (s *MemoryStorage) StoreBatch(_ context.Context, records []*blobv1.SignedBlobRecord) error {
+	ids := make([]uuid.UUID, 0, len(records))
+	for _, record := range records {
+		id, err := uuid.Parse(record.GetPayload().GetUuid())
+		if err != nil {
+			return err
+		}
+		if _, err := createdAt(record.Payload.Timestamp); err != nil {
+			return err
+		}
+		if _, _, err := expiry(record.Payload.ExpiresAt); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// a UUID repeated within the batch is a duplicate too
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := s.records[id]; ok || seen[id] {
+			return ErrBlobExists
+		}
+		seen[id] = true
+	}
+	for i, record := range records {
+		s.records[ids[i]] = proto.Clone(record).(*blobv1.SignedBlobRecord)
+	}
+
+	return nil
+}
+
+// GetByUUID retrieves a copy of the record by its UUID
+func (s *MemoryStorage) GetByUUID(_ context.Context, uuid uuid.UUID) (*blobv1.SignedBlobRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[uuid]
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+
+	return proto.Clone(record).(*blobv1.SignedBlobRecord), nil
+}
+
+// GetMetadata retrieves the metadata of a blob by its UUID
+func (s *MemoryStorage) GetMetadata(_ context.Context, uuid uuid.UUID) (*blobv1.BlobMetadata, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[uuid]
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+
+	metadata := metadataFromRecord(record)
+	metadata.LegalHold = s.legalHolds[uuid]
+	return metadata, nil
+}
+
+// AddCountersignature stores a copy of the countersignature for the blob with the given UUID
+func (s *MemoryStorage) AddCountersignature(
+	_ context.Context,
+	uuid uuid.UUID,
+	countersignature *blobv1.Countersignature,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[uuid]; !ok {
+		return ErrBlobNotFound
+	}
+	for _, existing := range s.countersignatures[uuid] {
+		if existing.KeyId == countersignature.KeyId {
+			return ErrCountersignatureExists
+		}
+	}
+	s.countersignatures[uuid] = append(s.countersignatures[uuid],
+		proto.Clone(countersignature).(*blobv1.Countersignature))
+
+	return nil
+}
+
+// GetCountersignatures retrieves copies of all countersignatures of a blob, oldest first
+func (s *MemoryStorage) GetCountersignatures(_ context.Context, uuid uuid.UUID) ([]*blobv1.Countersignature, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var countersignatures []*blobv1.Countersignature
+	for _, countersignature := range s.countersignatures[uuid] {
+		countersignatures = append(countersignatures, proto.Clone(countersignature).(*blobv1.Countersignature))
+	}
+
+	return countersignatures, nil
+}
+
+// Exists checks if a blob with the given UUID exists
+func (s *MemoryStorage) Exists(_ context.Context, uuid uuid.UUID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.records[uuid]
+	return ok, nil
+}
+
+// Delete removes a blob and its countersignatures by its UUID unless it is under legal hold
+func (s *MemoryStorage) Delete(_ context.Context, uuid uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteUnlessHeld(uuid)
+}
+
+// deleteUnlessHeld removes a blob and its countersignatures unless it is under legal hold,
+// the caller must hold the write lock
+func (s *MemoryStorage) deleteUnlessHeld(uuid uuid.UUID) error {
+	if _, ok := s.records[uuid]; !ok {
+		return ErrBlobNotFound
+	}
+	if s.legalHolds[uuid] {
+		return ErrLegalHold
+	}
+	delete(s.records, uuid)
+	delete(s.countersignatures, uuid)
+
+	return nil
+}
+
+// ListBlobs retrieves the metadata of up to query.Limit blobs selected by the query, by creation time then UUID
+func (s *MemoryStorage) ListBlobs(_ context.Context, query ListQuery) ([]*blobv1.BlobMetadata, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type listed struct {
+		createdAt time.Time
+		metadata  *blobv1.BlobMetadata
+	}
+	var blobs []listed
+	for id, record := range s.records {
+		created, err := createdAt(record.Payload.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		if query.matches(created, record.Payload.Uuid, record.Payload.Labels) {
+			metadata := metadataFromRecord(record)
+			metadata.LegalHold = s.legalHolds[id]
+			blobs = append(blobs, listed{createdAt: created, metadata: metadata})
+		}
+	}
+	slices.SortFunc(blobs, func(a, b listed) int {
+		return cmp.Or(a.createdAt.Compare(b.createdAt), cmp.Compare(a.metadata.Uuid, b.metadata.Uuid))
+	})
+
+	metadata := make([]*blobv1.BlobMetadata, 0, min(query.Limit, len(blobs)))
+	for _, blob := range blobs[:min(query.Limit, len(blobs))] {
+		metadata = append(metadata, blob.metadata)
+	}
+	return metadata, nil
+}
+
+// ListExpired retrieves the metadata of up to limit blobs that expired at or before now, earliest first
+func (s *MemoryStorage) ListExpired(_ context.Context, now time.Time, limit int) ([]*blobv1.BlobMetadata, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type listed struct {
+		expiry   time.Time
+		metadata *blobv1.BlobMetadata
+	}
+	var expired []listed
+	for id, record := range s.records {
+		expires, expiring, err := expiry(record.Payload.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if expiring && !expires.After(now) && !s.legalHolds[id] {
+			expired = append(expired, listed{expiry: expires, metadata: metadataFromRecord(record)})
+		}
+	}
+	slices.SortFunc(expired, func(a, b listed) int {
+		return cmp.Or(a.expiry.Compare(b.expiry), cmp.Compare(a.metadata.Uuid, b.metadata.Uuid))
+	})
+
+	metadata := make([]*blobv1.BlobMetadata, 0, min(limit, len(expired)))
+	for _, blob := range expired[:min(limit, len(expired))] {
+		metadata = append(metadata, blob.metadata)
+	}
+	return metadata, nil
+}
+
+// Expire removes the blob and its countersignatures and stores a copy of its tombstone
+func (s *MemoryStorage) Expire(_ context.Context, uuid uuid.UUID, tombstone *blobv1.SignedTombstone) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.deleteUnlessHeld(uuid); err != nil {
+		return err
+	}
+	s.tombstones[uuid] = proto.Clone(tombstone).(*blobv1.SignedTombstone)
+
+	return nil
+}
+
+// GetTombstone retrieves a copy of the tombstone of an expired blob by its UUID
+func (s *MemoryStorage) GetTombstone(_ context.Context, uuid uuid.UUID) (*blobv1.SignedTombstone, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tombstone, ok := s.tombstones[uuid]
+	if !ok {
+		return nil, ErrTombstoneNotFound
+	}
+
+	return proto.Clone(tombstone).(*blobv1.SignedTombstone), nil
+}
+
+// AcquireContent counts an upload writing the content with the hash, after any deletion of it in progress
+func (s *MemoryStorage) AcquireContent(ctx context.Context, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.waitContentDeleted(ctx, hash); err != nil {
+		return err
+	}
+	s.pendingContent[hash]++
+
+	return nil
+}
+
+// ReleaseContent uncounts an upload counted by AcquireContent
+func (s *MemoryStorage) ReleaseContent(_ context.Context, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pendingContent[hash] <= 1 {
+		delete(s.pendingContent, hash)
+	} else {
+		s.pendingContent[hash]--
+	}
+
+	return nil
+}
+
+// DeleteContent calls deleteContent unless a stored blob has the hash or an upload holds it.
+// Only uploads of the same content wait for the deletion, the storage is not locked meanwhile.
+func (s *MemoryStorage) DeleteContent(
+	ctx context.Context,
+	hash string,
+	deleteContent func(ctx context.Context) error,
+) (bool, error) {
+	s.mu.Lock()
+	if err := s.waitContentDeleted(ctx, hash); err != nil {
+		s.mu.Unlock()
+		return false, err
+	}
+	if s.pendingContent[hash] > 0 || s.hashInUse(hash) {
+		s.mu.Unlock()
+		return false, nil
+	}
+	deleted := make(chan struct{})
+	s.deletingContent[hash] = deleted
+	s.mu.Unlock()
+
+	err := deleteContent(ctx)
+
+	s.mu.Lock()
+	delete(s.deletingContent, hash)
+	close(deleted)
+	s.mu.Unlock()
+
+	return err == nil, err
+}
+
+// waitContentDeleted waits for a deletion of the content hash in progress,
+// the caller must hold the write lock, which is released while waiting
+func (s *MemoryStorage) waitContentDeleted(ctx context.Context, hash string) error {
+	for {
+		deleted, ok := s.deletingContent[hash]
+		if !ok {
+			return nil
+		}
+		s.mu.Unlock()
+		select {
+		case <-deleted:
+			s.mu.Lock()
+		case <-ctx.Done():
+			s.mu.Lock()
+			return ctx.Err()
+		}
+	}
+}
+
+// hashInUse checks if any stored blob has the content hash, the caller must hold the lock
+func (s *MemoryStorage) hashInUse(hash string) bool {
+	for _, record := range s.records {
+		if record.Payload.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLegalHold places or releases the legal hold of a blob and records a copy of the event
+func (s *MemoryStorage) SetLegalHold(_ context.Context, uuid uuid.UUID, event *blobv1.LegalHoldEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[uuid]; !ok {
+		return ErrBlobNotFound
+	}
+	if event.Held {
+		s.legalHolds[uuid] = true
+	} else {
+		delete(s.legalHolds, uuid)
+	}
+	s.legalHoldEvents[uuid] = append(s.legalHoldEvents[uuid], proto.Clone(event).(*blobv1.LegalHoldEvent))
+
+	return nil
+}
+
+// GetLegalHoldEvents retrieves copies of the legal hold audit log of a blob, oldest first
+func (s *MemoryStorage) GetLegalHoldEvents(_ context.Context, uuid uuid.UUID) ([]*blobv1.LegalHoldEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []*blobv1.LegalHoldEvent
+	for _, event := range s.legalHoldEvents[uuid] {
+		events = append(events, proto.Clone(event).(*blobv1.LegalHoldEvent))
+	}
+
+	return events, nil
+}
+
+// Migrate is a no-op, the in-memory storage has no schema
+func (s *MemoryStorage) Migrate(_ context.Context, _ string) error {
+	return nil
+}
+
+// Ping always succeeds, the in-memory storage is always reachable
+func (s *MemoryStorage) Ping(_ context.Context) error {
+	return nil
+}
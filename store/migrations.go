@@ -4,49 +4,318 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file" // migrate filesystem driver
-	_ "github.com/lib/pq"                                // postgres driver
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/lib/pq" // postgres driver
+	dbmigrations "github.com/prit342/signed-blob-service/db-migrations"
 )
 
-// Migrate - helps migrate database schema using migration files in the directory
-func (s *PostgresStorage) Migrate(
-	_ context.Context, // context for request
-	directory string, // directory containing migration files
-) error {
-	// create a driver instance
-	driver, err := postgres.WithInstance(s.db, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("database migration initilisation failed: %w", err)
+// Schema versions this code reads and writes, the version of the last migration of each backend.
+// They are bumped with every new migration.
+const (
+	PostgresSchemaVersion uint = 14
+	SQLiteSchemaVersion   uint = 12
+)
+
+// Migration errors
+var (
+	// ErrSchemaTooNew is returned when the database was migrated by a newer release
+	ErrSchemaTooNew = errors.New("database schema is newer than this release supports")
+	// ErrSchemaDirty is returned when a migration failed half way and the schema needs fixing by hand
+	ErrSchemaDirty = errors.New("database schema is dirty after a failed migration")
+)
+
+// Migratable is implemented by the storages with a versioned database schema
+type Migratable interface {
+	// Migrator returns a migrator applying the migration files in the directory,
+	// or the migrations embedded in the binary when the directory is ""
+	Migrator(ctx context.Context, directory string) (*Migrator, error)
+	// CheckSchema refuses a schema that is dirty or newer than this release supports
+	CheckSchema(ctx context.Context) error
+}
+
+// MigrationStatus is the schema version of a database
+type MigrationStatus struct {
+	Version   uint   // last applied migration, 0 when none has been applied
+	Dirty     bool   // the last migration failed half way
+	Source    string // "embedded" or the directory of the migration files
+	Latest    uint   // last migration among the migration files
+	Supported uint   // schema version this release reads and writes
+}
+
+// Migrator applies the migration files of a directory to a database, one step at a time if need be
+type Migrator struct {
+	m          *migrate.Migrate
+	source     source.Driver
+	sourceName string       // "embedded" or the directory of the migration files
+	release    func() error // releases the database connection held by the migration driver
+	supported  uint
+	log        *slog.Logger
+}
+
+// embeddedSource is the name of the migrations embedded in the binary
+const embeddedSource = "embedded"
+
+// migrationSource opens the migrations of the backend embedded in the binary,
+// or the migration files in the directory when it is not ""
+func migrationSource(backend, directory string) (source.Driver, string, error) {
+	if directory == "" {
+		src, err := iofs.New(dbmigrations.FS, backend)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open embedded migrations: %w", err)
+		}
+		return src, embeddedSource, nil
 	}
 
 	info, err := os.Stat(directory)
 	if err != nil {
-		return fmt.Errorf("failed to get file info for migration directory: %w", err)
+		return nil, "", fmt.Errorf("failed to get file info for migration directory: %w", err)
 	}
 
 	if !info.IsDir() {
-		return fmt.Errorf("migration directory %s is not a directory", directory)
+		return nil, "", fmt.Errorf("migration directory %s is not a directory", directory)
+	}
+
+	src, err := source.Open("file://" + directory)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open migration directory: %w", err)
+	}
+	return src, directory, nil
+}
+
+// newMigrator creates a migrator applying the migrations of the backend through the database driver
+func newMigrator(
+	backend string, // storage backend, also the name of its embedded migration directory
+	directory string, // directory overriding the embedded migrations, "" to use them
+	driver database.Driver, // initialised database driver
+	release func() error, // releases the database connection held by the driver
+	supported uint, // schema version this release supports
+	log *slog.Logger, // logger for logging
+) (*Migrator, error) {
+	src, sourceName, err := migrationSource(backend, directory)
+	if err != nil {
+		_ = release()
+		return nil, err
 	}
 
-	// create a migration instance
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+directory, // Use full path for DB migration files directory
-		"postgres",          // we are using postgres driver
-		driver,              // initialised driver
-	)
+	m, err := migrate.NewWithInstance(sourceName, src, backend, driver)
 	if err != nil {
-		return fmt.Errorf("failed to create a migration DB instance: %w", err)
+		_ = src.Close()
+		_ = release()
+		return nil, fmt.Errorf("failed to create a migration DB instance: %w", err)
+	}
+
+	migrator := &Migrator{
+		m:          m,
+		source:     src,
+		sourceName: sourceName,
+		release:    release,
+		supported:  supported,
+		log:        log,
+	}
+
+	// files from another release would migrate to a schema the queries of this one do not expect
+	if directory != "" {
+		if latest, err := migrator.latest(); err == nil && latest != supported {
+			log.Warn("migration directory does not match this release",
+				"directory", directory, "latest", latest, "supported", supported)
+		}
 	}
 
-	// run the migrations
-	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+	return migrator, nil
+}
+
+// Up applies all the migrations not applied yet
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
+	m.log.Info("Database migration completed successfully", "source", m.sourceName)
+	return nil
+}
 
-	s.log.Info("Database migration completed successfully", "directory", directory)
+// Down reverts the last steps migrations
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return errors.New("number of migrations to revert must be positive")
+	}
+	if err := m.m.Steps(-steps); err != nil {
+		return fmt.Errorf("failed to revert migrations: %w", err)
+	}
+	m.log.Info("reverted database migrations", "steps", steps)
 	return nil
 }
+
+// Goto migrates up or down to the version
+func (m *Migrator) Goto(version uint) error {
+	if version > m.supported {
+		return fmt.Errorf("%w: version %d requested, %d supported", ErrSchemaTooNew, version, m.supported)
+	}
+	if err := m.m.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to migrate database to version %d: %w", version, err)
+	}
+	m.log.Info("migrated database", "schema_version", version)
+	return nil
+}
+
+// Force records the version as applied and clears the dirty flag without running any migration.
+// It is the way out after a failed migration has been fixed by hand, -1 means no migration applied.
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("failed to force database version %d: %w", version, err)
+	}
+	m.log.Warn("forced database version", "schema_version", version)
+	return nil
+}
+
+// Status returns the schema version of the database and of the migration files
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	status := &MigrationStatus{Source: m.sourceName, Supported: m.supported}
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read database version: %w", err)
+	}
+	status.Version, status.Dirty = version, dirty
+
+	if status.Latest, err = m.latest(); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// latest returns the version of the last migration of the source
+func (m *Migrator) latest() (uint, error) {
+	// the source lists the versions in order, the last one is the latest
+	var latest uint
+	next, err := m.source.First()
+	for err == nil {
+		latest = next
+		next, err = m.source.Next(next)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("failed to list migration files: %w", err)
+	}
+	return latest, nil
+}
+
+// Close releases the migration files and the database connection, the storage stays open
+func (m *Migrator) Close() error {
+	return errors.Join(m.source.Close(), m.release())
+}
+
+// checkSchema refuses a schema that is dirty or newer than the supported version
+func checkSchema(driver database.Driver, supported uint) error {
+	version, dirty, err := driver.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read database version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d, fix it and run the migrate force command", ErrSchemaDirty, version)
+	}
+	// version is -1 when no migration has been applied
+	if version > int(supported) {
+		return fmt.Errorf("%w: database is at version %d, this release supports up to %d",
+			ErrSchemaTooNew, version, supported)
+	}
+	return nil
+}
+
+// migrationDriver returns a migration driver on a connection of its own, released by the returned function
+func (s *PostgresStorage) migrationDriver(ctx context.Context) (database.Driver, func() error, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get a database connection: %w", err)
+	}
+
+	// unlike WithInstance, closing a driver created on a connection leaves the pool open
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("database migration initilisation failed: %w", err)
+	}
+
+	return driver, driver.Close, nil
+}
+
+// Migrator returns a migrator applying the PostgreSQL migration files in the directory
+func (s *PostgresStorage) Migrator(ctx context.Context, directory string) (*Migrator, error) {
+	driver, release, err := s.migrationDriver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(BackendPostgres, directory, driver, release, PostgresSchemaVersion, s.log)
+}
+
+// CheckSchema refuses a schema that is dirty or newer than this release supports
+func (s *PostgresStorage) CheckSchema(ctx context.Context) error {
+	driver, release, err := s.migrationDriver(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return checkSchema(driver, PostgresSchemaVersion)
+}
+
+// Migrate - helps migrate database schema using migration files in the directory, or the embedded ones
+func (s *PostgresStorage) Migrate(
+	ctx context.Context, // context for request
+	directory string, // directory containing migration files, "" for the embedded migrations
+) error {
+	m, err := s.Migrator(ctx, directory)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up()
+}
+
+// migrationDriver returns a migration driver on the storage database, the release function is a no-op
+// because closing the SQLite driver would close the database
+func (s *SQLiteStorage) migrationDriver() (database.Driver, func() error, error) {
+	driver, err := migratesqlite.WithInstance(s.db, &migratesqlite.Config{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("database migration initilisation failed: %w", err)
+	}
+	return driver, func() error { return nil }, nil
+}
+
+// Migrator returns a migrator applying the SQLite migration files in the directory
+func (s *SQLiteStorage) Migrator(_ context.Context, directory string) (*Migrator, error) {
+	driver, release, err := s.migrationDriver()
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(BackendSQLite, directory, driver, release, SQLiteSchemaVersion, s.log)
+}
+
+// CheckSchema refuses a schema that is dirty or newer than this release supports
+func (s *SQLiteStorage) CheckSchema(_ context.Context) error {
+	driver, _, err := s.migrationDriver()
+	if err != nil {
+		return err
+	}
+	return checkSchema(driver, SQLiteSchemaVersion)
+}
+
+// Migrate - helps migrate database schema using the SQLite migration files in the directory, or the embedded ones
+func (s *SQLiteStorage) Migrate(
+	ctx context.Context, // context for request
+	directory string, // directory containing migration files, "" for the embedded migrations
+) error {
+	m, err := s.Migrator(ctx, directory)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up()
+}
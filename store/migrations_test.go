@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchemaVersionsMatchMigrations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		directory string
+		supported uint
+	}{
+		{directory: "../db-migrations/postgres", supported: PostgresSchemaVersion},
+		{directory: "../db-migrations/sqlite", supported: SQLiteSchemaVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.directory, func(t *testing.T) {
+			t.Parallel()
+			entries, err := os.ReadDir(tt.directory)
+			if err != nil {
+				t.Fatalf("failed to read migration directory: %v", err)
+			}
+
+			var latest uint64
+			for _, entry := range entries {
+				prefix, _, _ := strings.Cut(entry.Name(), "_")
+				version, err := strconv.ParseUint(prefix, 10, 64)
+				if err != nil {
+					t.Fatalf("unexpected migration file name %s", entry.Name())
+				}
+				latest = max(latest, version)
+			}
+			if uint(latest) != tt.supported {
+				t.Fatalf("the latest migration is %d but the supported schema version is %d", latest, tt.supported)
+			}
+		})
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s, m := newSQLiteMigrator(t)
+
+	expectStatus := func(version uint, dirty bool) {
+		t.Helper()
+		status, err := m.Status()
+		if err != nil {
+			t.Fatalf("failed to get migration status: %v", err)
+		}
+		if status.Version != version || status.Dirty != dirty {
+			t.Fatalf("expected version %d dirty %v but got version %d dirty %v",
+				version, dirty, status.Version, status.Dirty)
+		}
+		if status.Latest != SQLiteSchemaVersion || status.Supported != SQLiteSchemaVersion {
+			t.Fatalf("expected latest and supported version %d but got %d and %d",
+				SQLiteSchemaVersion, status.Latest, status.Supported)
+		}
+	}
+
+	expectStatus(0, false)
+	if err := m.Up(); err != nil {
+		t.Fatalf("failed to migrate up: %v", err)
+	}
+	expectStatus(SQLiteSchemaVersion, false)
+	if err := s.CheckSchema(ctx); err != nil {
+		t.Fatalf("unexpected schema check error: %v", err)
+	}
+
+	if err := m.Down(2); err != nil {
+		t.Fatalf("failed to migrate down: %v", err)
+	}
+	expectStatus(SQLiteSchemaVersion-2, false)
+	if err := m.Down(0); err == nil {
+		t.Fatal("expected error for zero steps but got none")
+	}
+
+	if err := m.Goto(SQLiteSchemaVersion); err != nil {
+		t.Fatalf("failed to migrate to the latest version: %v", err)
+	}
+	expectStatus(SQLiteSchemaVersion, false)
+	if err := m.Goto(SQLiteSchemaVersion + 1); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew but got %v", err)
+	}
+
+	// a failed migration leaves the schema dirty until the version is forced
+	if _, err := s.db.ExecContext(ctx, `UPDATE schema_migrations SET dirty = 1`); err != nil {
+		t.Fatalf("failed to mark the schema dirty: %v", err)
+	}
+	expectStatus(SQLiteSchemaVersion, true)
+	if err := s.CheckSchema(ctx); !errors.Is(err, ErrSchemaDirty) {
+		t.Fatalf("expected ErrSchemaDirty but got %v", err)
+	}
+	if err := m.Force(int(SQLiteSchemaVersion)); err != nil {
+		t.Fatalf("failed to force version: %v", err)
+	}
+	expectStatus(SQLiteSchemaVersion, false)
+
+	// a schema migrated by a newer release is refused
+	if err := m.Force(int(SQLiteSchemaVersion) + 1); err != nil {
+		t.Fatalf("failed to force version: %v", err)
+	}
+	if err := s.CheckSchema(ctx); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew but got %v", err)
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	s, err := NewSQLiteStorage("sqlite://"+filepath.Join(t.TempDir(), "blobs.db"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create SQLite storage: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	// no directory selects the migrations embedded in the binary
+	m, err := s.Migrator(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("failed to migrate up: %v", err)
+	}
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("failed to get migration status: %v", err)
+	}
+	if status.Source != embeddedSource || status.Version != SQLiteSchemaVersion || status.Latest != SQLiteSchemaVersion {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	if _, err := s.Migrator(ctx, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected error for a missing migration directory but got none")
+	}
+}
+
+// newSQLiteMigrator creates an empty SQLite database and a migrator for it
+func newSQLiteMigrator(t *testing.T) (*SQLiteStorage, *Migrator) {
+	t.Helper()
+	ctx := context.Background()
+
+	s, err := NewSQLiteStorage("sqlite://"+filepath.Join(t.TempDir(), "blobs.db"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create SQLite storage: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	m, err := s.Migrator(ctx, "../db-migrations/sqlite")
+	if err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	return s, m
+}
+
+func TestSQLiteMigrationBackfill(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s, m := newSQLiteMigrator(t)
+
+	// rows stored before created_at and expiry were added
+	if err := m.Goto(6); err != nil {
+		t.Fatalf("failed to migrate to version 6: %v", err)
+	}
+	rows := []struct {
+		timestamp string
+		expiresAt string
+	}{
+		{timestamp: "2025-07-28T17:42:05.123456Z", expiresAt: "2025-10-26T17:42:05.5Z"},
+		{timestamp: "2025-07-28T17:42:05Z"},
+		{timestamp: "2025-07-28T19:42:05.000001+02:00", expiresAt: "2025-10-26T17:42:05.123456789Z"},
+		{timestamp: "1969-12-31T23:59:59.25Z", expiresAt: "2025-10-26T17:42:05Z"},
+	}
+	for i, row := range rows {
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT INTO signed_blobs (uuid, blob, hash, timestamp, signature, expires_at) VALUES (?, '', '', ?, x'01', ?)`,
+			strconv.Itoa(i), row.timestamp, row.expiresAt); err != nil {
+			t.Fatalf("failed to insert row: %v", err)
+		}
+	}
+	if err := m.Goto(SQLiteSchemaVersion); err != nil {
+		t.Fatalf("failed to migrate to the latest version: %v", err)
+	}
+
+	// the backfill keeps the fractional seconds, truncated to microseconds like the storage writes them
+	unixMicro := func(value string) int64 {
+		ts, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", value, err)
+		}
+		return ts.UnixMicro()
+	}
+	for i, row := range rows {
+		var createdAt int64
+		var expiry sql.NullInt64
+		if err := s.db.QueryRowContext(ctx, `SELECT created_at, expiry FROM signed_blobs WHERE uuid = ?`,
+			strconv.Itoa(i)).Scan(&createdAt, &expiry); err != nil {
+			t.Fatalf("failed to read row: %v", err)
+		}
+		if expected := unixMicro(row.timestamp); createdAt != expected {
+			t.Fatalf("expected created_at %d for %q but got %d", expected, row.timestamp, createdAt)
+		}
+		if row.expiresAt == "" {
+			if expiry.Valid {
+				t.Fatalf("expected no expiry but got %d", expiry.Int64)
+			}
+			continue
+		}
+		if expected := unixMicro(row.expiresAt); !expiry.Valid || expiry.Int64 != expected {
+			t.Fatalf("expected expiry %d for %q but got %v", expected, row.expiresAt, expiry)
+		}
+	}
+}
+
+func TestSQLiteMigrationBackfillInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		version   uint // the version the backfill fails to migrate to
+		timestamp string
+		expiresAt string
+	}{
+		{name: "timestamp", version: 7, timestamp: "not a timestamp"},
+		{name: "expiry", version: 12, timestamp: "2025-07-28T17:42:05Z", expiresAt: "next tuesday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			s, m := newSQLiteMigrator(t)
+
+			if err := m.Goto(tt.version - 1); err != nil {
+				t.Fatalf("failed to migrate to version %d: %v", tt.version-1, err)
+			}
+			if _, err := s.db.ExecContext(ctx,
+				`INSERT INTO signed_blobs (uuid, blob, hash, timestamp, signature, expires_at) VALUES ('1', '', '', ?, x'01', ?)`,
+				tt.timestamp, tt.expiresAt); err != nil {
+				t.Fatalf("failed to insert row: %v", err)
+			}
+			if err := m.Goto(tt.version); err == nil || !strings.Contains(err.Error(), "not a timestamp") {
+				t.Fatalf("expected the backfill to fail but got %v", err)
+			}
+		})
+	}
+}
@@ -8,19 +8,46 @@ import (
 	"log/slog"
 	"time"
 
-	"github.com/google/uuid"
-	_ "github.com/lib/pq" // postgres driver
-	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/lib/pq" // postgres driver
 )
 
 const (
 	selectTimeQuery = `SELECT NOW()`
 )
 
+// PostgreSQL error codes mapped to storage errors
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
 // PostgresStorage implements the Storage interface for PostgreSQL
 type PostgresStorage struct {
-	db  *sql.DB
-	log *slog.Logger
+	sqlStorage
+}
+
+// PoolConfig sizes the PostgreSQL connection pools, zero values keep the database/sql defaults
+type PoolConfig struct {
+	MaxOpenConns    int           // maximum number of open connections, 0 is unlimited
+	MaxIdleConns    int           // maximum number of idle connections kept around, 0 keeps 2
+	ConnMaxLifetime time.Duration // connections are closed after this long, 0 keeps them forever
+	ConnMaxIdleTime time.Duration // idle connections are closed after this long, 0 keeps them forever
+}
+
+// WithPool sizes the connection pool of the primary and of the read replica
+func WithPool(pool PoolConfig) Option {
+	return func(o *options) {
+		o.pool = pool
+	}
+}
+
+// WithReadReplica sends the read-only queries, such as fetching a blob or listing expired blobs,
+// to the read replica with the DSN. Writes, and the reads that decide a write, go to the primary.
+// Replicas lag behind, a blob may not be readable right after it was stored.
+func WithReadReplica(dsn string) Option {
+	return func(o *options) {
+		o.replicaDSN = dsn
+	}
 }
 
 // NewPostgresStorage creates a new PostgreSQL storage implementation
@@ -29,6 +56,7 @@ func NewPostgresStorage(
 	log *slog.Logger, // Logger for logging
 	retryInterval time.Duration, // retryInterval for pinging the database
 	maxReadyDuration time.Duration, // Maximum duration to wait for the database to be ready
+	opts ...Option, // optional features such as compression and encryption
 ) (*PostgresStorage, error) {
 	// validate the DSN and logger
 	if dsn == "" {
@@ -38,109 +66,36 @@ func NewPostgresStorage(
 		return nil, errors.New("log parameter cannot be nil")
 	}
 
+	o := newOptions(opts)
+
 	ctx, cancel := context.WithTimeout(context.Background(), maxReadyDuration)
 	defer cancel()
 	// we expect the database to be ready within maxReadyDuration
-	db, err := pingWithRetry(ctx, dsn, retryInterval)
+	db, err := pingWithRetry(ctx, dsn, retryInterval, o.pool)
 	if err != nil {
 		log.Error("database was not ready", "error", err)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &PostgresStorage{db: db, log: log}, nil
-}
-
-// Store saves a new blob to the database
-func (s *PostgresStorage) Store(ctx context.Context, record *blobv1.SignedBlobRecord) error {
-	query := `
-		INSERT INTO signed_blobs (uuid, blob, hash, timestamp, signature)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-
-	_, err := s.db.ExecContext(ctx, query,
-		record.Payload.Uuid,
-		record.Payload.Blob,
-		record.Payload.Hash,
-		record.Payload.Timestamp,
-		record.Signature, // signature is a byte slice
-	)
-
-	if err != nil {
-		s.log.Error("failed to store blob", "error", err)
-	}
-
-	return err
-}
-
-// GetByUUID retrieves a blob by its UUID
-func (s *PostgresStorage) GetByUUID(ctx context.Context, uuid uuid.UUID) (*blobv1.SignedBlobRecord, error) {
-	query := `
-		SELECT uuid, blob, hash, timestamp, signature
-		FROM signed_blobs
-		WHERE uuid = $1
-	`
-
-	record := &blobv1.SignedBlobRecord{
-		Payload: &blobv1.BlobRecord{},
-	}
-	err := s.db.QueryRowContext(ctx, query, uuid).Scan(
-		&record.Payload.Uuid,
-		&record.Payload.Blob,
-		&record.Payload.Hash,
-		&record.Payload.Timestamp,
-		&record.Signature,
-	)
-
-	if err != nil {
-		s.log.Error("failed to retrieve blob", "error", err, "uuid", uuid)
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrBlobNotFound
+	reader := db
+	if o.replicaDSN != "" {
+		if reader, err = pingWithRetry(ctx, o.replicaDSN, retryInterval, o.pool); err != nil {
+			_ = db.Close()
+			log.Error("read replica was not ready", "error", err)
+			return nil, fmt.Errorf("failed to ping read replica: %w", err)
 		}
-		return nil, err
-	}
-
-	return record, nil
-}
-
-// Exists checks if a blob with the given UUID exists
-func (s *PostgresStorage) Exists(ctx context.Context, uuid uuid.UUID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM signed_blobs WHERE uuid = $1)`
-
-	var exists bool
-	err := s.db.QueryRowContext(ctx, query, uuid).Scan(&exists)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
-}
-
-// Delete removes a blob by its UUID
-func (s *PostgresStorage) Delete(ctx context.Context, uuid uuid.UUID) error {
-	query := `DELETE FROM signed_blobs WHERE uuid = $1`
-
-	result, err := s.db.ExecContext(ctx, query, uuid)
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
 	}
 
-	if rowsAffected == 0 {
-		return ErrBlobNotFound
-	}
-
-	return nil
+	return &PostgresStorage{sqlStorage{db: db, reader: reader, dialect: postgresDialect, log: log, opts: o}}, nil
 }
 
-// PingWithRetry runs a simple query to check if the database is alive, retrying till
+// PingWithRetry opens the database and runs a simple query to check it is alive, retrying till
+// the context is done. Handles that fail the check are closed before the next attempt.
 func pingWithRetry(
 	ctx context.Context, // ctx is the context with timeout for the ping operation
 	dataSourceName string, // dataSourceName is the connection string for the database
 	retryInterval time.Duration, // retryInterval is the time to wait between retries
+	pool PoolConfig, // pool sizes the connection pool of the returned handle
 ) (*sql.DB, error) {
 
 	ticker := time.NewTicker(retryInterval)
@@ -153,23 +108,43 @@ func pingWithRetry(
 			return nil, fmt.Errorf("unable to ping database: %w", ctx.Err())
 		case <-ticker.C:
 			db, err := sql.Open("postgres", dataSourceName)
-			if err == nil { // if no error
-				// run a simple query to check if the database is alive
-				if err = db.QueryRowContext(ctx, selectTimeQuery).Scan(&ts); err == nil {
-					return db, err
-				}
+			if err != nil {
+				// only an unregistered driver fails to open, retrying will not help
+				return nil, fmt.Errorf("failed to open database: %w", err)
+			}
+			// run a simple query to check if the database is alive
+			if err := db.QueryRowContext(ctx, selectTimeQuery).Scan(&ts); err != nil {
+				_ = db.Close()
+				continue
 			}
 
+			db.SetMaxOpenConns(pool.MaxOpenConns)
+			db.SetMaxIdleConns(pool.MaxIdleConns)
+			db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+			db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+			return db, nil
 		}
 	}
 
 }
 
-// Ping checks if the storage is reachable
-func (s *PostgresStorage) Ping(ctx context.Context) error {
-	if _, err := s.db.ExecContext(ctx, selectTimeQuery); err != nil {
-		s.log.Error("failed to ping database", "error", err)
-		return fmt.Errorf("failed to ping database: %w", err)
+// postgresDialect writes the statements for PostgreSQL, label filters use the GIN index on labels
+var postgresDialect = sqlDialect{
+	placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	timeValue:   func(t time.Time) any { return t },
+	hasLabels: func(labels map[string]string, arg func(value any) string) string {
+		return "labels @> " + arg(labelsColumn(labels)) + "::jsonb"
+	},
+	blobLength:            "octet_length(blob)",
+	isUniqueViolation:     func(err error) bool { return postgresErrorCode(err) == pgUniqueViolation },
+	isForeignKeyViolation: func(err error) bool { return postgresErrorCode(err) == pgForeignKeyViolation },
+}
+
+// postgresErrorCode returns the SQLSTATE code of err, or "" when it is not a PostgreSQL error
+func postgresErrorCode(err error) pq.ErrorCode {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code
 	}
-	return nil
+	return ""
 }
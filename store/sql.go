@@ -0,0 +1,661 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+)
+
+// sqlStorage implements the Storage interface on a SQL database, the backends only differ by their
+// dialect and by how they open and migrate the database
+type sqlStorage struct {
+	db      *sql.DB // primary, takes all the writes
+	reader  *sql.DB // read replica serving the read-only queries, the primary when there is none
+	dialect sqlDialect
+	log     *slog.Logger
+	opts    options
+}
+
+// Store saves a new blob to the database
+func (s *sqlStorage) Store(ctx context.Context, record *blobv1.SignedBlobRecord) error {
+	row, err := recordRow(s.opts, s.dialect, record)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, insertStatement(s.dialect, 1), row...)
+	if err != nil {
+		s.log.Error("failed to store blob", "error", err)
+		if s.dialect.isUniqueViolation(err) {
+			return ErrBlobExists
+		}
+	}
+
+	return err
+}
+
+// StoreBatch saves new blobs to the database in a single transaction, either all of them or none
+func (s *sqlStorage) StoreBatch(ctx context.Context, records []*blobv1.SignedBlobRecord) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // no-op once committed
+	}()
+
+	if err := insertRecords(ctx, tx, s.opts, s.dialect, records); err != nil {
+		s.log.Error("failed to store blobs", "error", err, "count", len(records))
+		if s.dialect.isUniqueViolation(err) {
+			return ErrBlobExists
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.log.Error("failed to commit blobs", "error", err, "count", len(records))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUUID retrieves a blob by its UUID
+func (s *sqlStorage) GetByUUID(ctx context.Context, uuid uuid.UUID) (*blobv1.SignedBlobRecord, error) {
+	query := s.dialect.rebind(`
+		SELECT uuid, blob, hash, timestamp, signature, detached, filename, size, key_id, expires_at,
+			codec, encoded_blob, master_key_id, wrapped_key, labels, content_type, inclusion_proof
+		FROM signed_blobs
+		WHERE uuid = ?
+	`)
+
+	record := &blobv1.SignedBlobRecord{
+		Payload: &blobv1.BlobRecord{},
+	}
+	var content encodedContent
+	err := s.reader.QueryRowContext(ctx, query, uuid.String()).Scan(
+		&record.Payload.Uuid,
+		&record.Payload.Blob,
+		&record.Payload.Hash,
+		&record.Payload.Timestamp,
+		&record.Signature,
+		&record.Payload.Detached,
+		&record.Payload.Filename,
+		&content.size,
+		&record.KeyId,
+		&record.Payload.ExpiresAt,
+		&content.codec,
+		&content.data,
+		&content.masterKeyID,
+		&content.wrappedKey,
+		labelsScanner{&record.Payload.Labels},
+		&record.Payload.ContentType,
+		inclusionProofScanner{&record.InclusionProof},
+	)
+
+	if err != nil {
+		s.log.Error("failed to retrieve blob", "error", err, "uuid", uuid)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+
+	// only detached records carry a size in their signed payload
+	if record.Payload.Detached {
+		record.Payload.Size = content.size
+	}
+	if content.encoded() {
+		if record.Payload.Blob, err = decodeContent(s.opts, record.Payload.Uuid, record.Payload.Hash, &content); err != nil {
+			s.log.Error("failed to decode blob content", "error", err, "uuid", uuid,
+				"codec", content.codec, "master_key_id", content.masterKeyID)
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// GetMetadata retrieves the metadata of a blob by its UUID without reading its content
+func (s *sqlStorage) GetMetadata(ctx context.Context, uuid uuid.UUID) (*blobv1.BlobMetadata, error) {
+	// the size of uploaded content is computed by the database, detached records carry the declared size
+	query := s.dialect.rebind(`
+		SELECT uuid, hash, timestamp, detached, filename,
+			` + s.dialect.sizeColumn() + `, key_id, expires_at, legal_hold, labels, content_type
+		FROM signed_blobs
+		WHERE uuid = ?
+	`)
+
+	metadata := &blobv1.BlobMetadata{}
+	err := s.reader.QueryRowContext(ctx, query, uuid.String()).Scan(
+		&metadata.Uuid,
+		&metadata.Hash,
+		&metadata.Timestamp,
+		&metadata.Detached,
+		&metadata.Filename,
+		&metadata.Size,
+		&metadata.KeyId,
+		&metadata.ExpiresAt,
+		&metadata.LegalHold,
+		labelsScanner{&metadata.Labels},
+		&metadata.ContentType,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBlobNotFound
+		}
+		s.log.Error("failed to retrieve blob metadata", "error", err, "uuid", uuid)
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// AddCountersignature stores a countersignature for the blob with the given UUID
+func (s *sqlStorage) AddCountersignature(
+	ctx context.Context,
+	uuid uuid.UUID,
+	countersignature *blobv1.Countersignature,
+) error {
+	query := s.dialect.rebind(`
+		INSERT INTO countersignatures (uuid, key_id, signature, timestamp)
+		VALUES (?, ?, ?, ?)
+	`)
+
+	_, err := s.db.ExecContext(ctx, query,
+		uuid.String(),
+		countersignature.KeyId,
+		countersignature.Signature,
+		countersignature.Timestamp,
+	)
+
+	if err != nil {
+		s.log.Error("failed to store countersignature", "error", err, "uuid", uuid)
+		switch {
+		case s.dialect.isForeignKeyViolation(err): // the record does not exist
+			return ErrBlobNotFound
+		case s.dialect.isUniqueViolation(err): // the key has already countersigned the record
+			return ErrCountersignatureExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetCountersignatures retrieves all countersignatures of a blob, oldest first
+func (s *sqlStorage) GetCountersignatures(ctx context.Context, uuid uuid.UUID) ([]*blobv1.Countersignature, error) {
+	query := s.dialect.rebind(`
+		SELECT key_id, signature, timestamp
+		FROM countersignatures
+		WHERE uuid = ?
+		ORDER BY timestamp, key_id
+	`)
+
+	rows, err := s.reader.QueryContext(ctx, query, uuid.String())
+	if err != nil {
+		s.log.Error("failed to retrieve countersignatures", "error", err, "uuid", uuid)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var countersignatures []*blobv1.Countersignature
+	for rows.Next() {
+		countersignature := &blobv1.Countersignature{}
+		if err := rows.Scan(
+			&countersignature.KeyId,
+			&countersignature.Signature,
+			&countersignature.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		countersignatures = append(countersignatures, countersignature)
+	}
+
+	return countersignatures, rows.Err()
+}
+
+// Exists checks if a blob with the given UUID exists
+func (s *sqlStorage) Exists(ctx context.Context, uuid uuid.UUID) (bool, error) {
+	query := s.dialect.rebind(`SELECT EXISTS(SELECT 1 FROM signed_blobs WHERE uuid = ?)`)
+
+	var exists bool
+	err := s.reader.QueryRowContext(ctx, query, uuid.String()).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Delete removes a blob by its UUID unless it is under legal hold
+func (s *sqlStorage) Delete(ctx context.Context, uuid uuid.UUID) error {
+	return s.deleteUnlessHeld(ctx, s.db, uuid)
+}
+
+// deleteUnlessHeld deletes the blob, its countersignatures by cascade, unless it is under legal hold
+func (s *sqlStorage) deleteUnlessHeld(ctx context.Context, db sqlExecutor, uuid uuid.UUID) error {
+	query := s.dialect.rebind(`DELETE FROM signed_blobs WHERE uuid = ? AND NOT legal_hold`)
+
+	result, err := db.ExecContext(ctx, query, uuid.String())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		// nothing was deleted, either the blob does not exist or it is held
+		var held bool
+		err := db.QueryRowContext(ctx, s.dialect.rebind(`SELECT legal_hold FROM signed_blobs WHERE uuid = ?`), uuid.String()).Scan(&held)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrBlobNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return ErrLegalHold
+	}
+
+	return nil
+}
+
+// ListBlobs retrieves the metadata of up to query.Limit blobs selected by the query, by creation time then UUID
+func (s *sqlStorage) ListBlobs(ctx context.Context, query ListQuery) ([]*blobv1.BlobMetadata, error) {
+	where, args := query.conditions(s.dialect)
+	statement := `
+		SELECT uuid, hash, timestamp, detached, filename,
+			` + s.dialect.sizeColumn() + `, key_id, expires_at, legal_hold, labels, content_type
+		FROM signed_blobs
+		` + where + `
+		ORDER BY created_at, uuid
+		LIMIT ` + s.dialect.placeholder(len(args)+1)
+
+	rows, err := s.reader.QueryContext(ctx, statement, append(args, query.Limit)...)
+	if err != nil {
+		s.log.Error("failed to list blobs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blobs []*blobv1.BlobMetadata
+	for rows.Next() {
+		metadata := &blobv1.BlobMetadata{}
+		if err := rows.Scan(
+			&metadata.Uuid,
+			&metadata.Hash,
+			&metadata.Timestamp,
+			&metadata.Detached,
+			&metadata.Filename,
+			&metadata.Size,
+			&metadata.KeyId,
+			&metadata.ExpiresAt,
+			&metadata.LegalHold,
+			labelsScanner{&metadata.Labels},
+			&metadata.ContentType,
+		); err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, metadata)
+	}
+
+	return blobs, rows.Err()
+}
+
+// ListExpired retrieves the metadata of up to limit blobs that expired at or before now, earliest first
+func (s *sqlStorage) ListExpired(ctx context.Context, now time.Time, limit int) ([]*blobv1.BlobMetadata, error) {
+	query := s.dialect.rebind(`
+		SELECT uuid, hash, timestamp, detached, filename,
+			` + s.dialect.sizeColumn() + `, key_id, expires_at
+		FROM signed_blobs
+		WHERE expiry IS NOT NULL AND expiry <= ? AND NOT legal_hold
+		ORDER BY expiry, uuid
+		LIMIT ?
+	`)
+
+	// the reaper deletes what it lists, a lagging replica would list blobs already deleted
+	rows, err := s.db.QueryContext(ctx, query, s.dialect.timeValue(now.UTC()), limit)
+	if err != nil {
+		s.log.Error("failed to list expired blobs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expired []*blobv1.BlobMetadata
+	for rows.Next() {
+		metadata := &blobv1.BlobMetadata{}
+		if err := rows.Scan(
+			&metadata.Uuid,
+			&metadata.Hash,
+			&metadata.Timestamp,
+			&metadata.Detached,
+			&metadata.Filename,
+			&metadata.Size,
+			&metadata.KeyId,
+			&metadata.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		expired = append(expired, metadata)
+	}
+
+	return expired, rows.Err()
+}
+
+// Expire deletes the blob, its countersignatures by cascade, and stores its tombstone in one transaction
+func (s *sqlStorage) Expire(ctx context.Context, uuid uuid.UUID, tombstone *blobv1.SignedTombstone) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // no-op once committed
+	}()
+
+	if err := s.deleteUnlessHeld(ctx, tx, uuid); err != nil {
+		if !errors.Is(err, ErrBlobNotFound) && !errors.Is(err, ErrLegalHold) {
+			s.log.Error("failed to delete expired blob", "error", err, "uuid", uuid)
+		}
+		return err
+	}
+
+	query := s.dialect.rebind(`
+		INSERT INTO tombstones (uuid, hash, timestamp, expires_at, deleted_at, signature, key_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+
+	if _, err := tx.ExecContext(ctx, query,
+		uuid.String(),
+		tombstone.Payload.Hash,
+		tombstone.Payload.Timestamp,
+		tombstone.Payload.ExpiresAt,
+		tombstone.Payload.DeletedAt,
+		tombstone.Signature,
+		tombstone.KeyId,
+	); err != nil {
+		s.log.Error("failed to store tombstone", "error", err, "uuid", uuid)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTombstone retrieves the tombstone of an expired blob by its UUID
+func (s *sqlStorage) GetTombstone(ctx context.Context, uuid uuid.UUID) (*blobv1.SignedTombstone, error) {
+	query := s.dialect.rebind(`
+		SELECT uuid, hash, timestamp, expires_at, deleted_at, signature, key_id
+		FROM tombstones
+		WHERE uuid = ?
+	`)
+
+	tombstone := &blobv1.SignedTombstone{
+		Payload: &blobv1.Tombstone{},
+	}
+	err := s.reader.QueryRowContext(ctx, query, uuid.String()).Scan(
+		&tombstone.Payload.Uuid,
+		&tombstone.Payload.Hash,
+		&tombstone.Payload.Timestamp,
+		&tombstone.Payload.ExpiresAt,
+		&tombstone.Payload.DeletedAt,
+		&tombstone.Signature,
+		&tombstone.KeyId,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTombstoneNotFound
+		}
+		s.log.Error("failed to retrieve tombstone", "error", err, "uuid", uuid)
+		return nil, err
+	}
+
+	return tombstone, nil
+}
+
+// AcquireContent counts an upload writing the content with the hash, the row of the hash is locked
+// by a DeleteContent in progress so the upload waits for it
+func (s *sqlStorage) AcquireContent(ctx context.Context, hash string) error {
+	query := s.dialect.rebind(`
+		INSERT INTO content_refs (hash, pending) VALUES (?, 1)
+		ON CONFLICT (hash) DO UPDATE SET pending = content_refs.pending + 1
+	`)
+
+	if _, err := s.db.ExecContext(ctx, query, hash); err != nil {
+		s.log.Error("failed to acquire blob content", "error", err, "hash", hash)
+		return err
+	}
+
+	return nil
+}
+
+// ReleaseContent uncounts an upload counted by AcquireContent
+func (s *sqlStorage) ReleaseContent(ctx context.Context, hash string) error {
+	query := s.dialect.rebind(`UPDATE content_refs SET pending = pending - 1 WHERE hash = ? AND pending > 0`)
+
+	if _, err := s.db.ExecContext(ctx, query, hash); err != nil {
+		s.log.Error("failed to release blob content", "error", err, "hash", hash)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteContent locks the row of the hash, then deletes the content and the row unless a stored
+// blob has the hash or an upload holds it. The lock is held while deleteContent runs, so an upload
+// of the same content waits and writes it again rather than finding it about to disappear.
+func (s *sqlStorage) DeleteContent(
+	ctx context.Context,
+	hash string,
+	deleteContent func(ctx context.Context) error,
+) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = tx.Rollback() // no-op once committed
+	}()
+
+	// updating the row locks it, in SQLite the whole database, until the transaction ends
+	lock := s.dialect.rebind(`
+		INSERT INTO content_refs (hash) VALUES (?)
+		ON CONFLICT (hash) DO UPDATE SET pending = content_refs.pending
+		RETURNING pending
+	`)
+	var pending int
+	if err := tx.QueryRowContext(ctx, lock, hash).Scan(&pending); err != nil {
+		s.log.Error("failed to lock blob content", "error", err, "hash", hash)
+		return false, err
+	}
+
+	// records stored by uploads that released the content are visible once the lock is held
+	var inUse bool
+	query := s.dialect.rebind(`SELECT EXISTS(SELECT 1 FROM signed_blobs WHERE hash = ?)`)
+	if err := tx.QueryRowContext(ctx, query, hash).Scan(&inUse); err != nil {
+		return false, err
+	}
+	if pending > 0 || inUse {
+		return false, tx.Commit()
+	}
+
+	if err := deleteContent(ctx); err != nil {
+		return false, err
+	}
+
+	// when the commit fails after the content is gone the row stays, the next upload writes it again
+	if _, err := tx.ExecContext(ctx, s.dialect.rebind(`DELETE FROM content_refs WHERE hash = ?`), hash); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// SetLegalHold places or releases the legal hold of a blob and records the event in one transaction
+func (s *sqlStorage) SetLegalHold(ctx context.Context, uuid uuid.UUID, event *blobv1.LegalHoldEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // no-op once committed
+	}()
+
+	result, err := tx.ExecContext(ctx, s.dialect.rebind(`UPDATE signed_blobs SET legal_hold = ? WHERE uuid = ?`),
+		event.Held, uuid.String())
+	if err != nil {
+		s.log.Error("failed to set legal hold", "error", err, "uuid", uuid)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrBlobNotFound
+	}
+
+	query := s.dialect.rebind(`
+		INSERT INTO legal_hold_events (uuid, held, reason, requested_by, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+
+	if _, err := tx.ExecContext(ctx, query,
+		uuid.String(),
+		event.Held,
+		event.Reason,
+		event.RequestedBy,
+		event.Timestamp,
+	); err != nil {
+		s.log.Error("failed to record legal hold event", "error", err, "uuid", uuid)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetLegalHoldEvents retrieves the legal hold audit log of a blob, oldest first
+func (s *sqlStorage) GetLegalHoldEvents(ctx context.Context, uuid uuid.UUID) ([]*blobv1.LegalHoldEvent, error) {
+	query := s.dialect.rebind(`
+		SELECT uuid, held, reason, requested_by, timestamp
+		FROM legal_hold_events
+		WHERE uuid = ?
+		ORDER BY id
+	`)
+
+	rows, err := s.reader.QueryContext(ctx, query, uuid.String())
+	if err != nil {
+		s.log.Error("failed to retrieve legal hold events", "error", err, "uuid", uuid)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*blobv1.LegalHoldEvent
+	for rows.Next() {
+		event := &blobv1.LegalHoldEvent{}
+		if err := rows.Scan(
+			&event.Uuid,
+			&event.Held,
+			&event.Reason,
+			&event.RequestedBy,
+			&event.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// RewrapKeys re-wraps the data keys of up to batchSize records under the active master key.
+// The content itself is not re-encrypted, only the data keys are.
+func (s *sqlStorage) RewrapKeys(ctx context.Context, batchSize int) (int, error) {
+	if s.opts.keyring == nil {
+		return 0, ErrEncryptionDisabled
+	}
+	active := s.opts.keyring.ActiveKeyID()
+
+	query := s.dialect.rebind(`
+		SELECT uuid, master_key_id, wrapped_key
+		FROM signed_blobs
+		WHERE master_key_id <> '' AND master_key_id <> ?
+		LIMIT ?
+	`)
+
+	rows, err := s.db.QueryContext(ctx, query, active, batchSize)
+	if err != nil {
+		s.log.Error("failed to list records to re-wrap", "error", err)
+		return 0, err
+	}
+	// the rows are read in full before updating, the SQLite storage holds a single connection
+	var stale []staleKey
+	for rows.Next() {
+		var key staleKey
+		if err := rows.Scan(&key.uuid, &key.masterKeyID, &key.wrapped); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		stale = append(stale, key)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, key := range stale {
+		wrapped, err := s.opts.keyring.rewrapKey(key.uuid, key.masterKeyID, key.wrapped)
+		if err != nil {
+			return rewrapped, fmt.Errorf("failed to re-wrap data key of blob %s: %w", key.uuid, err)
+		}
+
+		// the record may have been deleted or re-wrapped by another replica meanwhile
+		result, err := s.db.ExecContext(ctx,
+			s.dialect.rebind(`UPDATE signed_blobs SET master_key_id = ?, wrapped_key = ? WHERE uuid = ? AND master_key_id = ?`),
+			active, wrapped, key.uuid, key.masterKeyID)
+		if err != nil {
+			s.log.Error("failed to store re-wrapped data key", "error", err, "uuid", key.uuid)
+			return rewrapped, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return rewrapped, err
+		}
+		rewrapped += int(rowsAffected)
+	}
+
+	return rewrapped, nil
+}
+
+// Ping checks if the storage is reachable
+func (s *sqlStorage) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		s.log.Error("failed to ping database", "error", err)
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	if s.reader != s.db {
+		if err := s.reader.PingContext(ctx); err != nil {
+			s.log.Error("failed to ping read replica", "error", err)
+			return fmt.Errorf("failed to ping read replica: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connections to the primary and to the read replica
+func (s *sqlStorage) Close() error {
+	if s.reader != s.db {
+		return errors.Join(s.db.Close(), s.reader.Close())
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite" // pure-Go sqlite driver, no cgo needed
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqlitePragmas are applied to every connection opened by the SQLite storage:
+// foreign keys are off by default in SQLite, WAL lets readers run alongside the writer
+// and the busy timeout waits for locks instead of failing straight away.
+var sqlitePragmas = []string{
+	"_pragma=foreign_keys(1)",
+	"_pragma=journal_mode(WAL)",
+	"_pragma=busy_timeout(5000)",
+}
+
+// SQLiteStorage implements the Storage interface for an embedded SQLite database
+type SQLiteStorage struct {
+	sqlStorage
+}
+
+// NewSQLiteStorage creates a new SQLite storage implementation.
+// The DSN is "sqlite://" followed by the database file path, e.g. sqlite://./blobs.db
+// or sqlite:///var/lib/signed-blob-service/blobs.db. The file is created if it does not exist.
+func NewSQLiteStorage(
+	dsn string, // Data Source Name for the SQLite database
+	log *slog.Logger, // Logger for logging
+	opts ...Option, // optional features such as compression and encryption
+) (*SQLiteStorage, error) {
+	if dsn == "" {
+		return nil, errors.New("DataSourceName (DNS) parameter cannot be empty")
+	}
+	if log == nil {
+		return nil, errors.New("log parameter cannot be nil")
+	}
+
+	path, ok := strings.CutPrefix(dsn, "sqlite://")
+	if !ok || path == "" || strings.HasPrefix(path, "?") {
+		return nil, fmt.Errorf("invalid SQLite DSN %q, expected sqlite://<path>", dsn)
+	}
+
+	// any query parameters in the DSN are passed on to the driver along with the pragmas
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	driverDSN := path + separator + strings.Join(sqlitePragmas, "&")
+
+	db, err := sql.Open("sqlite", driverDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	// SQLite allows a single writer, one connection avoids SQLITE_BUSY errors between our own connections
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		log.Error("database was not ready", "error", err)
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	// there is no replica, the reads go to the database itself
+	return &SQLiteStorage{sqlStorage{db: db, reader: db, dialect: sqliteDialect, log: log, opts: newOptions(opts)}}, nil
+}
+
+// sqliteErrorCode returns the extended SQLite result code of err, or 0 when it is not a SQLite error
+func sqliteErrorCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+// sqliteDialect writes the statements for SQLite, created_at and expiry hold Unix microseconds
+var sqliteDialect = sqlDialect{
+	placeholder: func(int) string { return "?" },
+	timeValue:   func(t time.Time) any { return t.UnixMicro() },
+	hasLabels: func(labels map[string]string, arg func(value any) string) string {
+		// sorted so the same filter always yields the same statement
+		conditions := make([]string, 0, len(labels))
+		for _, key := range slices.Sorted(maps.Keys(labels)) {
+			conditions = append(conditions, fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM json_each(labels) WHERE json_each.key = %s AND json_each.value = %s)",
+				arg(key), arg(labels[key])))
+		}
+		return strings.Join(conditions, " AND ")
+	},
+	// length() counts characters of TEXT, the cast makes it count bytes
+	blobLength: "length(CAST(blob AS BLOB))",
+	isUniqueViolation: func(err error) bool {
+		return sqliteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	},
+	isForeignKeyViolation: func(err error) bool {
+		return sqliteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+	},
+}
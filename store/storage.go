@@ -2,30 +2,544 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 // Storage errors
 var (
 	ErrBlobNotFound = errors.New("blob not found")
 	ErrBlobExists   = errors.New("blob already exists")
+	// ErrCountersignatureExists is returned when a key has already countersigned a record
+	ErrCountersignatureExists = errors.New("countersignature already exists")
+	// ErrTombstoneNotFound is returned when no expired record with the UUID was deleted
+	ErrTombstoneNotFound = errors.New("tombstone not found")
+	// ErrLegalHold is returned when deleting or expiring a blob under legal hold
+	ErrLegalHold = errors.New("blob is under legal hold")
 )
 
 // Storage defines the interface for blob storage operations
 type Storage interface {
 	// Store saves a new blob to the storage
 	Store(ctx context.Context, record *blobv1.SignedBlobRecord) error
+	// StoreBatch saves new blobs to the storage, either all of them or none
+	StoreBatch(ctx context.Context, records []*blobv1.SignedBlobRecord) error
 	// GetByUUID retrieves a blob by its UUID
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*blobv1.SignedBlobRecord, error)
+	// GetMetadata retrieves the metadata of a blob by its UUID without its content
+	GetMetadata(ctx context.Context, uuid uuid.UUID) (*blobv1.BlobMetadata, error)
+	// ListBlobs retrieves the metadata of up to query.Limit blobs selected by the query,
+	// ordered by creation time then UUID
+	ListBlobs(ctx context.Context, query ListQuery) ([]*blobv1.BlobMetadata, error)
+	// AddCountersignature stores a countersignature for the blob with the given UUID
+	AddCountersignature(ctx context.Context, uuid uuid.UUID, countersignature *blobv1.Countersignature) error
+	// GetCountersignatures retrieves all countersignatures of a blob, oldest first
+	GetCountersignatures(ctx context.Context, uuid uuid.UUID) ([]*blobv1.Countersignature, error)
 	// Exists checks if a blob with the given UUID exists
 	Exists(ctx context.Context, uuid uuid.UUID) (bool, error)
-	// Delete removes a blob by its UUID (optional for future use)
+	// Delete removes a blob by its UUID, blobs under legal hold are refused with ErrLegalHold
 	Delete(ctx context.Context, uuid uuid.UUID) error
-	// Migrate helps migrate database schema using migration files in the directory
+	// ListExpired retrieves the metadata of up to limit blobs that expired at or before now, earliest first.
+	// Blobs under legal hold are left out.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*blobv1.BlobMetadata, error)
+	// Expire removes an expired blob and its countersignatures and stores its tombstone in a single step,
+	// blobs under legal hold are refused with ErrLegalHold
+	Expire(ctx context.Context, uuid uuid.UUID, tombstone *blobv1.SignedTombstone) error
+	// GetTombstone retrieves the tombstone of an expired blob by its UUID
+	GetTombstone(ctx context.Context, uuid uuid.UUID) (*blobv1.SignedTombstone, error)
+	// AcquireContent records an upload writing content with the hash to a content store,
+	// DeleteContent leaves the content in place until the upload calls ReleaseContent.
+	// It waits for a DeleteContent of the hash in progress, in any process sharing the storage.
+	AcquireContent(ctx context.Context, hash string) error
+	// ReleaseContent ends an upload started by AcquireContent, once its record is stored or refused
+	ReleaseContent(ctx context.Context, hash string) error
+	// DeleteContent calls deleteContent unless a stored blob has the hash or an upload holds it,
+	// and reports whether it did. Uploads acquiring the hash meanwhile wait for it to return.
+	DeleteContent(ctx context.Context, hash string, deleteContent func(ctx context.Context) error) (bool, error)
+	// SetLegalHold places or releases the legal hold of a blob and records the event in the audit log
+	SetLegalHold(ctx context.Context, uuid uuid.UUID, event *blobv1.LegalHoldEvent) error
+	// GetLegalHoldEvents retrieves the legal hold audit log of a blob, oldest first,
+	// including the events of blobs deleted since
+	GetLegalHoldEvents(ctx context.Context, uuid uuid.UUID) ([]*blobv1.LegalHoldEvent, error)
+	// Migrate helps migrate database schema using migration files in the directory,
+	// or the migrations embedded in the binary when the directory is ""
 	Migrate(ctx context.Context, directory string) error
 	// Ping checks if the storage is reachable
 	Ping(ctx context.Context) error
 }
+
+// ListQuery selects the blobs listed by ListBlobs
+type ListQuery struct {
+	CreatedFrom time.Time         // blobs created at or after this time, zero for no lower bound
+	CreatedTo   time.Time         // blobs created before this time, zero for no upper bound
+	Labels      map[string]string // blobs carrying all of these labels, nil for any labels
+	After       *ListCursor       // resume after this blob, nil for the first page
+	Limit       int               // maximum number of blobs returned
+}
+
+// ListCursor is the position of a blob in the listing order, by creation time then UUID
+type ListCursor struct {
+	CreatedAt time.Time
+	UUID      string
+}
+
+// matches reports whether the query selects a blob created at the time with the UUID and labels
+func (q *ListQuery) matches(createdAt time.Time, uuid string, labels map[string]string) bool {
+	if !q.CreatedFrom.IsZero() && createdAt.Before(q.CreatedFrom) {
+		return false
+	}
+	if !q.CreatedTo.IsZero() && !createdAt.Before(q.CreatedTo) {
+		return false
+	}
+	if q.After != nil {
+		if c := createdAt.Compare(q.After.CreatedAt); c < 0 || c == 0 && uuid <= q.After.UUID {
+			return false
+		}
+	}
+	for key, value := range q.Labels {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sqlDialect writes the parts of the SQL statements that differ between the database backends
+type sqlDialect struct {
+	placeholder func(n int) string    // placeholder of the n-th query argument
+	timeValue   func(t time.Time) any // value of a time in the created_at and expiry columns
+	// hasLabels returns the condition selecting the blobs carrying all the labels,
+	// arg adds a query argument and returns its placeholder
+	hasLabels  func(labels map[string]string, arg func(value any) string) string
+	blobLength string // expression of the length of the blob column in bytes
+
+	isUniqueViolation     func(err error) bool // the error is a primary key or unique constraint violation
+	isForeignKeyViolation func(err error) bool // the error is a foreign key constraint violation
+}
+
+// rebind replaces the ? placeholders of the query with the placeholders of the dialect
+func (d sqlDialect) rebind(query string) string {
+	var rebound strings.Builder
+	n := 0
+	for _, c := range query {
+		if c != '?' {
+			rebound.WriteRune(c)
+			continue
+		}
+		n++
+		rebound.WriteString(d.placeholder(n))
+	}
+	return rebound.String()
+}
+
+// sizeColumn returns the expression of the content size, computed by the database for uploaded
+// content kept as it is, detached and encoded records carry it in the size column
+func (d sqlDialect) sizeColumn() string {
+	return "CASE WHEN detached OR codec <> '' OR master_key_id <> '' THEN size ELSE " + d.blobLength + " END"
+}
+
+// conditions returns the WHERE clause selecting the blobs of the query and its arguments
+func (q *ListQuery) conditions(dialect sqlDialect) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(value any) string {
+		args = append(args, value)
+		return dialect.placeholder(len(args))
+	}
+
+	if !q.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(dialect.timeValue(q.CreatedFrom)))
+	}
+	if !q.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(dialect.timeValue(q.CreatedTo)))
+	}
+	if len(q.Labels) > 0 {
+		conditions = append(conditions, dialect.hasLabels(q.Labels, arg))
+	}
+	if q.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, uuid) > (%s, %s)",
+			arg(dialect.timeValue(q.After.CreatedAt)), arg(q.After.UUID)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// createdAt parses the signed timestamp of a record, the time it was created at
+func createdAt(timestamp string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid record timestamp %q: %w", timestamp, err)
+	}
+	return t.UTC(), nil
+}
+
+// expiry parses the expiry time of a record, ok is false when it is kept forever
+func expiry(expiresAt string) (t time.Time, ok bool, err error) {
+	if expiresAt == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err = time.Parse(time.RFC3339Nano, expiresAt); err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid record expiry time %q: %w", expiresAt, err)
+	}
+	return t.UTC(), true, nil
+}
+
+// signedBlobColumns are the columns written when a record is stored, in the order of recordRow
+var signedBlobColumns = []string{
+	"uuid", "blob", "hash", "timestamp", "signature", "detached", "filename", "size", "key_id", "expires_at",
+	"codec", "encoded_blob", "master_key_id", "wrapped_key", "created_at", "labels", "content_type",
+	"inclusion_proof", "expiry",
+}
+
+// maxInsertRows is the number of records inserted by one statement, well within the limit on
+// query arguments of both PostgreSQL (65535) and SQLite (32766)
+const maxInsertRows = 500
+
+// insertStatement returns the statement inserting rows records into signed_blobs
+func insertStatement(dialect sqlDialect, rows int) string {
+	var statement strings.Builder
+	statement.WriteString("INSERT INTO signed_blobs (" + strings.Join(signedBlobColumns, ", ") + ") VALUES ")
+	n := 0
+	for row := range rows {
+		if row > 0 {
+			statement.WriteString(", ")
+		}
+		placeholders := make([]string, len(signedBlobColumns))
+		for i := range placeholders {
+			n++
+			placeholders[i] = dialect.placeholder(n)
+		}
+		statement.WriteString("(" + strings.Join(placeholders, ", ") + ")")
+	}
+	return statement.String()
+}
+
+// recordRow returns the values of the signedBlobColumns of a record
+func recordRow(opts options, dialect sqlDialect, record *blobv1.SignedBlobRecord) ([]any, error) {
+	// created_at indexes the signed timestamp for time-range listings
+	created, err := createdAt(record.Payload.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	// encoded content goes to encoded_blob, the size column keeps the original size
+	content, err := encodeContent(opts, record)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := inclusionProofColumn(record.InclusionProof)
+	if err != nil {
+		return nil, err
+	}
+
+	// expiry indexes the expiry time for the reaper, NULL when the record is kept forever
+	expires, expiring, err := expiry(record.Payload.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	var expiryValue any
+	if expiring {
+		expiryValue = dialect.timeValue(expires)
+	}
+
+	return []any{
+		record.Payload.Uuid,
+		content.blob,
+		record.Payload.Hash,
+		record.Payload.Timestamp,
+		record.Signature, // signature is a byte slice
+		record.Payload.Detached,
+		record.Payload.Filename,
+		content.size,
+		record.KeyId,
+		record.Payload.ExpiresAt,
+		content.codec,
+		content.data,
+		content.masterKeyID,
+		content.wrappedKey,
+		dialect.timeValue(created),
+		labelsColumn(record.Payload.Labels),
+		record.Payload.ContentType,
+		proof,
+		expiryValue,
+	}, nil
+}
+
+// insertRecords inserts the records with as few statements as possible, the caller runs it in a transaction
+func insertRecords(ctx context.Context, tx *sql.Tx, opts options, dialect sqlDialect, records []*blobv1.SignedBlobRecord) error {
+	for batch := range slices.Chunk(records, maxInsertRows) {
+		args := make([]any, 0, len(batch)*len(signedBlobColumns))
+		for _, record := range batch {
+			row, err := recordRow(opts, dialect, record)
+			if err != nil {
+				return fmt.Errorf("record %s: %w", record.GetPayload().GetUuid(), err)
+			}
+			args = append(args, row...)
+		}
+		if _, err := tx.ExecContext(ctx, insertStatement(dialect, len(batch)), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelsColumn returns the labels column value of a record, a JSON object
+func labelsColumn(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "{}"
+	}
+	// encoding a map of strings cannot fail, invalid UTF-8 is replaced
+	encoded, _ := json.Marshal(labels)
+	return string(encoded)
+}
+
+// inclusionProofColumn encodes the inclusion proof of a record signed in a Merkle batch,
+// NULL for a record signed on its own
+func inclusionProofColumn(proof *blobv1.InclusionProof) ([]byte, error) {
+	if proof == nil {
+		return nil, nil
+	}
+	encoded, err := proto.Marshal(proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inclusion proof: %w", err)
+	}
+	return encoded, nil
+}
+
+// inclusionProofScanner decodes the inclusion_proof column, left nil for a record signed on its own
+type inclusionProofScanner struct {
+	proof **blobv1.InclusionProof
+}
+
+// Scan implements sql.Scanner for the Protobuf-encoded inclusion proof
+func (i inclusionProofScanner) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		proof := &blobv1.InclusionProof{}
+		if err := proto.Unmarshal(src, proof); err != nil {
+			return fmt.Errorf("invalid inclusion_proof column: %w", err)
+		}
+		*i.proof = proof
+		return nil
+	default:
+		return fmt.Errorf("unexpected inclusion_proof column type %T", src)
+	}
+}
+
+// labelsScanner decodes the labels column into a map, left nil when the record has no labels
+type labelsScanner struct {
+	labels *map[string]string
+}
+
+// Scan implements sql.Scanner for the JSON object of the labels column
+func (l labelsScanner) Scan(src any) error {
+	var encoded []byte
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		encoded = src
+	case string:
+		encoded = []byte(src)
+	default:
+		return fmt.Errorf("unexpected labels column type %T", src)
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal(encoded, &labels); err != nil {
+		return fmt.Errorf("invalid labels column: %w", err)
+	}
+	if len(labels) > 0 {
+		*l.labels = labels
+	}
+	return nil
+}
+
+// Option configures optional features of the database storages
+type Option func(*options)
+
+// options holds the optional features of the database storages
+type options struct {
+	compression Codec      // codec applied to blob content before it is written
+	keyring     *Keyring   // master keys wrapping the data keys of encrypted content, nil disables encryption
+	pool        PoolConfig // PostgreSQL connection pool sizes
+	replicaDSN  string     // PostgreSQL read replica, "" sends all queries to the primary
+}
+
+// newOptions applies the options over the defaults
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// encodedContent is blob content as the database storages write it
+type encodedContent struct {
+	blob        string // content kept as it is, "" when it is encoded
+	size        int64  // the size column, the original content size of encoded records
+	codec       Codec  // compression codec of the encoded content
+	data        []byte // compressed and or encrypted content
+	masterKeyID string // master key wrapping the data key of encrypted content, "" when not encrypted
+	wrappedKey  []byte // data key the content is encrypted with
+}
+
+// encoded reports whether the content is kept in data rather than in blob
+func (e *encodedContent) encoded() bool {
+	return e.codec != CodecNone || e.masterKeyID != ""
+}
+
+// encodeContent compresses and then encrypts the content of a record as configured by the options
+func encodeContent(opts options, record *blobv1.SignedBlobRecord) (*encodedContent, error) {
+	e := &encodedContent{
+		blob: record.Payload.Blob,
+		size: record.Payload.Size,
+	}
+
+	codec, compressed, err := compressContent(opts.compression, record.Payload.Blob)
+	if err != nil {
+		return nil, err
+	}
+	if codec != CodecNone {
+		e.codec, e.data = codec, compressed
+	}
+
+	if opts.keyring != nil && record.Payload.Blob != "" {
+		content := e.data
+		if !e.encoded() {
+			content = []byte(record.Payload.Blob)
+		}
+		if e.masterKeyID, e.wrappedKey, e.data, err = opts.keyring.encryptContent(record.Payload.Uuid, content); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.encoded() {
+		e.blob, e.size = "", int64(len(record.Payload.Blob))
+	}
+
+	return e, nil
+}
+
+// decodeContent reverses encodeContent and checks the content against the hash it was signed with
+func decodeContent(opts options, uuid, hash string, e *encodedContent) (string, error) {
+	content := e.data
+	if e.masterKeyID != "" {
+		if opts.keyring == nil {
+			return "", fmt.Errorf("%w: blob content is encrypted", ErrEncryptionDisabled)
+		}
+		var err error
+		if content, err = opts.keyring.decryptContent(uuid, e.masterKeyID, e.wrappedKey, content); err != nil {
+			return "", err
+		}
+	}
+
+	if e.codec != CodecNone {
+		var err error
+		if content, err = decompressContent(e.codec, content); err != nil {
+			return "", err
+		}
+	}
+
+	if err := checkContentHash(hash, content); err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// sqlExecutor runs statements on a database or within a transaction
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// metadataFromRecord returns the metadata of a record, the size of uploaded content is its length
+func metadataFromRecord(record *blobv1.SignedBlobRecord) *blobv1.BlobMetadata {
+	size := record.Payload.Size
+	if !record.Payload.Detached {
+		size = int64(len(record.Payload.Blob))
+	}
+	return &blobv1.BlobMetadata{
+		Uuid:        record.Payload.Uuid,
+		Hash:        record.Payload.Hash,
+		Timestamp:   record.Payload.Timestamp,
+		Size:        size,
+		KeyId:       record.KeyId,
+		Detached:    record.Payload.Detached,
+		Filename:    record.Payload.Filename,
+		ExpiresAt:   record.Payload.ExpiresAt,
+		Labels:      maps.Clone(record.Payload.Labels),
+		ContentType: record.Payload.ContentType,
+	}
+}
+
+// Storage backends, selected by the scheme of the data source name
+const (
+	BackendPostgres = "postgres" // postgres:// or postgresql://
+	BackendSQLite   = "sqlite"   // sqlite://<path>
+	BackendMemory   = "memory"   // memory://, nothing survives a restart
+)
+
+// BackendForDSN returns the storage backend the data source name selects.
+func BackendForDSN(dsn string) (string, error) {
+	scheme, _, ok := strings.Cut(dsn, "://")
+	if !ok {
+		return "", errors.New("data source name must start with a scheme such as postgres://, sqlite:// or memory://")
+	}
+
+	switch strings.ToLower(scheme) {
+	case "postgres", "postgresql":
+		return BackendPostgres, nil
+	case "sqlite":
+		return BackendSQLite, nil
+	case "memory":
+		return BackendMemory, nil
+	default:
+		return "", fmt.Errorf("unsupported data source name scheme %q", scheme)
+	}
+}
+
+// NewStorage creates the storage backend selected by the scheme of the data source name.
+// retryInterval and maxReadyDuration only apply to database servers, see NewPostgresStorage.
+func NewStorage(
+	dsn string, // Data Source Name, postgres://, sqlite:// or memory://
+	log *slog.Logger, // Logger for logging
+	retryInterval time.Duration, // retryInterval for pinging the database
+	maxReadyDuration time.Duration, // Maximum duration to wait for the database to be ready
+	opts ...Option, // optional features of the database storages, ignored by the in-memory storage
+) (Storage, error) {
+	backend, err := BackendForDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend {
+	case BackendSQLite:
+		return NewSQLiteStorage(dsn, log, opts...)
+	case BackendMemory:
+		return NewMemoryStorage(), nil
+	default:
+		return NewPostgresStorage(dsn, log, retryInterval, maxReadyDuration, opts...)
+	}
+}
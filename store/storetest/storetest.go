@@ -0,0 +1,884 @@
+// Package storetest provides a conformance test suite for store.Storage implementations.
+// Every backend must pass it, so the service behaves the same whichever storage it runs on:
+//
+//	func TestConformance(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) (store.Storage, string) {
+//			return newEmptyStorage(t), "" // the migrations embedded in the binary
+//		})
+//	}
+package storetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/store"
+	"google.golang.org/protobuf/proto"
+)
+
+// Factory creates a new storage with an empty database and returns it with the directory of its
+// migrations. The suite runs the migrations itself. Storages without a schema, or using the
+// migrations embedded in the binary, return "".
+// Each call must return an independent storage, the tests run in parallel.
+type Factory func(t *testing.T) (storage store.Storage, migrationDir string)
+
+// concurrency is the number of goroutines used by the concurrency tests
+const concurrency = 20
+
+// timestampFormat is the format of the times the service signs, RFC 3339 in UTC with microseconds
+const timestampFormat = "2006-01-02T15:04:05.000000Z"
+
+// Run runs the conformance suite against the storages created by the factory
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, s store.Storage)
+	}{
+		{name: "StoreAndGetByUUID", test: testStoreAndGetByUUID},
+		{name: "StoreDetached", test: testStoreDetached},
+		{name: "StoreCompressible", test: testStoreCompressible},
+		{name: "StoreDuplicate", test: testStoreDuplicate},
+		{name: "StoreBatch", test: testStoreBatch},
+		{name: "GetByUUIDNotFound", test: testGetByUUIDNotFound},
+		{name: "ReturnedRecordIsACopy", test: testReturnedRecordIsACopy},
+		{name: "GetMetadata", test: testGetMetadata},
+		{name: "Exists", test: testExists},
+		{name: "Delete", test: testDelete},
+		{name: "Countersignatures", test: testCountersignatures},
+		{name: "ListBlobs", test: testListBlobs},
+		{name: "Labels", test: testLabels},
+		{name: "ListExpired", test: testListExpired},
+		{name: "ListExpiredMixedFormats", test: testListExpiredMixedFormats},
+		{name: "Expire", test: testExpire},
+		{name: "ContentReferences", test: testContentReferences},
+		{name: "DeleteContentBlocksAcquire", test: testDeleteContentBlocksAcquire},
+		{name: "LegalHold", test: testLegalHold},
+		{name: "ConcurrentStoreAndGet", test: testConcurrentStoreAndGet},
+		{name: "Ping", test: testPing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.test(t, newMigratedStorage(t, factory))
+		})
+	}
+
+	t.Run("MigrateIsIdempotent", func(t *testing.T) {
+		t.Parallel()
+		s, migrationDir := factory(t)
+		for i := range 2 {
+			if err := s.Migrate(context.Background(), migrationDir); err != nil {
+				t.Fatalf("migration %d failed: %v", i+1, err)
+			}
+		}
+	})
+}
+
+// newMigratedStorage creates a storage with the factory and migrates it
+func newMigratedStorage(t *testing.T, factory Factory) store.Storage {
+	t.Helper()
+	s, migrationDir := factory(t)
+	if err := s.Migrate(context.Background(), migrationDir); err != nil {
+		t.Fatalf("failed to migrate storage: %v", err)
+	}
+	return s
+}
+
+// newRecord returns a signed record with a fresh UUID and a hash matching its content
+func newRecord(blob string) *blobv1.SignedBlobRecord {
+	digest := sha256.Sum256([]byte(blob))
+	return &blobv1.SignedBlobRecord{
+		Payload: &blobv1.BlobRecord{
+			Uuid:      uuid.NewString(),
+			Blob:      blob,
+			Hash:      hex.EncodeToString(digest[:]),
+			Timestamp: time.Now().UTC().Format(timestampFormat),
+		},
+		Signature: []byte("signature of " + blob),
+		KeyId:     "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+}
+
+// mustStore stores the record and returns its UUID
+func mustStore(t *testing.T, s store.Storage, record *blobv1.SignedBlobRecord) uuid.UUID {
+	t.Helper()
+	if err := s.Store(context.Background(), record); err != nil {
+		t.Fatalf("failed to store record: %v", err)
+	}
+	return uuid.MustParse(record.Payload.Uuid)
+}
+
+func testStoreAndGetByUUID(t *testing.T, s store.Storage) {
+	named := newRecord("{\"hello\": \"world\"}")
+	named.Payload.Filename = "hello.json"
+	named.Payload.ContentType = "application/json"
+	batched := newRecord("signed in a Merkle batch")
+	batched.InclusionProof = &blobv1.InclusionProof{
+		LeafIndex: 2, TreeSize: 5, Hashes: [][]byte{bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)},
+	}
+
+	for _, record := range []*blobv1.SignedBlobRecord{newRecord("hello world"), named, batched} {
+		id := mustStore(t, s, record)
+
+		got, err := s.GetByUUID(context.Background(), id)
+		if err != nil {
+			t.Fatalf("failed to retrieve record: %v", err)
+		}
+		if !proto.Equal(record, got) {
+			t.Fatalf("retrieved record does not match the stored one:\n got: %v\nwant: %v", got, record)
+		}
+	}
+}
+
+func testStoreDetached(t *testing.T, s store.Storage) {
+	record := newRecord("")
+	record.Payload.Detached = true
+	record.Payload.Filename = "release.tar.gz"
+	record.Payload.Size = 5 << 30 // larger than 32 bits
+	id := mustStore(t, s, record)
+
+	got, err := s.GetByUUID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if !proto.Equal(record, got) {
+		t.Fatalf("retrieved record does not match the stored one:\n got: %v\nwant: %v", got, record)
+	}
+}
+
+func testStoreCompressible(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	// large, repetitive content that storages with compression enabled store compressed
+	var content strings.Builder
+	for i := range 2000 {
+		fmt.Fprintf(&content, "{\"id\": %d, \"name\": \"item\", \"tags\": [\"a\", \"b\"]}\n", i)
+	}
+	record := newRecord(content.String())
+	id := mustStore(t, s, record)
+
+	got, err := s.GetByUUID(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if !proto.Equal(record, got) {
+		t.Fatal("retrieved record does not match the stored one")
+	}
+
+	metadata, err := s.GetMetadata(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve metadata: %v", err)
+	}
+	if metadata.Size != int64(content.Len()) {
+		t.Fatalf("expected the uncompressed size %d but got %d", content.Len(), metadata.Size)
+	}
+}
+
+func testStoreDuplicate(t *testing.T, s store.Storage) {
+	record := newRecord("stored twice")
+	mustStore(t, s, record)
+
+	if err := s.Store(context.Background(), record); !errors.Is(err, store.ErrBlobExists) {
+		t.Fatalf("expected ErrBlobExists but got %v", err)
+	}
+}
+
+func testStoreBatch(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	if err := s.StoreBatch(ctx, nil); err != nil {
+		t.Fatalf("failed to store an empty batch: %v", err)
+	}
+
+	// large enough for the database storages to split it into several statements
+	records := make([]*blobv1.SignedBlobRecord, 0, 1200)
+	for i := range cap(records) {
+		record := newRecord(fmt.Sprintf("batched blob %d", i))
+		if i%3 == 0 {
+			record.Payload.Labels = map[string]string{"batch": "1"}
+		}
+		if i%5 == 0 {
+			record.Payload = &blobv1.BlobRecord{
+				Uuid: record.Payload.Uuid, Hash: record.Payload.Hash, Timestamp: record.Payload.Timestamp,
+				Detached: true, Filename: "batched.bin", Size: 42,
+			}
+		}
+		records = append(records, record)
+	}
+	if err := s.StoreBatch(ctx, records); err != nil {
+		t.Fatalf("failed to store batch: %v", err)
+	}
+	for _, record := range records {
+		got, err := s.GetByUUID(ctx, uuid.MustParse(record.Payload.Uuid))
+		if err != nil {
+			t.Fatalf("failed to retrieve record: %v", err)
+		}
+		if !proto.Equal(record, got) {
+			t.Fatalf("retrieved record does not match the stored one:\n got: %v\nwant: %v", got, record)
+		}
+	}
+
+	// a batch with a record already stored is refused as a whole
+	fresh := newRecord("not stored")
+	if err := s.StoreBatch(ctx, []*blobv1.SignedBlobRecord{fresh, records[0]}); !errors.Is(err, store.ErrBlobExists) {
+		t.Fatalf("expected ErrBlobExists but got %v", err)
+	}
+	if exists, err := s.Exists(ctx, uuid.MustParse(fresh.Payload.Uuid)); err != nil || exists {
+		t.Fatalf("expected no record of the refused batch to be stored: %v %v", exists, err)
+	}
+}
+
+func testGetByUUIDNotFound(t *testing.T, s store.Storage) {
+	if _, err := s.GetByUUID(context.Background(), uuid.New()); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+}
+
+func testReturnedRecordIsACopy(t *testing.T, s store.Storage) {
+	record := newRecord("original")
+	id := mustStore(t, s, record)
+
+	// changes to the stored or the returned record must not leak into the storage
+	record.Payload.Blob = "changed after store"
+	got, err := s.GetByUUID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	got.Payload.Blob = "changed after get"
+
+	again, err := s.GetByUUID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if again.Payload.Blob != "original" {
+		t.Fatalf("stored record was modified through a caller's copy: %q", again.Payload.Blob)
+	}
+}
+
+func testGetMetadata(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	if _, err := s.GetMetadata(ctx, uuid.New()); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+
+	// the size is in bytes, not characters
+	uploaded := newRecord("héllo wörld")
+	uploaded.Payload.Filename = "hello.txt"
+	uploaded.Payload.ContentType = "text/plain; charset=utf-8"
+	detached := newRecord("")
+	detached.Payload.Detached = true
+	detached.Payload.Filename = "release.tar.gz"
+	detached.Payload.Size = 5 << 30
+
+	for _, record := range []*blobv1.SignedBlobRecord{uploaded, detached} {
+		metadata, err := s.GetMetadata(ctx, mustStore(t, s, record))
+		if err != nil {
+			t.Fatalf("failed to retrieve metadata: %v", err)
+		}
+
+		size := int64(len(record.Payload.Blob))
+		if record.Payload.Detached {
+			size = record.Payload.Size
+		}
+		want := &blobv1.BlobMetadata{
+			Uuid:        record.Payload.Uuid,
+			Hash:        record.Payload.Hash,
+			Timestamp:   record.Payload.Timestamp,
+			Size:        size,
+			KeyId:       record.KeyId,
+			Detached:    record.Payload.Detached,
+			Filename:    record.Payload.Filename,
+			ContentType: record.Payload.ContentType,
+		}
+		if !proto.Equal(want, metadata) {
+			t.Fatalf("unexpected metadata:\n got: %v\nwant: %v", metadata, want)
+		}
+	}
+}
+
+func testExists(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	exists, err := s.Exists(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Fatal("Exists returned true for a record that was never stored")
+	}
+
+	id := mustStore(t, s, newRecord("exists"))
+	if exists, err = s.Exists(ctx, id); err != nil || !exists {
+		t.Fatalf("expected stored record to exist: %v", err)
+	}
+
+	if err := s.Delete(ctx, id); err != nil {
+		t.Fatalf("failed to delete record: %v", err)
+	}
+	if exists, err = s.Exists(ctx, id); err != nil || exists {
+		t.Fatalf("expected deleted record not to exist: %v", err)
+	}
+}
+
+func testDelete(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	if err := s.Delete(ctx, uuid.New()); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound for an unknown record but got %v", err)
+	}
+
+	id := mustStore(t, s, newRecord("deleted"))
+	other := mustStore(t, s, newRecord("kept"))
+
+	if err := s.Delete(ctx, id); err != nil {
+		t.Fatalf("failed to delete record: %v", err)
+	}
+	if _, err := s.GetByUUID(ctx, id); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound after delete but got %v", err)
+	}
+	if err := s.Delete(ctx, id); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound for a second delete but got %v", err)
+	}
+	if _, err := s.GetByUUID(ctx, other); err != nil {
+		t.Fatalf("deleting one record affected another: %v", err)
+	}
+}
+
+func testCountersignatures(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	id := mustStore(t, s, newRecord("countersigned"))
+
+	countersignatures, err := s.GetCountersignatures(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve countersignatures: %v", err)
+	}
+	if len(countersignatures) != 0 {
+		t.Fatalf("expected no countersignatures but got %d", len(countersignatures))
+	}
+
+	first := &blobv1.Countersignature{KeyId: "aa", Signature: []byte{0x01}, Timestamp: "2025-07-28T17:42:05Z"}
+	second := &blobv1.Countersignature{KeyId: "bb", Signature: []byte{0x02}, Timestamp: "2025-07-28T17:43:05Z"}
+	for _, cs := range []*blobv1.Countersignature{first, second} {
+		if err := s.AddCountersignature(ctx, id, cs); err != nil {
+			t.Fatalf("failed to add countersignature: %v", err)
+		}
+	}
+
+	if err := s.AddCountersignature(ctx, id, first); !errors.Is(err, store.ErrCountersignatureExists) {
+		t.Fatalf("expected ErrCountersignatureExists but got %v", err)
+	}
+	if err := s.AddCountersignature(ctx, uuid.New(), first); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound but got %v", err)
+	}
+
+	countersignatures, err = s.GetCountersignatures(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve countersignatures: %v", err)
+	}
+	if len(countersignatures) != 2 ||
+		!proto.Equal(first, countersignatures[0]) || !proto.Equal(second, countersignatures[1]) {
+		t.Fatalf("unexpected countersignatures, oldest first expected: %v", countersignatures)
+	}
+
+	// countersignatures are removed along with their record
+	if err := s.Delete(ctx, id); err != nil {
+		t.Fatalf("failed to delete record: %v", err)
+	}
+	if countersignatures, err = s.GetCountersignatures(ctx, id); err != nil || len(countersignatures) != 0 {
+		t.Fatalf("expected no countersignatures after delete: %v %v", countersignatures, err)
+	}
+}
+
+func testListBlobs(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	base := time.Date(2025, 7, 28, 17, 42, 5, 0, time.UTC)
+	timestamp := func(d time.Duration) string {
+		return base.Add(d).Format(timestampFormat)
+	}
+
+	// stored out of order, two of them signed in the same microsecond
+	records := map[string]*blobv1.SignedBlobRecord{}
+	for name, d := range map[string]time.Duration{
+		"first": 0, "second": time.Microsecond, "tie": time.Microsecond, "third": time.Second, "later": time.Hour,
+	} {
+		record := newRecord(name)
+		record.Payload.Timestamp = timestamp(d)
+		records[name] = record
+		mustStore(t, s, record)
+	}
+	second, tie := records["second"].Payload.Uuid, records["tie"].Payload.Uuid
+	if tie < second {
+		second, tie = tie, second
+	}
+	want := []string{records["first"].Payload.Uuid, second, tie, records["third"].Payload.Uuid, records["later"].Payload.Uuid}
+
+	uuids := func(blobs []*blobv1.BlobMetadata) []string {
+		ids := make([]string, 0, len(blobs))
+		for _, blob := range blobs {
+			ids = append(ids, blob.Uuid)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		query store.ListQuery
+		want  []string
+	}{
+		{name: "all", query: store.ListQuery{Limit: 10}, want: want},
+		{name: "limit", query: store.ListQuery{Limit: 2}, want: want[:2]},
+		{
+			name:  "from is inclusive",
+			query: store.ListQuery{CreatedFrom: base.Add(time.Microsecond), Limit: 10},
+			want:  want[1:],
+		},
+		{
+			name:  "to is exclusive",
+			query: store.ListQuery{CreatedTo: base.Add(time.Second), Limit: 10},
+			want:  want[:3],
+		},
+		{
+			name:  "range",
+			query: store.ListQuery{CreatedFrom: base.Add(time.Microsecond), CreatedTo: base.Add(time.Minute), Limit: 10},
+			want:  want[1:4],
+		},
+		{name: "empty range", query: store.ListQuery{CreatedFrom: base.Add(2 * time.Hour), Limit: 10}},
+	}
+
+	for _, tt := range tests {
+		got, err := s.ListBlobs(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("%s: failed to list blobs: %v", tt.name, err)
+		}
+		if ids := uuids(got); !slices.Equal(ids, tt.want) {
+			t.Fatalf("%s: expected %v but got %v", tt.name, tt.want, ids)
+		}
+	}
+
+	// paging resumes after the last blob of the previous page, also between blobs signed in the same microsecond
+	var paged []string
+	query := store.ListQuery{Limit: 2}
+	for {
+		page, err := s.ListBlobs(ctx, query)
+		if err != nil {
+			t.Fatalf("failed to list blobs: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, uuids(page)...)
+		last := page[len(page)-1]
+		createdAt, err := time.Parse(time.RFC3339Nano, last.Timestamp)
+		if err != nil {
+			t.Fatalf("failed to parse timestamp: %v", err)
+		}
+		query.After = &store.ListCursor{CreatedAt: createdAt, UUID: last.Uuid}
+	}
+	if !slices.Equal(paged, want) {
+		t.Fatalf("expected pages %v but got %v", want, paged)
+	}
+
+	got, err := s.ListBlobs(ctx, store.ListQuery{CreatedFrom: base.Add(time.Second), Limit: 1})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("expected one blob: %v %v", got, err)
+	}
+	third := records["third"].Payload
+	if got[0].Hash != third.Hash || got[0].Timestamp != third.Timestamp || got[0].Size != int64(len(third.Blob)) {
+		t.Fatalf("unexpected metadata of listed blob: %v", got[0])
+	}
+}
+
+func testLabels(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	// keys needing escaping in a JSON path or a query are stored as they are
+	build := newRecord("build output")
+	build.Payload.Labels = map[string]string{"git.commit": "0a1b2c3", "pipeline/id": "42", "owner": "team-a"}
+	release := newRecord("release notes")
+	release.Payload.Labels = map[string]string{"owner": "team-a", "content-type": "text/markdown"}
+	unlabelled := newRecord("no labels")
+	for _, record := range []*blobv1.SignedBlobRecord{build, release, unlabelled} {
+		mustStore(t, s, record)
+	}
+
+	// the labels are part of the signed payload and must come back unchanged
+	got, err := s.GetByUUID(ctx, uuid.MustParse(build.Payload.Uuid))
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if !proto.Equal(build, got) {
+		t.Fatalf("retrieved record does not match the stored one:\n got: %v\nwant: %v", got, build)
+	}
+	metadata, err := s.GetMetadata(ctx, uuid.MustParse(release.Payload.Uuid))
+	if err != nil {
+		t.Fatalf("failed to retrieve metadata: %v", err)
+	}
+	if !maps.Equal(metadata.Labels, release.Payload.Labels) {
+		t.Fatalf("expected labels %v but got %v", release.Payload.Labels, metadata.Labels)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []*blobv1.SignedBlobRecord
+	}{
+		{name: "no filter", want: []*blobv1.SignedBlobRecord{build, release, unlabelled}},
+		{name: "shared label", labels: map[string]string{"owner": "team-a"}, want: []*blobv1.SignedBlobRecord{build, release}},
+		{
+			name:   "all labels must match",
+			labels: map[string]string{"owner": "team-a", "pipeline/id": "42"},
+			want:   []*blobv1.SignedBlobRecord{build},
+		},
+		{name: "different value", labels: map[string]string{"owner": "team-b"}},
+		{name: "missing key", labels: map[string]string{"git.commit": "0a1b2c3", "content-type": "text/markdown"}},
+	}
+
+	for _, tt := range tests {
+		listed, err := s.ListBlobs(ctx, store.ListQuery{Labels: tt.labels, Limit: 10})
+		if err != nil {
+			t.Fatalf("%s: failed to list blobs: %v", tt.name, err)
+		}
+		if len(listed) != len(tt.want) {
+			t.Fatalf("%s: expected %d blobs but got %v", tt.name, len(tt.want), listed)
+		}
+		want := map[string]*blobv1.SignedBlobRecord{}
+		for _, record := range tt.want {
+			want[record.Payload.Uuid] = record
+		}
+		for _, metadata := range listed {
+			record, ok := want[metadata.Uuid]
+			if !ok {
+				t.Fatalf("%s: unexpected blob %s", tt.name, metadata.Uuid)
+			}
+			if !maps.Equal(metadata.Labels, record.Payload.Labels) {
+				t.Fatalf("%s: expected labels %v but got %v", tt.name, record.Payload.Labels, metadata.Labels)
+			}
+		}
+	}
+}
+
+func testListExpired(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	expiresAt := func(d time.Duration) string {
+		return now.Add(d).Format(timestampFormat)
+	}
+
+	// stored out of order, listed earliest first
+	later := newRecord("expired an hour ago")
+	later.Payload.ExpiresAt = expiresAt(-time.Hour)
+	earlier := newRecord("expired a day ago")
+	earlier.Payload.ExpiresAt = expiresAt(-24 * time.Hour)
+	future := newRecord("expires tomorrow")
+	future.Payload.ExpiresAt = expiresAt(24 * time.Hour)
+	forever := newRecord("kept forever")
+	for _, record := range []*blobv1.SignedBlobRecord{later, future, earlier, forever} {
+		mustStore(t, s, record)
+	}
+
+	got, err := s.GetByUUID(ctx, uuid.MustParse(later.Payload.Uuid))
+	if err != nil {
+		t.Fatalf("failed to retrieve record: %v", err)
+	}
+	if !proto.Equal(later, got) {
+		t.Fatalf("retrieved record does not match the stored one:\n got: %v\nwant: %v", got, later)
+	}
+
+	expired, err := s.ListExpired(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("failed to list expired records: %v", err)
+	}
+	if len(expired) != 2 ||
+		expired[0].Uuid != earlier.Payload.Uuid || expired[1].Uuid != later.Payload.Uuid {
+		t.Fatalf("expected the two expired records, earliest first, but got %v", expired)
+	}
+	if expired[0].ExpiresAt != earlier.Payload.ExpiresAt || expired[0].Hash != earlier.Payload.Hash {
+		t.Fatalf("unexpected metadata of expired record: %v", expired[0])
+	}
+
+	// the limit caps the batch
+	if expired, err = s.ListExpired(ctx, now, 1); err != nil || len(expired) != 1 ||
+		expired[0].Uuid != earlier.Payload.Uuid {
+		t.Fatalf("expected only the earliest expired record: %v %v", expired, err)
+	}
+}
+
+func testListExpiredMixedFormats(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	expired := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	// older releases wrote expiry times to the second, "…05Z" sorts after "…05.500000Z" as a string
+	legacy := newRecord("expiry written to the second")
+	legacy.Payload.ExpiresAt = expired.Format(time.RFC3339)
+	precise := newRecord("expiry written to the microsecond")
+	precise.Payload.ExpiresAt = expired.Add(500 * time.Millisecond).Format(timestampFormat)
+	pending := newRecord("expires half a second after the cutoff")
+	pending.Payload.ExpiresAt = expired.Add(time.Second + 500*time.Millisecond).Format(timestampFormat)
+	for _, record := range []*blobv1.SignedBlobRecord{precise, pending, legacy} {
+		mustStore(t, s, record)
+	}
+
+	listed, err := s.ListExpired(ctx, expired.Add(time.Second), 10)
+	if err != nil {
+		t.Fatalf("failed to list expired records: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Uuid != legacy.Payload.Uuid || listed[1].Uuid != precise.Payload.Uuid {
+		t.Fatalf("expected the two expired records by expiry time but got %v", listed)
+	}
+
+	// a cutoff within the second of a legacy expiry time
+	if listed, err = s.ListExpired(ctx, expired.Add(250*time.Millisecond), 10); err != nil ||
+		len(listed) != 1 || listed[0].Uuid != legacy.Payload.Uuid {
+		t.Fatalf("expected only the record expired to the second: %v %v", listed, err)
+	}
+}
+
+func testExpire(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	record := newRecord("expired")
+	record.Payload.ExpiresAt = "2025-07-28T17:42:05Z"
+	id := mustStore(t, s, record)
+	countersignature := &blobv1.Countersignature{KeyId: "aa", Signature: []byte{0x01}, Timestamp: "2025-07-28T17:42:05Z"}
+	if err := s.AddCountersignature(ctx, id, countersignature); err != nil {
+		t.Fatalf("failed to add countersignature: %v", err)
+	}
+
+	if _, err := s.GetTombstone(ctx, id); !errors.Is(err, store.ErrTombstoneNotFound) {
+		t.Fatalf("expected ErrTombstoneNotFound before expiry but got %v", err)
+	}
+
+	tombstone := &blobv1.SignedTombstone{
+		Payload: &blobv1.Tombstone{
+			Uuid:      record.Payload.Uuid,
+			Hash:      record.Payload.Hash,
+			Timestamp: record.Payload.Timestamp,
+			ExpiresAt: record.Payload.ExpiresAt,
+			DeletedAt: "2025-07-28T17:43:05Z",
+		},
+		Signature: []byte("signature of tombstone"),
+		KeyId:     record.KeyId,
+	}
+	if err := s.Expire(ctx, id, tombstone); err != nil {
+		t.Fatalf("failed to expire record: %v", err)
+	}
+
+	// the record and its countersignatures are gone, the tombstone remains
+	if _, err := s.GetByUUID(ctx, id); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound after expiry but got %v", err)
+	}
+	if countersignatures, err := s.GetCountersignatures(ctx, id); err != nil || len(countersignatures) != 0 {
+		t.Fatalf("expected no countersignatures after expiry: %v %v", countersignatures, err)
+	}
+	got, err := s.GetTombstone(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve tombstone: %v", err)
+	}
+	if !proto.Equal(tombstone, got) {
+		t.Fatalf("retrieved tombstone does not match the stored one:\n got: %v\nwant: %v", got, tombstone)
+	}
+
+	if err := s.Expire(ctx, id, tombstone); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound for a second expiry but got %v", err)
+	}
+}
+
+func testContentReferences(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	record := newRecord("referenced content")
+	hash := record.Payload.Hash
+
+	deleteContent := func(expected bool) {
+		t.Helper()
+		called := false
+		deleted, err := s.DeleteContent(ctx, hash, func(context.Context) error {
+			called = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to delete content: %v", err)
+		}
+		if deleted != expected || called != expected {
+			t.Fatalf("expected content deleted %t but got %t, called %t", expected, deleted, called)
+		}
+	}
+
+	deleteContent(true) // nothing references the hash
+
+	if err := s.AcquireContent(ctx, hash); err != nil {
+		t.Fatalf("failed to acquire content: %v", err)
+	}
+	deleteContent(false) // an upload holds the hash
+	id := mustStore(t, s, record)
+	if err := s.ReleaseContent(ctx, hash); err != nil {
+		t.Fatalf("failed to release content: %v", err)
+	}
+	deleteContent(false) // the stored record references the hash
+
+	if err := s.Delete(ctx, id); err != nil {
+		t.Fatalf("failed to delete record: %v", err)
+	}
+	if _, err := s.DeleteContent(ctx, hash, func(context.Context) error {
+		return errors.New("content store unavailable")
+	}); err == nil {
+		t.Fatal("expected the error of the content store but got none")
+	}
+	deleteContent(true)
+}
+
+func testDeleteContentBlocksAcquire(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	hash := newRecord("content being deleted").Payload.Hash
+
+	acquired := make(chan error, 1)
+	if _, err := s.DeleteContent(ctx, hash, func(context.Context) error {
+		go func() {
+			acquired <- s.AcquireContent(ctx, hash)
+		}()
+		// the upload must not go ahead while the content is being deleted
+		select {
+		case err := <-acquired:
+			return fmt.Errorf("content acquired while being deleted: %v", err)
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		}
+	}); err != nil {
+		t.Fatalf("failed to delete content: %v", err)
+	}
+
+	if err := <-acquired; err != nil {
+		t.Fatalf("failed to acquire content after its deletion: %v", err)
+	}
+	// the upload now holds the hash
+	if deleted, err := s.DeleteContent(ctx, hash, func(context.Context) error { return nil }); err != nil || deleted {
+		t.Fatalf("expected content held by an upload to be kept, deleted %t: %v", deleted, err)
+	}
+}
+
+func testLegalHold(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+
+	record := newRecord("held")
+	record.Payload.ExpiresAt = "2025-07-28T17:42:05Z"
+	id := mustStore(t, s, record)
+
+	hold := &blobv1.LegalHoldEvent{
+		Uuid: record.Payload.Uuid, Held: true, Reason: "case 42", RequestedBy: "legal", Timestamp: "2025-07-28T17:43:05Z",
+	}
+	release := &blobv1.LegalHoldEvent{
+		Uuid: record.Payload.Uuid, Held: false, Reason: "case 42 closed", RequestedBy: "legal", Timestamp: "2025-07-28T17:44:05Z",
+	}
+
+	if err := s.SetLegalHold(ctx, uuid.New(), hold); !errors.Is(err, store.ErrBlobNotFound) {
+		t.Fatalf("expected ErrBlobNotFound for an unknown record but got %v", err)
+	}
+	if err := s.SetLegalHold(ctx, id, hold); err != nil {
+		t.Fatalf("failed to place legal hold: %v", err)
+	}
+
+	metadata, err := s.GetMetadata(ctx, id)
+	if err != nil || !metadata.LegalHold {
+		t.Fatalf("expected metadata to report the legal hold: %v %v", metadata, err)
+	}
+
+	// a held record is neither deleted nor expired
+	if err := s.Delete(ctx, id); !errors.Is(err, store.ErrLegalHold) {
+		t.Fatalf("expected ErrLegalHold on delete but got %v", err)
+	}
+	if expired, err := s.ListExpired(ctx, time.Now(), 10); err != nil || len(expired) != 0 {
+		t.Fatalf("expected held record not to be listed as expired: %v %v", expired, err)
+	}
+	tombstone := &blobv1.SignedTombstone{
+		Payload:   &blobv1.Tombstone{Uuid: record.Payload.Uuid, Hash: record.Payload.Hash},
+		Signature: []byte("signature of tombstone"),
+	}
+	if err := s.Expire(ctx, id, tombstone); !errors.Is(err, store.ErrLegalHold) {
+		t.Fatalf("expected ErrLegalHold on expire but got %v", err)
+	}
+	if _, err := s.GetByUUID(ctx, id); err != nil {
+		t.Fatalf("held record was removed: %v", err)
+	}
+
+	if err := s.SetLegalHold(ctx, id, release); err != nil {
+		t.Fatalf("failed to release legal hold: %v", err)
+	}
+	if expired, err := s.ListExpired(ctx, time.Now(), 10); err != nil || len(expired) != 1 {
+		t.Fatalf("expected released record to be listed as expired: %v %v", expired, err)
+	}
+	if err := s.Delete(ctx, id); err != nil {
+		t.Fatalf("failed to delete released record: %v", err)
+	}
+
+	// the audit log outlives the record
+	events, err := s.GetLegalHoldEvents(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to retrieve legal hold events: %v", err)
+	}
+	if len(events) != 2 || !proto.Equal(hold, events[0]) || !proto.Equal(release, events[1]) {
+		t.Fatalf("unexpected legal hold events, oldest first expected: %v", events)
+	}
+}
+
+func testConcurrentStoreAndGet(t *testing.T, s store.Storage) {
+	ctx := context.Background()
+	records := make([]*blobv1.SignedBlobRecord, concurrency)
+	for i := range records {
+		records[i] = newRecord(fmt.Sprintf("concurrent blob %d", i))
+	}
+
+	// every record is stored and read back from its own goroutine
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for _, record := range records {
+		wg.Add(1)
+		go func(record *blobv1.SignedBlobRecord) {
+			defer wg.Done()
+			if err := s.Store(ctx, record); err != nil {
+				errs <- fmt.Errorf("store: %w", err)
+				return
+			}
+			got, err := s.GetByUUID(ctx, uuid.MustParse(record.Payload.Uuid))
+			if err != nil {
+				errs <- fmt.Errorf("get: %w", err)
+				return
+			}
+			if !proto.Equal(record, got) {
+				errs <- fmt.Errorf("record %s does not match", record.Payload.Uuid)
+			}
+		}(record)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, record := range records {
+		if exists, err := s.Exists(ctx, uuid.MustParse(record.Payload.Uuid)); err != nil || !exists {
+			t.Fatalf("expected record %s to exist: %v", record.Payload.Uuid, err)
+		}
+	}
+}
+
+func testPing(t *testing.T, s store.Storage) {
+	if err := s.Ping(context.Background()); err != nil {
+		t.Fatalf("ping failed: %v", err)
+	}
+}
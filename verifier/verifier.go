@@ -0,0 +1,203 @@
+// Package verifier verifies signed blob records with only the public keys of their signers,
+// the way the client verify command does, so other programs can check records without the server.
+package verifier
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/merkle"
+	"github.com/prit342/signed-blob-service/signature"
+	"google.golang.org/protobuf/proto"
+)
+
+// HashMismatchError is returned when the content does not match the hash in the record
+type HashMismatchError struct {
+	Expected string // hex-encoded SHA-256 hash in the record
+	Computed string // hex-encoded SHA-256 hash of the content
+}
+
+func (e *HashMismatchError) Error() string {
+	return fmt.Sprintf("hash mismatch: expected %s, computed %s", e.Expected, e.Computed)
+}
+
+// SizeMismatchError is returned when the content of a detached record is not of the size in the record
+type SizeMismatchError struct {
+	Expected int64 // size in bytes in the record
+	Actual   int64 // size in bytes of the content
+}
+
+func (e *SizeMismatchError) Error() string {
+	return fmt.Sprintf("size mismatch: expected %d, actual %d", e.Expected, e.Actual)
+}
+
+// SignatureError is returned when the signature is not valid under any of the keys tried
+type SignatureError struct {
+	KeyIDs []string // identifiers of the keys tried
+	Err    error    // error of the last key tried
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("signature verification failed under %d keys: %v", len(e.KeyIDs), e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownKeyError is returned when none of the keys has the identifier the signature was made with,
+// or when no key is given at all
+type UnknownKeyError struct {
+	KeyID string // identifier of the signing key, empty when none was given
+}
+
+func (e *UnknownKeyError) Error() string {
+	if e.KeyID == "" {
+		return "no public key to verify the signature with"
+	}
+	return fmt.Sprintf("unknown signing key %s", e.KeyID)
+}
+
+// Result describes a valid signature
+type Result struct {
+	KeyID     string              // identifier of the key that made the signature
+	PublicKey crypto.PublicKey    // the key that made the signature
+	Algorithm signature.Algorithm // algorithm of the signature
+	// TreeHead the signature covers for records signed in a Merkle batch, nil otherwise
+	TreeHead *blobv1.MerkleTreeHead
+}
+
+// Option customises a verification
+type Option func(*options)
+
+type options struct {
+	content    []byte
+	hasContent bool
+	proof      *blobv1.InclusionProof
+	keyID      string
+}
+
+// WithContent verifies the record against the content instead of the blob it carries,
+// which is how detached records are checked against the original file
+func WithContent(content []byte) Option {
+	return func(o *options) {
+		o.content, o.hasContent = content, true
+	}
+}
+
+// WithInclusionProof verifies a record signed in a Merkle batch, whose signature covers the
+// tree head the proof leads to rather than the record itself
+func WithInclusionProof(proof *blobv1.InclusionProof) Option {
+	return func(o *options) {
+		o.proof = proof
+	}
+}
+
+// WithKeyID only tries the key with the identifier, e.g. the key_id returned with the record,
+// and fails with an UnknownKeyError when none of the keys has it
+func WithKeyID(keyID string) Option {
+	return func(o *options) {
+		o.keyID = keyID
+	}
+}
+
+// Verify verifies the signature over the record under one of the keys, RSA, P-256 ECDSA or Ed25519
+// public keys as the server signs with. The content hash is checked first, then the inclusion proof
+// and last the signature. Detached records carry no content, their hash is only checked WithContent.
+//
+// Errors are a *HashMismatchError, a *SizeMismatchError, a *SignatureError or an *UnknownKeyError, or wrap
+// merkle.ErrInvalidProof for an inclusion proof not leading to a root.
+func Verify(record *blobv1.BlobRecord, sig []byte, keys []crypto.PublicKey, opts ...Option) (*Result, error) {
+	if record == nil {
+		return nil, errors.New("record cannot be nil")
+	}
+	if len(sig) == 0 {
+		return nil, errors.New("signature cannot be empty")
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch {
+	case o.hasContent:
+		if err := VerifyContent(record, o.content); err != nil {
+			return nil, err
+		}
+	case !record.Detached:
+		if err := VerifyContent(record, []byte(record.Blob)); err != nil {
+			return nil, err
+		}
+	}
+
+	payloadBytes, err := MarshalRecord(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	result := &Result{}
+	signed := payloadBytes
+	if o.proof != nil {
+		root, err := merkle.RootFromInclusionProof(o.proof.LeafIndex, o.proof.TreeSize, merkle.LeafHash(payloadBytes), o.proof.Hashes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify inclusion proof: %w", err)
+		}
+		result.TreeHead = &blobv1.MerkleTreeHead{RootHash: root, TreeSize: o.proof.TreeSize}
+		if signed, err = MarshalTreeHead(result.TreeHead); err != nil {
+			return nil, fmt.Errorf("failed to marshal Merkle tree head: %w", err)
+		}
+	}
+
+	var tried []string
+	var lastErr error
+	for _, key := range keys {
+		keyID := signature.KeyIDForPublicKey(key)
+		if o.keyID != "" && keyID != o.keyID {
+			continue
+		}
+		algorithm, err := signature.AlgorithmForPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		tried = append(tried, keyID)
+		if lastErr = signature.VerifyWithPublicKey(key, signed, sig); lastErr == nil {
+			result.KeyID, result.PublicKey, result.Algorithm = keyID, key, algorithm
+			return result, nil
+		}
+	}
+
+	if len(tried) == 0 {
+		return nil, &UnknownKeyError{KeyID: o.keyID}
+	}
+	return nil, &SignatureError{KeyIDs: tried, Err: lastErr}
+}
+
+// VerifyContent checks the content against the SHA-256 hash in the record and returns
+// a *HashMismatchError when it does not match. The size of a detached record is optional,
+// when it has one the content must be of that size or a *SizeMismatchError is returned.
+func VerifyContent(record *blobv1.BlobRecord, content []byte) error {
+	hash := sha256.Sum256(content)
+	if computed := hex.EncodeToString(hash[:]); computed != record.GetHash() {
+		return &HashMismatchError{Expected: record.GetHash(), Computed: computed}
+	}
+	if record.GetDetached() && record.GetSize() > 0 && int64(len(content)) != record.GetSize() {
+		return &SizeMismatchError{Expected: record.GetSize(), Actual: int64(len(content))}
+	}
+	return nil
+}
+
+// MarshalRecord serialises a record the way the server signs it, map entries sorted by key
+func MarshalRecord(record *blobv1.BlobRecord) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(record)
+}
+
+// MarshalTreeHead serialises the tree head of a Merkle batch the way the server signs it
+func MarshalTreeHead(treeHead *blobv1.MerkleTreeHead) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(treeHead)
+}
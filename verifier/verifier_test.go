@@ -0,0 +1,213 @@
+package verifier
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	blobv1 "github.com/prit342/signed-blob-service/gen/blob/v1"
+	"github.com/prit342/signed-blob-service/merkle"
+	"github.com/prit342/signed-blob-service/signature"
+	"github.com/prit342/signed-blob-service/signature/signaturetest"
+)
+
+// newTestSigner returns a signer for the algorithm and its public key as clients parse it
+func newTestSigner(t *testing.T, algorithm signature.Algorithm) (signature.Signer, crypto.PublicKey) {
+	t.Helper()
+	signer := signaturetest.NewSignerFor(t, algorithm)
+	return signer, signaturetest.PublicKey(t, signer)
+}
+
+func newRecord(content string, detached bool) *blobv1.BlobRecord {
+	hash := sha256.Sum256([]byte(content))
+	record := &blobv1.BlobRecord{
+		Uuid:      "10315b7a-0000-0000-0000-000000000000",
+		Hash:      hex.EncodeToString(hash[:]),
+		Timestamp: "2025-07-28T17:42:05.123456Z",
+		Detached:  detached,
+		Labels:    map[string]string{"team": "payments", "env": "prod"},
+	}
+	if detached {
+		record.Size = int64(len(content))
+	} else {
+		record.Blob = content
+	}
+	return record
+}
+
+func sign(t *testing.T, signer signature.Signer, record *blobv1.BlobRecord) []byte {
+	t.Helper()
+	payload, err := MarshalRecord(record)
+	if err != nil {
+		t.Fatalf("failed to marshal record: %v", err)
+	}
+	sig, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatalf("failed to sign record: %v", err)
+	}
+	return sig
+}
+
+func TestVerifyAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	for _, algorithm := range signaturetest.Algorithms {
+		t.Run(string(algorithm), func(t *testing.T) {
+			t.Parallel()
+			signer, publicKey := newTestSigner(t, algorithm)
+			record := newRecord("hello world", false)
+
+			result, err := Verify(record, sign(t, signer, record), []crypto.PublicKey{publicKey})
+			if err != nil {
+				t.Fatalf("failed to verify: %v", err)
+			}
+			if result.KeyID != signer.KeyID() || result.Algorithm != algorithm || result.TreeHead != nil {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	signer, publicKey := newTestSigner(t, signature.AlgorithmEd25519)
+	otherSigner, otherKey := newTestSigner(t, signature.AlgorithmECDSAP256SHA256)
+
+	record := newRecord("hello world", false)
+	sig := sign(t, signer, record)
+
+	detached := newRecord("hello world", true)
+	detachedSig := sign(t, signer, detached)
+
+	// the size of a detached record is optional, a digest signed without one
+	unsized := newRecord("hello world", true)
+	unsized.Size = 0
+	unsizedSig := sign(t, signer, unsized)
+
+	wrongSize := newRecord("hello world", true)
+	wrongSize.Size = 5
+
+	// a Merkle batch of two records, signed through its tree head like the server does
+	sibling, err := MarshalRecord(newRecord("hello again", false))
+	if err != nil {
+		t.Fatalf("failed to marshal record: %v", err)
+	}
+	payload, err := MarshalRecord(record)
+	if err != nil {
+		t.Fatalf("failed to marshal record: %v", err)
+	}
+	root, proofs, err := merkle.Build([][]byte{merkle.LeafHash(payload), merkle.LeafHash(sibling)})
+	if err != nil {
+		t.Fatalf("failed to build Merkle tree: %v", err)
+	}
+	treeHead, err := MarshalTreeHead(&blobv1.MerkleTreeHead{RootHash: root, TreeSize: 2})
+	if err != nil {
+		t.Fatalf("failed to marshal tree head: %v", err)
+	}
+	batchSig, err := signer.Sign(treeHead)
+	if err != nil {
+		t.Fatalf("failed to sign tree head: %v", err)
+	}
+	proof := &blobv1.InclusionProof{LeafIndex: 0, TreeSize: 2, Hashes: proofs[0]}
+
+	tampered := newRecord("hello world", false)
+	tampered.Labels["env"] = "dev"
+
+	keys := []crypto.PublicKey{otherKey, publicKey}
+
+	tests := []struct {
+		name   string
+		record *blobv1.BlobRecord
+		sig    []byte
+		keys   []crypto.PublicKey
+		opts   []Option
+		check  func(error) bool // nil when the signature is valid
+	}{
+		{name: "record", record: record, sig: sig, keys: keys},
+		{name: "record with key ID", record: record, sig: sig, keys: keys, opts: []Option{WithKeyID(signer.KeyID())}},
+		{name: "detached record", record: detached, sig: detachedSig, keys: keys},
+		{name: "detached record with content", record: detached, sig: detachedSig, keys: keys,
+			opts: []Option{WithContent([]byte("hello world"))}},
+		{name: "detached record without size", record: unsized, sig: unsizedSig, keys: keys,
+			opts: []Option{WithContent([]byte("hello world"))}},
+		{name: "batched record", record: record, sig: batchSig, keys: keys, opts: []Option{WithInclusionProof(proof)}},
+		{name: "tampered content", record: newRecord("hello world", false), sig: sig, keys: keys,
+			opts: []Option{WithContent([]byte("hello there"))}, check: isHashMismatch},
+		{name: "tampered detached content", record: detached, sig: detachedSig, keys: keys,
+			opts: []Option{WithContent([]byte("hello there"))}, check: isHashMismatch},
+		{name: "detached content of another size", record: wrongSize, sig: sign(t, signer, wrongSize), keys: keys,
+			opts: []Option{WithContent([]byte("hello world"))}, check: isSizeMismatch},
+		{name: "tampered label", record: tampered, sig: sig, keys: keys, check: isSignatureError},
+		{name: "signed by another key", record: record, sig: sign(t, otherSigner, record),
+			keys: []crypto.PublicKey{publicKey}, check: isSignatureError},
+		{name: "batched record without its proof", record: record, sig: batchSig, keys: keys, check: isSignatureError},
+		{name: "proof of another leaf", record: record, sig: batchSig, keys: keys,
+			opts:  []Option{WithInclusionProof(&blobv1.InclusionProof{LeafIndex: 1, TreeSize: 2, Hashes: proofs[0]})},
+			check: isSignatureError},
+		{name: "leaf outside the tree", record: record, sig: batchSig, keys: keys,
+			opts:  []Option{WithInclusionProof(&blobv1.InclusionProof{LeafIndex: 2, TreeSize: 2, Hashes: proofs[0]})},
+			check: func(err error) bool { return errors.Is(err, merkle.ErrInvalidProof) }},
+		{name: "unknown key ID", record: record, sig: sig, keys: keys,
+			opts: []Option{WithKeyID("00")}, check: isUnknownKey},
+		{name: "no keys", record: record, sig: sig, check: isUnknownKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := Verify(tt.record, tt.sig, tt.keys, tt.opts...)
+			if tt.check == nil {
+				if err != nil {
+					t.Fatalf("failed to verify: %v", err)
+				}
+				if result.KeyID != signer.KeyID() {
+					t.Fatalf("expected key ID %s but got %s", signer.KeyID(), result.KeyID)
+				}
+				return
+			}
+			if err == nil || !tt.check(err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, publicKey := newTestSigner(t, signature.AlgorithmEd25519)
+	keys := []crypto.PublicKey{publicKey}
+
+	if _, err := Verify(nil, []byte("signature"), keys); err == nil {
+		t.Fatal("expected error for a nil record but got none")
+	}
+	if _, err := Verify(newRecord("hello world", false), nil, keys); err == nil {
+		t.Fatal("expected error for an empty signature but got none")
+	}
+	if _, err := Verify(newRecord("hello world", false), []byte("signature"), []crypto.PublicKey{"not a key"}); err == nil {
+		t.Fatal("expected error for an unsupported key but got none")
+	}
+}
+
+func isHashMismatch(err error) bool {
+	var target *HashMismatchError
+	return errors.As(err, &target)
+}
+
+func isSizeMismatch(err error) bool {
+	var target *SizeMismatchError
+	return errors.As(err, &target)
+}
+
+func isSignatureError(err error) bool {
+	var target *SignatureError
+	return errors.As(err, &target)
+}
+
+func isUnknownKey(err error) bool {
+	var target *UnknownKeyError
+	return errors.As(err, &target)
+}